@@ -0,0 +1,437 @@
+// The backfill command reconverts historical elo logs into baseviews (e.g.
+// after the converter changes), writing them under a versioned output path
+// and comparing each with the previous output. Progress is checkpointed to a
+// local file, so an interrupted backfill can be resumed by running the same
+// command again.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VantageSports/common/files"
+	fileutil "github.com/VantageSports/common/files/util"
+	vsjson "github.com/VantageSports/common/json"
+	"github.com/VantageSports/common/log"
+	"github.com/VantageSports/lolelo/convert"
+	"github.com/VantageSports/lolelo/parse"
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/riot"
+	"github.com/VantageSports/riot/api"
+
+	"cloud.google.com/go/storage"
+	"golang.org/x/net/context"
+)
+
+var (
+	eloDir         = flag.String("elo_dir", "", "directory of elo logs, named <match>-<platform>-data_extract.txt")
+	matchDir       = flag.String("match_dir", "", "directory of match details, named <match>-<region>.json")
+	outputDir      = flag.String("output_dir", "", "baseviews are written to <output_dir>/<version>")
+	version        = flag.String("version", "", "version of the conversion (e.g. v2)")
+	previousDir    = flag.String("previous_dir", "", "optional directory of previous baseviews to diff against")
+	platforms      = flag.String("platforms", "", "optional comma-separated platforms to include (e.g. NA1,EUW1)")
+	patches        = flag.String("patches", "", "optional comma-separated patches to include (e.g. 7.1,7.2)")
+	since          = flag.String("since", "", "optional date (YYYY-MM-DD) of the earliest match to include")
+	until          = flag.String("until", "", "optional date (YYYY-MM-DD) after the latest match to include")
+	matchIDs       = flag.String("match_ids", "", "optional comma-separated match ids to include, or @file with one per line")
+	concurrency    = flag.Int("concurrency", 4, "number of matches to convert at once")
+	maxFiles       = flag.Int("max_files", 1000000, "most files to list in -elo_dir; the backfill fails rather than skip any past it")
+	checkpointPath = flag.String("checkpoint", "backfill.checkpoint.jsonl", "local file to log progress to (and resume from)")
+	reportPath     = flag.String("report", "backfill.report.json", "local file to write the summary report to")
+	googProjectID  = flag.String("goog_project_id", "", "google project id, needed for gs:// paths")
+)
+
+// eloLogName matches the names of the elo logs written by the datagen worker.
+var eloLogName = regexp.MustCompile(`^(\d+)-([A-Za-z0-9]+)-data_extract\.txt(\.gz)?$`)
+
+func main() {
+	flag.Parse()
+	if *eloDir == "" || *matchDir == "" || *outputDir == "" || *version == "" {
+		fmt.Println("Must pass in -elo_dir, -match_dir, -output_dir and -version")
+		os.Exit(1)
+	}
+
+	f, err := newFilter()
+	exitIf(err)
+	fc := mustFilesClient()
+
+	checkpoint, err := loadCheckpoint(*checkpointPath, *version)
+	exitIf(err)
+
+	paths, err := listAll(fc, *eloDir, *maxFiles)
+	exitIf(err)
+
+	b := &backfiller{
+		files:      fc,
+		filter:     f,
+		outputDir:  strings.TrimSuffix(*outputDir, "/") + "/" + *version,
+		checkpoint: checkpoint,
+	}
+	jobs := b.jobs(paths)
+	log.Notice(fmt.Sprintf("backfilling %d of %d elo logs (%d already done)", len(jobs), len(paths), checkpoint.Done()))
+
+	b.run(context.Background(), jobs, *concurrency)
+
+	report := checkpoint.Report()
+	exitIf(vsjson.Write(*reportPath, report, 0664))
+	log.Notice(fmt.Sprintf("converted: %d, changed: %d, new: %d, skipped: %d, failed: %d (report in %s)",
+		report.Converted, report.Changed, report.New, report.Skipped, report.Failed, *reportPath))
+}
+
+// listAll lists the files in dir, failing if there may be more than max of
+// them. files.Client has no paging, and its listings silently stop at
+// files.MaxListResults, so a backfill over too large a directory would
+// otherwise be incomplete.
+func listAll(fc *files.Client, dir string, max int) ([]string, error) {
+	files.MaxListResults = max
+	paths, err := fc.List(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) >= max {
+		return nil, fmt.Errorf("listed the maximum of %d files in %s: raise -max_files, or backfill a narrower -elo_dir", max, dir)
+	}
+	return paths, nil
+}
+
+// job is a single elo log to reconvert.
+type job struct {
+	MatchID  int64
+	Platform riot.Platform
+	EloPath  string
+}
+
+// Key identifies the match in checkpoints and reports.
+func (j job) Key() string {
+	return fmt.Sprintf("%d-%s", j.MatchID, j.Platform)
+}
+
+// parseEloPath returns the job for the elo log at the path, or false if the
+// file isn't an elo log.
+func parseEloPath(path string) (job, bool) {
+	m := eloLogName.FindStringSubmatch(filepath.Base(path))
+	if m == nil {
+		return job{}, false
+	}
+	matchID, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return job{}, false
+	}
+	return job{MatchID: matchID, Platform: riot.PlatformFromString(m[2]), EloPath: path}, true
+}
+
+// filter decides which matches are backfilled. Match ids and platforms are
+// checked before anything is downloaded; dates and patches once the match
+// details have been.
+type filter struct {
+	MatchIDs  map[int64]bool
+	Platforms map[riot.Platform]bool
+	Patches   []string
+	Since     time.Time
+	Until     time.Time
+}
+
+func newFilter() (*filter, error) {
+	f := &filter{}
+	var err error
+
+	if f.MatchIDs, err = parseMatchIDs(*matchIDs); err != nil {
+		return nil, err
+	}
+	if *platforms != "" {
+		f.Platforms = map[riot.Platform]bool{}
+		for _, p := range strings.Split(*platforms, ",") {
+			f.Platforms[riot.PlatformFromString(strings.TrimSpace(p))] = true
+		}
+	}
+	if *patches != "" {
+		for _, p := range strings.Split(*patches, ",") {
+			f.Patches = append(f.Patches, strings.TrimSpace(p))
+		}
+	}
+	if *since != "" {
+		if f.Since, err = time.Parse("2006-01-02", *since); err != nil {
+			return nil, err
+		}
+	}
+	if *until != "" {
+		if f.Until, err = time.Parse("2006-01-02", *until); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// parseMatchIDs parses a comma-separated list of match ids, or reads them
+// (one per line) from the file if the value begins with @. It returns nil if
+// the value is empty.
+func parseMatchIDs(value string) (map[int64]bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+	ids := []string{}
+	if strings.HasPrefix(value, "@") {
+		file, err := os.Open(strings.TrimPrefix(value, "@"))
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			ids = append(ids, scanner.Text())
+		}
+		if err = scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else {
+		ids = strings.Split(value, ",")
+	}
+
+	res := map[int64]bool{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		matchID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match id %q: %v", id, err)
+		}
+		res[matchID] = true
+	}
+	return res, nil
+}
+
+// Includes returns true if the job passes the match id and platform filters.
+func (f *filter) Includes(j job) bool {
+	if f.MatchIDs != nil && !f.MatchIDs[j.MatchID] {
+		return false
+	}
+	return f.Platforms == nil || f.Platforms[j.Platform]
+}
+
+// SkipReason returns the reason the match details fail the date and patch
+// filters, or "" if they pass.
+func (f *filter) SkipReason(md *api.MatchDetail) string {
+	created := time.Unix(md.MatchCreation/1000, 0).UTC()
+	if !f.Since.IsZero() && created.Before(f.Since) {
+		return fmt.Sprintf("created %s, before %s", created.Format(time.RFC3339), f.Since.Format("2006-01-02"))
+	}
+	if !f.Until.IsZero() && !created.Before(f.Until) {
+		return fmt.Sprintf("created %s, after %s", created.Format(time.RFC3339), f.Until.Format("2006-01-02"))
+	}
+	if len(f.Patches) == 0 {
+		return ""
+	}
+	for _, patch := range f.Patches {
+		if md.MatchVersion == patch || strings.HasPrefix(md.MatchVersion, patch+".") {
+			return ""
+		}
+	}
+	return fmt.Sprintf("version %s not in %v", md.MatchVersion, f.Patches)
+}
+
+// backfiller reconverts elo logs.
+type backfiller struct {
+	files      *files.Client
+	filter     *filter
+	outputDir  string
+	checkpoint *checkpoint
+}
+
+// jobs returns the jobs for the elo logs at the paths that pass the filter
+// and haven't already been done.
+func (b *backfiller) jobs(paths []string) []job {
+	jobs := []job{}
+	for _, path := range paths {
+		j, ok := parseEloPath(path)
+		if !ok || !b.filter.Includes(j) || b.checkpoint.IsDone(j.Key()) {
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs
+}
+
+// run converts the jobs, at most concurrency at a time, recording the result
+// of each in the checkpoint.
+func (b *backfiller) run(ctx context.Context, jobs []job, concurrency int) {
+	queue := make(chan job)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				start := time.Now()
+				entry := b.process(ctx, j)
+				entry.Seconds = time.Since(start).Seconds()
+				if entry.Status == statusFailed {
+					log.Error(fmt.Sprintf("%s failed: %s", j.Key(), entry.Error))
+				}
+				if err := b.checkpoint.Record(j.Key(), entry); err != nil {
+					log.Error(fmt.Sprintf("unable to save checkpoint: %v", err))
+				}
+			}
+		}()
+	}
+	for _, j := range jobs {
+		queue <- j
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// process converts a single elo log, writes the baseview (and its reports)
+// to the output directory and diffs it with the previous baseview.
+func (b *backfiller) process(ctx context.Context, j job) *entry {
+	workDir, err := ioutil.TempDir("", "lol_backfill_tmp")
+	if err != nil {
+		return failed(err)
+	}
+	defer os.RemoveAll(workDir)
+
+	region := riot.RegionFromPlatform(j.Platform)
+	mdPath := fmt.Sprintf("%s/%d-%s.json", strings.TrimSuffix(*matchDir, "/"), j.MatchID, region)
+	mdLocal, err := downloadTo(b.files, mdPath, workDir)
+	if err != nil {
+		return failed(fmt.Errorf("error downloading match details: %v", err))
+	}
+	md := &api.MatchDetail{}
+	if err = decodeFile(mdLocal, md); err != nil {
+		return failed(err)
+	}
+	if reason := b.filter.SkipReason(md); reason != "" {
+		return &entry{Status: statusSkipped, Error: reason}
+	}
+
+	eloLocal, err := downloadTo(b.files, j.EloPath, workDir)
+	if err != nil {
+		return failed(fmt.Errorf("error downloading elo log: %v", err))
+	}
+	res, err := convert.File(ctx, eloLocal, md)
+	if err != nil {
+		return failed(err)
+	}
+
+	name := fmt.Sprintf("%s.baseview.json", j.Key())
+	outputs := map[string]interface{}{
+		name: res.Baseview,
+		fmt.Sprintf("%s.parse_report.json", j.Key()): res.ParseReport,
+		fmt.Sprintf("%s.quality.json", j.Key()):      res.Quality,
+		fmt.Sprintf("%s.conversion.json", j.Key()):   res.Stats,
+		fmt.Sprintf("%s.lint.json", j.Key()):         res.Lint,
+	}
+	for localName, v := range outputs {
+		if err = uploadJSON(b.files, v, filepath.Join(workDir, localName), b.outputDir); err != nil {
+			return failed(err)
+		}
+	}
+
+	e := &entry{
+		Status:     statusConverted,
+		Quality:    res.Quality.Score,
+		Unresolved: res.Stats.TotalUnresolved(),
+		LintErrors: res.Lint.Errors,
+	}
+	if *previousDir != "" {
+		previous, err := b.previous(strings.TrimSuffix(*previousDir, "/")+"/"+name, workDir)
+		if err != nil {
+			return failed(fmt.Errorf("error reading previous baseview: %v", err))
+		}
+		e.Diff = diffBaseviews(previous, res.Baseview)
+	}
+	return e
+}
+
+// previous returns the baseview at the remote path, or nil if there isn't one.
+func (b *backfiller) previous(remotePath, workDir string) (*baseview.Baseview, error) {
+	exists, err := b.files.Exists(remotePath)
+	if err != nil || !exists[0] {
+		return nil, err
+	}
+	local, err := downloadTo(b.files, remotePath, filepath.Join(workDir, "previous"))
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(local)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, err := parse.Decompress(f)
+	if err != nil {
+		return nil, err
+	}
+	return baseview.Read(r)
+}
+
+// decodeFile decodes the (possibly gzip-compressed) json file into v. Files
+// copied from a local directory keep the compression they were uploaded with.
+func decodeFile(filename string, v interface{}) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r, err := parse.Decompress(f)
+	if err != nil {
+		return err
+	}
+	return json.NewDecoder(r).Decode(v)
+}
+
+func failed(err error) *entry {
+	return &entry{Status: statusFailed, Error: err.Error()}
+}
+
+func downloadTo(fc *files.Client, remotePath, localDir string) (string, error) {
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return "", err
+	}
+	localPath := filepath.Join(localDir, filepath.Base(remotePath))
+	log.Debug(fmt.Sprintf("downloading %s to %s", remotePath, localPath))
+	return localPath, fc.Copy(remotePath, localPath)
+}
+
+// uploadJSON writes v as gzipped json to localPath, then copies it to the
+// remote directory.
+func uploadJSON(fc *files.Client, v interface{}, localPath, remoteDir string) error {
+	if err := vsjson.Write(localPath, v, 0664); err != nil {
+		return err
+	}
+	compressedPath := localPath + ".zip"
+	if err := fileutil.GzipFile(localPath, compressedPath, 0664); err != nil {
+		return err
+	}
+	remotePath := strings.TrimSuffix(remoteDir, "/") + "/" + filepath.Base(localPath)
+	return fc.Copy(compressedPath, remotePath,
+		files.ContentType("application/json"), files.ContentEncoding("gzip"))
+}
+
+func mustFilesClient() *files.Client {
+	options := []files.Option{}
+	if *googProjectID != "" {
+		options = append(options, files.AutoRegisterGCS(*googProjectID, storage.ScopeReadWrite))
+	}
+	client, err := files.InitClient(options...)
+	exitIf(err)
+	return client
+}
+
+func exitIf(err error) {
+	if err != nil {
+		log.Fatal(err)
+	}
+}
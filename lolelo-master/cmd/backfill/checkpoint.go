@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// statuses of a match in a backfill.
+const (
+	statusConverted = "converted"
+	statusSkipped   = "skipped"
+	statusFailed    = "failed"
+)
+
+// checkpoint records the outcome of every match backfilled so far. Each
+// outcome is appended to a log as it is recorded, so that a backfill can be
+// resumed without rewriting the whole checkpoint after every match. Failed
+// matches are retried when resuming.
+type checkpoint struct {
+	Version string
+	Matches map[string]*entry
+
+	path string
+	mu   sync.Mutex
+}
+
+// record is a line of the checkpoint log. A later record of a match replaces
+// an earlier one.
+type record struct {
+	Version string `json:"version"`
+	Key     string `json:"key"`
+	Entry   *entry `json:"entry"`
+}
+
+// entry is the outcome of backfilling a single match.
+type entry struct {
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	Seconds    float64 `json:"seconds"`
+	Quality    float64 `json:"quality,omitempty"`
+	Unresolved int     `json:"unresolved,omitempty"`
+	LintErrors int     `json:"lint_errors,omitempty"`
+	Diff       *diff   `json:"diff,omitempty"`
+}
+
+// loadCheckpoint returns the checkpoint logged at the local path, or a new one
+// if there is none. It is an error to resume a backfill of another version.
+func loadCheckpoint(path, version string) (*checkpoint, error) {
+	c := &checkpoint{Version: version, Matches: map[string]*entry{}, path: path}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	for {
+		r := record{}
+		err := dec.Decode(&r)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// the last record may have been cut short by an interrupted
+			// backfill, in which case the match is simply done again.
+			return c, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid checkpoint %s: %v", path, err)
+		}
+		if r.Version != version {
+			return nil, fmt.Errorf("checkpoint %s is for version %s, not %s", path, r.Version, version)
+		}
+		c.Matches[r.Key] = r.Entry
+	}
+}
+
+// IsDone returns true if the match was converted or skipped.
+func (c *checkpoint) IsDone(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.Matches[key]
+	return e != nil && e.Status != statusFailed
+}
+
+// Done returns the number of matches that were converted or skipped.
+func (c *checkpoint) Done() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	done := 0
+	for _, e := range c.Matches {
+		if e.Status != statusFailed {
+			done++
+		}
+	}
+	return done
+}
+
+// Record records the outcome of the match, appending it to the log.
+func (c *checkpoint) Record(key string, e *entry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Matches[key] = e
+
+	line, err := json.Marshal(record{Version: c.Version, Key: key, Entry: e})
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(c.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0664)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// report summarizes a backfill.
+type report struct {
+	Version   string `json:"version"`
+	Converted int    `json:"converted"`
+	Skipped   int    `json:"skipped"`
+	Failed    int    `json:"failed"`
+
+	// Of the converted matches, those that differ from their previous
+	// baseview, those that had none, and those that failed to lint.
+	Changed    int `json:"changed"`
+	New        int `json:"new"`
+	LintFailed int `json:"lint_failed"`
+
+	// EventCountChanges is the total change in the number of events of each
+	// type, over all changed matches.
+	EventCountChanges map[string]int `json:"event_count_changes"`
+
+	// The keys of the changed and failed matches, with their errors.
+	ChangedMatches []string          `json:"changed_matches"`
+	Failures       map[string]string `json:"failures"`
+}
+
+// Report returns the summary of every match in the checkpoint.
+func (c *checkpoint) Report() *report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r := &report{
+		Version:           c.Version,
+		EventCountChanges: map[string]int{},
+		ChangedMatches:    []string{},
+		Failures:          map[string]string{},
+	}
+	for key, e := range c.Matches {
+		switch e.Status {
+		case statusSkipped:
+			r.Skipped++
+		case statusFailed:
+			r.Failed++
+			r.Failures[key] = e.Error
+		case statusConverted:
+			r.Converted++
+			if e.LintErrors > 0 {
+				r.LintFailed++
+			}
+			if e.Diff == nil {
+				continue
+			}
+			if !e.Diff.Previous {
+				r.New++
+			} else if !e.Diff.Identical {
+				r.Changed++
+				r.ChangedMatches = append(r.ChangedMatches, key)
+				for eventType, counts := range e.Diff.EventCounts {
+					r.EventCountChanges[eventType] += counts[1] - counts[0]
+				}
+			}
+		}
+	}
+	sort.Strings(r.ChangedMatches)
+	return r
+}
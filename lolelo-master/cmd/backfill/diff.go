@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// diff describes how a reconverted baseview differs from the previous one.
+type diff struct {
+	// Previous is false if there was no previous baseview.
+	Previous  bool `json:"previous"`
+	Identical bool `json:"identical"`
+
+	ParticipantsChanged bool `json:"participants_changed,omitempty"`
+
+	// EventCounts are the previous and current number of events of each type
+	// whose count changed.
+	EventCounts map[string][2]int `json:"event_counts,omitempty"`
+
+	// EventsChanged is true if the events differ, and FirstDifference is
+	// then the time (in match seconds) of the first event that differs.
+	EventsChanged   bool    `json:"events_changed"`
+	FirstDifference float64 `json:"first_difference,omitempty"`
+}
+
+// diffBaseviews compares the previous baseview (which may be nil) with the
+// current one. The time each was last updated is ignored.
+func diffBaseviews(previous, current *baseview.Baseview) *diff {
+	d := &diff{}
+	if previous == nil {
+		return d
+	}
+	d.Previous = true
+	d.ParticipantsChanged = !reflect.DeepEqual(previous.Participants, current.Participants)
+
+	counts := map[string][2]int{}
+	for _, e := range previous.Events {
+		c := counts[e.Type()]
+		c[0]++
+		counts[e.Type()] = c
+	}
+	for _, e := range current.Events {
+		c := counts[e.Type()]
+		c[1]++
+		counts[e.Type()] = c
+	}
+	d.EventCounts = map[string][2]int{}
+	for eventType, c := range counts {
+		if c[0] != c[1] {
+			d.EventCounts[eventType] = c
+		}
+	}
+
+	for i := 0; i < len(previous.Events) || i < len(current.Events); i++ {
+		if i >= len(previous.Events) {
+			d.EventsChanged, d.FirstDifference = true, current.Events[i].Seconds()
+			break
+		}
+		if i >= len(current.Events) {
+			d.EventsChanged, d.FirstDifference = true, previous.Events[i].Seconds()
+			break
+		}
+		if !sameEvent(previous.Events[i], current.Events[i]) {
+			d.EventsChanged, d.FirstDifference = true, current.Events[i].Seconds()
+			break
+		}
+	}
+
+	d.Identical = !d.ParticipantsChanged && !d.EventsChanged
+	return d
+}
+
+// sameEvent returns true if the events have the same json encoding.
+func sameEvent(a, b baseview.Event) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aJSON, bJSON)
+}
@@ -17,9 +17,7 @@ import (
 	vsjson "github.com/VantageSports/common/json"
 	"github.com/VantageSports/common/log"
 	"github.com/VantageSports/common/queue/messages"
-	"github.com/VantageSports/lolelo/event"
-	"github.com/VantageSports/lolelo/parse"
-	"github.com/VantageSports/lolelo/validate"
+	"github.com/VantageSports/lolelo/convert"
 	"github.com/VantageSports/lolstats/baseview"
 	"github.com/VantageSports/riot"
 	"github.com/VantageSports/riot/api"
@@ -70,8 +68,8 @@ func (e *LolOCRExtractor) Handle(ctx context.Context, m *pubsub.Message) error {
 			if err != nil {
 				return err
 			}
-			bv := &baseview.Baseview{}
-			if err = vsjson.DecodeFile(baseviewLocal, bv); err != nil {
+			bv, err := baseview.ReadFile(baseviewLocal)
+			if err != nil {
 				return err
 			}
 
@@ -79,8 +77,9 @@ func (e *LolOCRExtractor) Handle(ctx context.Context, m *pubsub.Message) error {
 		}
 	}
 
-	// Download and parse elo data
-	eloEvents, err := downloadEloEvents(e.Files, msg.EloDataPath, workDir)
+	// Download elo data. It is parsed as a stream (twice) below rather than
+	// loaded into memory all at once.
+	eloLocal, err := downloadTo(e.Files, msg.EloDataPath, workDir)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -93,11 +92,15 @@ func (e *LolOCRExtractor) Handle(ctx context.Context, m *pubsub.Message) error {
 		return err
 	}
 
-	baseview, err := processEloText(ctx, e.Files, eloEvents, matchDetail, remoteBaseviewPath, workDir)
+	baseview, err := processEloText(ctx, e.Files, eloLocal, matchDetail, remoteBaseviewPath, workDir)
 	if err != nil {
 		log.Error(err)
 		return err
 	}
+	if baseview == nil {
+		// Rejected by lint, which would reject it again on redelivery.
+		return nil
+	}
 
 	// Make sure our task lease hasn't expired.
 	if ctx.Err() != nil {
@@ -108,21 +111,6 @@ func (e *LolOCRExtractor) Handle(ctx context.Context, m *pubsub.Message) error {
 	return publishMessage(ctx, e.PubTopic, baseview, remoteBaseviewPath, &msg)
 }
 
-func downloadEloEvents(fc *files.Client, eloDataPath, workDir string) ([]event.EloEvent, error) {
-	// Download the raw events
-	rawTextLocal, err := downloadTo(fc, eloDataPath, workDir)
-	if err != nil {
-		return nil, err
-	}
-
-	// Generate events from the elo text format.
-	events, err := parse.LogFile(rawTextLocal)
-	if err != nil {
-		return nil, err
-	}
-	return events, nil
-}
-
 func downloadMatchDetails(fc *files.Client, matchDetailsPath, workDir string) (*api.MatchDetail, error) {
 	// Download and parse the match details.
 	matchDetailsLocal, err := downloadTo(fc, matchDetailsPath, workDir)
@@ -136,32 +124,26 @@ func downloadMatchDetails(fc *files.Client, matchDetailsPath, workDir string) (*
 	return matchDetail, nil
 }
 
-func processEloText(ctx context.Context, fc *files.Client, events []event.EloEvent, matchDetail *api.MatchDetail, remoteBaseviewPath, workDir string) (*baseview.Baseview, error) {
-	// Align the raw events with the match detail events, and check to verify
-	// that the data file is not truncated.
-	var err error
-	if err = validate.AlignAPI(events, matchDetail); err != nil {
+// processEloText converts the elo data and uploads the baseview (and the
+// reports of the conversion) to remoteBaseviewPath. It returns a nil baseview
+// if lint rejected it.
+func processEloText(ctx context.Context, fc *files.Client, eloLocal string, matchDetail *api.MatchDetail, remoteBaseviewPath, workDir string) (*baseview.Baseview, error) {
+	res, err := convert.File(ctx, eloLocal, matchDetail)
+	if err != nil {
 		return nil, err
 	}
-	if err = validate.CheckTruncation(events, matchDetail.MatchDuration); err != nil {
+	if err = uploadReports(fc, res, remoteBaseviewPath); err != nil {
 		return nil, err
 	}
-
-	// Use the match details to build a participant list. (NOTE: we assume that
-	// the match details have been augmented with other sources of data by the
-	// match downloader and ALWAYS contain participant info.)
-	participants, err := validate.ParticipantMapMD(events, matchDetail)
-	if err != nil {
-		return nil, fmt.Errorf("unable to determine participants from match: %v", err)
-	}
-
-	baseview, err := event.EloToBaseview(events, participants)
-	if err != nil {
-		return nil, fmt.Errorf("error extracting baseview: %v", err)
+	// Don't store baseviews that would produce wrong stats. The rejection is
+	// recorded by the lint report uploaded above.
+	if err = res.Lint.Err(); err != nil {
+		log.Error(fmt.Sprintf("rejecting baseview %s: %v", remoteBaseviewPath, err))
+		return nil, nil
 	}
 
 	outputDir, localBaseview := filepath.Split(remoteBaseviewPath)
-	if err = vsjson.Write(localBaseview, baseview, 0664); err != nil {
+	if err = vsjson.Write(localBaseview, res.Baseview, 0664); err != nil {
 		return nil, err
 	}
 	defer os.Remove(localBaseview)
@@ -175,7 +157,60 @@ func processEloText(ctx context.Context, fc *files.Client, events []event.EloEve
 	}
 
 	// return the context error (which will be nil if the task lease has not expired)
-	return baseview, ctx.Err()
+	return res.Baseview, ctx.Err()
+}
+
+// uploadReports stores the reports of a conversion alongside the baseview.
+func uploadReports(fc *files.Client, res *convert.Result, remoteBaseviewPath string) error {
+	// The parse report, so that changes to the elo log format show up without
+	// failing the conversion.
+	report := res.ParseReport
+	if skipped := report.Skipped(); skipped > 0 {
+		log.Warning(fmt.Sprintf("skipped %d of %d elo lines for %s (unknown types: %v)",
+			skipped, report.LinesRead, remoteBaseviewPath, report.UnknownTypeNames()))
+	}
+	if err := uploadBesideBaseview(fc, report, remoteBaseviewPath, "parse_report"); err != nil {
+		return err
+	}
+
+	// Likewise the quality score, so that stats consumers can filter out (or
+	// flag) games with unreliable data.
+	quality := res.Quality
+	log.Info(fmt.Sprintf("elo quality score for %s: %.1f (%d findings)", remoteBaseviewPath, quality.Score, len(quality.Findings)))
+	if err := uploadBesideBaseview(fc, quality, remoteBaseviewPath, "quality"); err != nil {
+		return err
+	}
+
+	// And the counts of events that referenced network ids we couldn't resolve.
+	stats := res.Stats
+	if unresolved := stats.TotalUnresolved(); unresolved > 0 {
+		log.Warning(fmt.Sprintf("dropped %d of %d elo events with unresolved network ids for %s (%d deferred, %d late mappings)",
+			unresolved, stats.Events, remoteBaseviewPath, stats.Deferred, stats.LateMappings))
+	}
+	if err := uploadBesideBaseview(fc, stats, remoteBaseviewPath, "conversion"); err != nil {
+		return err
+	}
+
+	// And the invariants the baseview breaks, if any.
+	lint := res.Lint
+	if lint.Errors > 0 || lint.Warnings > 0 {
+		log.Warning(fmt.Sprintf("baseview %s has %d lint errors and %d warnings", remoteBaseviewPath, lint.Errors, lint.Warnings))
+	}
+	return uploadBesideBaseview(fc, lint, remoteBaseviewPath, "lint")
+}
+
+// uploadBesideBaseview writes v as json next to the baseview at
+// remoteBaseviewPath, as <match>-<platform>.<name>.json.
+func uploadBesideBaseview(fc *files.Client, v interface{}, remoteBaseviewPath, name string) error {
+	remotePath := strings.TrimSuffix(remoteBaseviewPath, ".baseview.json") + "." + name + ".json"
+	outputDir, localPath := filepath.Split(remotePath)
+	if err := vsjson.Write(localPath, v, 0664); err != nil {
+		return err
+	}
+	defer os.Remove(localPath)
+
+	_, err := uploadCompressedJSON(fc, localPath, outputDir)
+	return err
 }
 
 func downloadTo(fc *files.Client, remotePath, localDir string) (string, error) {
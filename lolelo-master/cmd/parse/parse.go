@@ -44,8 +44,11 @@ func main() {
 		}
 	} else {
 		log.Println("writing baseview")
-		output, err = event.EloToBaseview(events, participants)
+		bv, stats, err := event.EloToBaseview(events, participants)
 		exitIf(err)
+		log.Printf("converted %d elo events: %d late mappings, %d unresolved %v",
+			stats.Events, stats.LateMappings, stats.TotalUnresolved(), stats.UnresolvedIDs)
+		output = bv
 	}
 
 	exitIf(json.Write(*out, output, 0600))
@@ -0,0 +1,102 @@
+// Package convert converts elo logs into baseviews, along with the reports
+// that describe how well each conversion went.
+
+package convert
+
+import (
+	"fmt"
+
+	"github.com/VantageSports/lolelo/event"
+	"github.com/VantageSports/lolelo/parse"
+	"github.com/VantageSports/lolelo/validate"
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/lolstats/baseview/lint"
+	"github.com/VantageSports/riot/api"
+
+	"golang.org/x/net/context"
+)
+
+// Version identifies the converter in the baseviews it produces. It is set
+// at build time, e.g.
+//
+//	go build -ldflags "-X github.com/VantageSports/lolelo/convert.Version=$(git rev-parse --short HEAD)"
+var Version = "dev"
+
+// Result is a converted elo log.
+type Result struct {
+	Baseview *baseview.Baseview
+
+	// ParseReport describes the lines of the log that were skipped, Quality
+	// scores how trustworthy the log is, Stats describes the network ids that
+	// couldn't be resolved, and Lint lists the invariants the baseview breaks.
+	ParseReport *parse.Report
+	Quality     *validate.Quality
+	Stats       *event.ConversionStats
+	Lint        *lint.Report
+}
+
+// File converts the (possibly gzip-compressed) elo log at the local path into
+// a baseview, aligned with the match details. The log is streamed twice rather
+// than loaded into memory all at once.
+func File(ctx context.Context, eloLocal string, matchDetail *api.MatchDetail) (*Result, error) {
+	// Align the raw events with the match detail events, and check to verify
+	// that the data file is not truncated. Score the quality of the log in the
+	// same pass.
+	quality := validate.NewQualityChecker(matchDetail)
+	summary, err := summarize(ctx, eloLocal, matchDetail, quality)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := summary.APIOffset(matchDetail)
+	if err != nil {
+		return nil, err
+	}
+	if err = summary.CheckTruncation(matchDetail.MatchDuration); err != nil {
+		return nil, err
+	}
+
+	// Use the match details to build a participant list. (NOTE: we assume that
+	// the match details have been augmented with other sources of data by the
+	// match downloader and ALWAYS contain participant info.)
+	participants, err := validate.ParticipantMapMD(nil, matchDetail)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine participants from match: %v", err)
+	}
+
+	eloReader, err := parse.OpenEventReader(ctx, eloLocal)
+	if err != nil {
+		return nil, err
+	}
+	defer eloReader.Close()
+	eloReader.Lenient = true
+
+	bv, stats, err := event.StreamToBaseview(event.WithMatchOffset(eloReader, offset), participants)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting baseview: %v", err)
+	}
+	bv.Producer.ConverterVersion = Version
+	bv.Producer.GamePatch = matchDetail.MatchVersion
+	bv.MapID = int64(matchDetail.MapID)
+
+	return &Result{
+		Baseview:    bv,
+		ParseReport: eloReader.Report(),
+		Quality:     quality.Result(),
+		Stats:       stats,
+		Lint:        lint.Lint(bv),
+	}, nil
+}
+
+// summarize makes a first pass over the elo log to collect what's needed to
+// validate it against (and align it with) the match details. Every event is
+// also added to the quality checker.
+func summarize(ctx context.Context, eloLocal string, matchDetail *api.MatchDetail, quality *validate.QualityChecker) (*validate.Summary, error) {
+	r, err := parse.OpenEventReader(ctx, eloLocal)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	r.Lenient = true
+
+	return validate.Summarize(quality.Observe(r), matchDetail)
+}
@@ -75,6 +75,12 @@ type GameEnd struct {
 	baseEvent `mapstructure:",squash"`
 }
 
+// GameStall is logged when DataSpectator gives up on a game that appears to
+// have stopped (no attacks or no champion movement for a long time).
+type GameStall struct {
+	baseEvent `mapstructure:",squash"`
+}
+
 type NetworkIDMapping struct {
 	baseEvent  `mapstructure:",squash"`
 	SenderName string `json:"name" mapstructure:"name"`
@@ -148,6 +154,11 @@ type Ping struct {
 	UnderEnemyTurret     bool              `json:"under_enemy_turret" mapstructure:"under_enemy_turret"`
 }
 
+type SurrenderAgreed struct {
+	baseEvent `mapstructure:",squash"`
+	NetworkID int64 `json:"network_id" mapstructure:"network_id"`
+}
+
 type SpellCast struct {
 	baseEvent       `mapstructure:",squash"`
 	PositionStart   baseview.Position `json:"start_position" mapstructure:"start_position"`
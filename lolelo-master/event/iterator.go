@@ -0,0 +1,53 @@
+package event
+
+import (
+	"io"
+	"math"
+)
+
+// Iterator yields elo events one at a time, in log order. Next returns io.EOF
+// once there are no more events. Iterators let callers process a match
+// without holding every event in memory at once.
+type Iterator interface {
+	Next() (EloEvent, error)
+}
+
+// NewSliceIterator returns an Iterator over an in-memory slice of events.
+func NewSliceIterator(events []EloEvent) Iterator {
+	return &sliceIterator{events: events}
+}
+
+type sliceIterator struct {
+	events []EloEvent
+	index  int
+}
+
+func (s *sliceIterator) Next() (EloEvent, error) {
+	if s.index >= len(s.events) {
+		return nil, io.EOF
+	}
+	e := s.events[s.index]
+	s.index++
+	return e, nil
+}
+
+// WithMatchOffset returns an Iterator that sets the match seconds of every
+// event it yields to the event's elo time plus the offset (but never less than
+// zero). It is the streaming equivalent of validate.UpdateMatchSeconds.
+func WithMatchOffset(it Iterator, offset float64) Iterator {
+	return &offsetIterator{inner: it, offset: offset}
+}
+
+type offsetIterator struct {
+	inner  Iterator
+	offset float64
+}
+
+func (o *offsetIterator) Next() (EloEvent, error) {
+	e, err := o.inner.Next()
+	if err != nil {
+		return nil, err
+	}
+	e.SetMatchSeconds(math.Max(0, e.Time()+o.offset))
+	return e, nil
+}
@@ -2,6 +2,7 @@ package event
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/VantageSports/common/log"
@@ -18,62 +19,124 @@ type eloEntity struct {
 	ID int64
 }
 
+// lateMappingSeconds is how long after a reference to a network id that its
+// mapping may be logged (or before it that the entity may have died) and still
+// apply to the reference. The order in which DataSpectator logs events that
+// happen at about the same time varies.
+const lateMappingSeconds = 1.0
+
+// unresolvedError is returned by networkIDMap.GetAt when a network id can't be
+// resolved to an entity at a particular time.
+type unresolvedError struct {
+	NetworkID int64
+	Reason    string
+}
+
+func (u *unresolvedError) Error() string {
+	return fmt.Sprintf("%s: %d", u.Reason, u.NetworkID)
+}
+
+// scopedEntity is an entity that a network id refers to from one (elo) time
+// until another. Entities from id mappings are scoped to the whole game.
+type scopedEntity struct {
+	*eloEntity
+	from, until float64
+}
+
+func (s *scopedEntity) contains(t float64) bool {
+	return s.from <= t && t <= s.until+lateMappingSeconds
+}
+
 // netIDMap maps from network id to eloEntity. eloEntity is a crude abstraction
 // of various League entities (heroes, turrets, minions, etc.). Most entities
 // will just have a name, but heroes also have a participant id, which we use
 // to match up with objects from the riot API.
+//
+// Objects such as wards, minions and pets reuse network ids, so each network
+// id maps to a list of entities, each between its creation and its death or
+// deletion.
 type networkIDMap struct {
-	nameToPID      map[string]int64
-	nIDToEloEntity map[int64]*eloEntity
+	nameToPID map[string]int64
+	scopes    map[int64][]*scopedEntity
+
+	// lateMappings counts references that were resolved using a mapping logged
+	// after the reference.
+	lateMappings int
 }
 
 func NewNetworkIDMap() *networkIDMap {
 	return &networkIDMap{
-		nameToPID:      map[string]int64{},
-		nIDToEloEntity: map[int64]*eloEntity{},
+		nameToPID: map[string]int64{},
+		scopes:    map[int64][]*scopedEntity{},
 	}
 }
 
-// Get returns the eloEntity if it has been added to the map, or an empty
-// eloEntity otherwise.
-func (n *networkIDMap) Get(nID int64) (*eloEntity, error) {
+// GetAt returns the eloEntity that the network id refers to at elo time t. If
+// the id wasn't mapped at t, but was mapped shortly after t, that mapping is
+// used.
+func (n *networkIDMap) GetAt(nID int64, t float64) (*eloEntity, error) {
 	// If there was no network id (e.g. a null target on a spell cast) we just
 	// return an 'empty' eloevent.
 	if nID == 0 {
 		return &eloEntity{ID: 0, Name: "", Type: baseview.ActorType("")}, nil
 	}
 
-	entity := n.nIDToEloEntity[nID]
-	if entity == nil {
-		return nil, fmt.Errorf("id not found: %d", nID)
+	scope := n.scopeAt(nID, t)
+	if scope == nil {
+		return nil, &unresolvedError{NetworkID: nID, Reason: "id not found"}
 	}
+	entity := scope.eloEntity
 
 	// If this network id represents a hero, but we haven't yet figured out that
 	// hero's participant id, do that now.
 	if entity.Type == baseview.ActorHero && entity.ID == 0 {
 		entity.ID = n.nameToPID[entity.Name]
 		if entity.ID == 0 {
-			return nil, fmt.Errorf("unknown participant id for hero: %s", entity.Name)
+			return nil, &unresolvedError{NetworkID: nID, Reason: "unknown participant id for hero " + entity.Name}
 		}
-		n.nIDToEloEntity[nID] = entity
 	}
 
 	return entity, nil
 }
 
+// scopeAt returns the latest-created entity whose scope contains t, or else
+// the first entity created within lateMappingSeconds after t.
+func (n *networkIDMap) scopeAt(nID int64, t float64) *scopedEntity {
+	scopes := n.scopes[nID]
+	for i := len(scopes) - 1; i >= 0; i-- {
+		if scopes[i].contains(t) {
+			return scopes[i]
+		}
+	}
+	for _, s := range scopes {
+		if s.from > t && s.from-t <= lateMappingSeconds {
+			n.lateMappings++
+			return s
+		}
+	}
+	return nil
+}
+
+// latest returns the most recently created entity for the network id, or nil.
+func (n *networkIDMap) latest(nID int64) *scopedEntity {
+	scopes := n.scopes[nID]
+	if len(scopes) == 0 {
+		return nil
+	}
+	return scopes[len(scopes)-1]
+}
+
 func (n *networkIDMap) AddParticipant(pID int64, name string) {
 	n.nameToPID[name] = pID
 }
 
 // AddEvent is how the netIDMap builds the mappings from network id to entity.
 // Certain events contain both the name and network id, and when we see those
-// events we add them to the map.
+// events we add them to the map. Others end the scope of an entity.
 func (n *networkIDMap) AddEvent(e EloEvent) {
-	var entity *eloEntity
-
 	switch t := e.(type) {
 	case *NetworkIDMapping:
-		entity = &eloEntity{Name: t.SenderName}
+		entity := &eloEntity{Name: t.SenderName}
 		if t.EloType == "ID_HERO" {
 			// We add participant ids to heros at Get time (after we have a name
 			// to participant id mapping)
@@ -81,18 +144,47 @@ func (n *networkIDMap) AddEvent(e EloEvent) {
 		} else {
 			entity.Type, entity.ID = lookupBaseviewID(t.SenderName, t.NetworkID)
 		}
-		n.nIDToEloEntity[t.NetworkID] = entity
+		// heroes, turrets and barracks exist for the whole game.
+		n.scopes[t.NetworkID] = []*scopedEntity{{eloEntity: entity, from: math.Inf(-1), until: math.Inf(1)}}
 
 	case *OnCreate:
-		if _, found := n.nIDToEloEntity[t.NetworkID]; !found {
-			a, id := lookupBaseviewID(t.SenderName, t.NetworkID)
-			n.nIDToEloEntity[t.NetworkID] = &eloEntity{Name: t.SenderName, Type: a, ID: id}
+		latest := n.latest(t.NetworkID)
+		if latest != nil && latest.until == math.Inf(1) {
+			if latest.from == math.Inf(-1) || latest.Name == t.SenderName {
+				// already mapped.
+				return
+			}
+			// the id was reused without us noticing the previous entity end.
+			latest.until = t.Time()
 		}
+		a, id := lookupBaseviewID(t.SenderName, t.NetworkID)
+		n.scopes[t.NetworkID] = append(n.scopes[t.NetworkID], &scopedEntity{
+			eloEntity: &eloEntity{Name: t.SenderName, Type: a, ID: id},
+			from:      t.Time(),
+			until:     math.Inf(1),
+		})
 
 	case *OnDelete:
-		// TODO: (Cameron) any value (other than memory pressure) to deleting
-		// items from this map? E.g. useful to know WHICH minions were alive
-		// at a given time?
+		n.end(t.NetworkID, t.Time())
+
+	case *Die:
+		// turrets, inhibitors and the like may be mapped by OnCreate, but only
+		// these entities never come back with the same network id.
+		if latest := n.latest(t.NetworkID); latest != nil {
+			switch latest.Type {
+			case baseview.ActorMinion, baseview.ActorWard, baseview.ActorMonster:
+				n.end(t.NetworkID, t.Time())
+			}
+		}
+	}
+}
+
+// end ends the scope of the entity most recently created with the network id
+// (unless it exists for the whole game).
+func (n *networkIDMap) end(nID int64, t float64) {
+	latest := n.latest(nID)
+	if latest != nil && latest.from != math.Inf(-1) && latest.until == math.Inf(1) {
+		latest.until = t
 	}
 }
 
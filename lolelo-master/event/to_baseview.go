@@ -5,6 +5,7 @@ package event
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"sort"
 	"strings"
@@ -14,196 +15,560 @@ import (
 	"github.com/VantageSports/lolstats/baseview"
 )
 
-func EloToBaseview(events []EloEvent, participants []baseview.Participant) (*baseview.Baseview, error) {
-	res := &baseview.Baseview{
-		Participants: participants,
-		Events:       []baseview.Event{},
-		LastUpdated:  time.Now(),
+// streamLookaheadSeconds is how far (in elo seconds) past an event the
+// streaming converter reads before converting it. Some conversions look ahead
+// for the next ping of a participant (pings occur every half second) or for a
+// cooldown within 5 seconds of a spell cast.
+const streamLookaheadSeconds = 10.0
+
+// Reasons a game ended, as reported in baseview game_end events.
+const (
+	endNexusDestroyed = "nexus_destroyed"
+	endSurrender      = "surrender"
+	endStall          = "stall"
+	endUnknown        = "unknown"
+)
+
+// ConversionStats describes how well the network ids referenced by a game's
+// elo events could be resolved to entities.
+type ConversionStats struct {
+	// Events is the number of elo events read.
+	Events int `json:"events"`
+	// LateMappings is the number of references resolved using a mapping that
+	// was logged shortly after the reference.
+	LateMappings int `json:"late_mappings"`
+	// Deferred is the number of events that could only be converted once the
+	// rest of the game had been read.
+	Deferred int `json:"deferred"`
+	// Unresolved is the number of events dropped because they referenced a
+	// network id that could not be resolved, by elo event type.
+	Unresolved map[string]int `json:"unresolved"`
+	// UnresolvedIDs are the (sorted, distinct) network ids that could not be
+	// resolved.
+	UnresolvedIDs []int64 `json:"unresolved_ids"`
+}
+
+// TotalUnresolved returns the number of events dropped for unresolved ids.
+func (s *ConversionStats) TotalUnresolved() int {
+	total := 0
+	for _, n := range s.Unresolved {
+		total += n
 	}
+	return total
+}
 
-	// ward_placed events occur in two steps. first we notice a spell_cast,
-	// then a ward_created.
-	wardSpells := []*SpellCast{}
-	wardCreations := []*OnCreate{}
+// EloToBaseview converts a full game of elo events into a baseview. Events that
+// reference network ids that can't be resolved are dropped, and counted in the
+// stats.
+func EloToBaseview(events []EloEvent, participants []baseview.Participant) (*baseview.Baseview, *ConversionStats, error) {
+	c := newConverter(participants)
 
 	// iterate through all events initially to build up a mapping of network id
 	// to elo object (participant ids and names).
-	netIDs := NewNetworkIDMap()
 	for _, e := range events {
-		netIDs.AddEvent(e)
-	}
-	for _, p := range participants {
-		netIDs.AddParticipant(p.ParticipantID, p.SummonerName)
+		c.netIDs.AddEvent(e)
 	}
 
+	// every event is already in memory, so there's no reason to limit how far
+	// ahead each conversion may look.
+	return c.convert(NewSliceIterator(events), math.Inf(1), false)
+}
+
+// StreamToBaseview converts the events yielded by it into a baseview while
+// holding only a short window of elo events in memory at a time. Unlike
+// EloToBaseview, network id mappings are learned as they are read (up to
+// streamLookaheadSeconds ahead of the event being converted), rather than from
+// a full pass over the game. Events referencing ids that aren't mapped yet are
+// converted again once the game has been read.
+func StreamToBaseview(it Iterator, participants []baseview.Participant) (*baseview.Baseview, *ConversionStats, error) {
+	return newConverter(participants).convert(it, streamLookaheadSeconds, true)
+}
+
+// converter holds the state needed to convert elo events into baseview events.
+type converter struct {
+	res    *baseview.Baseview
+	netIDs *networkIDMap
+
+	// ward_placed events occur in two steps. first we notice a spell_cast,
+	// then a ward_created.
+	wardSpells    []*SpellCast
+	wardCreations []*OnCreate
+
 	// keep track of where each champ was last seen, so we can add positions to
 	// events that don't have them already (like deaths)
-	lastPosition := map[int64]baseview.Position{}
+	lastPosition map[int64]baseview.Position
+
+	// whether each champ was dead in their last ping (to notice respawns), and
+	// the highest level seen for each of their Q/W/E/R slots (to notice
+	// upgrades), by network id.
+	dead       map[int64]bool
+	slotLevels map[int64]*[4]int64
+
+	// the participant that most recently attacked each target (by network id),
+	// to credit epic monster kills, the epic monster kills we've already
+	// converted, and those whose killer was named by an EPIC_MONSTER_KILL
+	// (rather than guessed).
+	lastAttacker map[int64]int64
+	epicKills    map[int64]*baseview.EpicMonsterKill
+	namedKillers map[int64]bool
+
+	// why the game ended, as far as we can tell so far.
+	endReason string
+
+	// events that referenced network ids we couldn't (yet) resolve, and the
+	// ids that remained unresolved.
+	deferred []EloEvent
+	// what deferred pings and deaths saw when they were first converted, so
+	// that converting them again after the rest of the game gives the same
+	// spawns, skill upgrades and death positions.
+	pingChanges    map[*Ping][]baseview.Event
+	deathPositions map[*ChampDie]baseview.Position
+	unresolvedIDs  map[int64]bool
+	stats          *ConversionStats
+}
 
-	for index, e := range events {
-		var be baseview.Event
-		switch t := e.(type) {
+func newConverter(participants []baseview.Participant) *converter {
+	c := &converter{
+		res: &baseview.Baseview{
+			Version:      baseview.CurrentVersion,
+			Producer:     &baseview.Producer{Source: baseview.SourceElo},
+			Participants: participants,
+			Events:       []baseview.Event{},
+			LastUpdated:  time.Now(),
+		},
+		netIDs:         NewNetworkIDMap(),
+		wardSpells:     []*SpellCast{},
+		wardCreations:  []*OnCreate{},
+		lastPosition:   map[int64]baseview.Position{},
+		dead:           map[int64]bool{},
+		slotLevels:     map[int64]*[4]int64{},
+		lastAttacker:   map[int64]int64{},
+		epicKills:      map[int64]*baseview.EpicMonsterKill{},
+		namedKillers:   map[int64]bool{},
+		deferred:       []EloEvent{},
+		pingChanges:    map[*Ping][]baseview.Event{},
+		deathPositions: map[*ChampDie]baseview.Position{},
+		unresolvedIDs:  map[int64]bool{},
+		stats:          &ConversionStats{Unresolved: map[string]int{}, UnresolvedIDs: []int64{}},
+	}
+	for _, p := range participants {
+		c.netIDs.AddParticipant(p.ParticipantID, p.SummonerName)
+	}
+	return c
+}
 
-		case *ChampKill, *GameEnd, *NetworkIDMapping, *Kill, *OnDelete, *NexusDestroyed:
-			//skip
+// convert reads every event from it, converting each one once the window of
+// buffered events extends lookahead seconds past it (or the events run out).
+// If learnIDs is true, network id mappings are added as events are read.
+func (c *converter) convert(it Iterator, lookahead float64, learnIDs bool) (*baseview.Baseview, *ConversionStats, error) {
+	window := []EloEvent{}
 
-		case *BasicAttack:
-			attacker, err := netIDs.Get(t.NetworkID)
-			if err != nil {
-				return nil, err
-			}
-			target, err := netIDs.Get(t.TargetNetworkID)
-			if err != nil {
-				return nil, err
-			}
-			be = &baseview.Attack{
-				CooldownExpires: 0.0,
-				AttackerID:      attacker.ID,
-				AttackerType:    attacker.Type,
-				Slot:            "basic",
-				TargetID:        target.ID,
-				TargetType:      target.Type,
-				TargetPosition:  &t.TargetPosition,
-			}
-			be.SetType("attack")
+	for {
+		e, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		c.stats.Events++
+		if learnIDs {
+			c.netIDs.AddEvent(e)
+		}
+		window = append(window, e)
 
-		case *ChampDie:
-			champ, err := netIDs.Get(t.NetworkID)
-			if err != nil {
-				return nil, err
+		for len(window) > 0 && e.Time()-window[0].Time() > lookahead {
+			if err = c.convertOrDefer(window); err != nil {
+				return nil, nil, err
 			}
-			be = &baseview.Death{
-				VictimID: champ.ID,
-				Position: lastPosition[t.NetworkID],
-			}
-			be.SetType("death")
+			window[0] = nil
+			window = window[1:]
+		}
+	}
+	for ; len(window) > 0; window = window[1:] {
+		if err := c.convertOrDefer(window); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := c.convertDeferred(); err != nil {
+		return nil, nil, err
+	}
 
-		case *Damage:
-			attacker, err := netIDs.Get(t.NetworkID)
-			if err != nil {
-				return nil, err
-			}
-			victim, err := netIDs.Get(t.TargetNetworkID)
-			if err != nil {
-				return nil, err
-			}
-			senderMax := 1000.0 // default in case we can't get max health
-			if p := nextPing(events[index:], t.TargetNetworkID); p != nil {
-				senderMax = p.HealthMax
-			}
-			be = &baseview.Damage{
-				AttackerID:   attacker.ID,
-				AttackerType: attacker.Type,
-				VictimID:     victim.ID,
-				VictimType:   victim.Type,
-				Total:        t.Damage,
-				Percent:      t.Damage * 100.0 / senderMax,
+	wardsPlaced, err := placeWards(c.wardCreations, c.wardSpells, c.netIDs)
+	if err != nil {
+		return c.res, c.stats, err
+	}
+	c.res.Events = append(c.res.Events, wardsPlaced...)
+
+	// sort res.Events since we may have modified the relative ordering of
+	// events by adjusting certain event offsets.
+	sort.Stable(byTime(c.res.Events))
+
+	c.stats.LateMappings = c.netIDs.lateMappings
+	for id := range c.unresolvedIDs {
+		c.stats.UnresolvedIDs = append(c.stats.UnresolvedIDs, id)
+	}
+	sort.Sort(int64s(c.stats.UnresolvedIDs))
+
+	return c.res, c.stats, nil
+}
+
+// convertOrDefer converts the first event in the window, or saves it for
+// convertDeferred if it references a network id we can't resolve yet.
+func (c *converter) convertOrDefer(window []EloEvent) error {
+	err := c.convertResolved(window)
+	if _, ok := err.(*unresolvedError); ok {
+		c.deferred = append(c.deferred, window[0])
+		return nil
+	}
+	return err
+}
+
+// convertDeferred converts the deferred events again, now that every network
+// id mapping in the game is known, and counts those that still can't be
+// resolved. The events that followed each deferred event are no longer
+// available, so cooldowns and max health fall back to their defaults.
+func (c *converter) convertDeferred() error {
+	for _, e := range c.deferred {
+		err := c.convertResolved([]EloEvent{e})
+		if u, ok := err.(*unresolvedError); ok {
+			c.stats.Unresolved[e.Type()]++
+			c.unresolvedIDs[u.NetworkID] = true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		c.stats.Deferred++
+	}
+	c.deferred = nil
+	return nil
+}
+
+// convertResolved converts the first event in the window, like convertFirst,
+// but only counts the late mappings it used if all of its network ids could be
+// resolved. A deferred event resolves its ids again when it is retried.
+func (c *converter) convertResolved(window []EloEvent) error {
+	lateMappings := c.netIDs.lateMappings
+	err := c.convertFirst(window)
+	if _, ok := err.(*unresolvedError); ok {
+		c.netIDs.lateMappings = lateMappings
+	}
+	return err
+}
+
+// convertFirst converts the first event in the window, which contains the
+// event and (some of) the events that follow it.
+func (c *converter) convertFirst(window []EloEvent) error {
+	e := window[0]
+
+	var be baseview.Event
+	switch t := e.(type) {
+
+	case *ChampKill, *NetworkIDMapping, *Kill, *OnDelete:
+		//skip
+
+	case *GameStall:
+		if c.endReason == "" {
+			c.endReason = endStall
+		}
+
+	case *NexusDestroyed:
+		if c.endReason != endSurrender {
+			c.endReason = endNexusDestroyed
+		}
+
+	case *SurrenderAgreed:
+		c.endReason = endSurrender
+
+	case *GameEnd:
+		reason := c.endReason
+		if reason == "" {
+			reason = endUnknown
+		}
+		be = &baseview.GameEnd{Reason: reason}
+		be.SetType("game_end")
+
+	case *BasicAttack:
+		attacker, err := c.netIDs.GetAt(t.NetworkID, t.Time())
+		if err != nil {
+			return err
+		}
+		target, err := c.netIDs.GetAt(t.TargetNetworkID, t.Time())
+		if err != nil {
+			return err
+		}
+		c.noteAttack(attacker, t.TargetNetworkID)
+		be = &baseview.Attack{
+			CooldownExpires: 0.0,
+			AttackerID:      attacker.ID,
+			AttackerType:    attacker.Type,
+			Slot:            "basic",
+			TargetID:        target.ID,
+			TargetType:      target.Type,
+			TargetPosition:  &t.TargetPosition,
+		}
+		be.SetType("attack")
+
+	case *ChampDie:
+		position, deferred := c.deathPositions[t]
+		if !deferred {
+			position = c.lastPosition[t.NetworkID]
+		}
+		champ, err := c.netIDs.GetAt(t.NetworkID, t.Time())
+		if err != nil {
+			c.deathPositions[t] = position
+			return err
+		}
+		delete(c.deathPositions, t)
+		be = &baseview.Death{
+			VictimID: champ.ID,
+			Position: position,
+		}
+		be.SetType("death")
+
+	case *Damage:
+		attacker, err := c.netIDs.GetAt(t.NetworkID, t.Time())
+		if err != nil {
+			return err
+		}
+		victim, err := c.netIDs.GetAt(t.TargetNetworkID, t.Time())
+		if err != nil {
+			return err
+		}
+		senderMax := 1000.0 // default in case we can't get max health
+		if p := nextPing(window, t.TargetNetworkID); p != nil {
+			senderMax = p.HealthMax
+		}
+		be = &baseview.Damage{
+			AttackerID:   attacker.ID,
+			AttackerType: attacker.Type,
+			VictimID:     victim.ID,
+			VictimType:   victim.Type,
+			Total:        t.Damage,
+			Percent:      t.Damage * 100.0 / senderMax,
+		}
+		be.SetType("damage")
+
+	case *Die:
+		victim, err := c.netIDs.GetAt(t.NetworkID, t.Time())
+		if err != nil {
+			return err
+		}
+		if victim.Type == baseview.ActorTurret || victim.Type == baseview.ActorInhibitor {
+			be = &baseview.BuildingKill{
+				BuildingType: victim.Type,
+				BuildingID:   victim.ID,
 			}
-			be.SetType("damage")
+			be.SetType("building_kill")
+		} else if isWardCreate(victim.Name) {
+			be = &baseview.WardDeath{WardID: t.NetworkID}
+			be.SetType("ward_death")
+		} else if victim.Type == baseview.ActorMonster && baseview.IsEpicMonster(victim.ID) {
+			be = c.epicMonsterKill(t.NetworkID, victim.ID, c.lastAttacker[t.NetworkID], false)
+		}
+
+	case *EpicMonsterKill:
+		monster, err := c.netIDs.GetAt(t.SenderID, t.Time())
+		if err != nil {
+			return err
+		}
+		killer, err := c.netIDs.GetAt(t.KillerID, t.Time())
+		if err != nil {
+			return err
+		}
+		be = c.epicMonsterKill(t.SenderID, monster.ID, killer.ID, true)
 
-		case *Die:
-			victim, err := netIDs.Get(t.NetworkID)
+	case *LevelUp:
+		if t.Level > 0 {
+			champ, err := c.netIDs.GetAt(t.NetworkID, t.Time())
 			if err != nil {
-				return nil, err
+				return err
 			}
-			if victim.Type == baseview.ActorTurret || victim.Type == baseview.ActorInhibitor {
-				be = &baseview.BuildingKill{
-					BuildingType: victim.Type,
-					BuildingID:   victim.ID,
-				}
-				be.SetType("building_kill")
-			} else if isWardCreate(victim.Name) {
-				be = &baseview.WardDeath{WardID: t.NetworkID}
-				be.SetType("ward_death")
+			be = &baseview.LevelUp{
+				Level:         t.Level,
+				ParticipantID: champ.ID,
 			}
+			be.SetType("level_up")
+		}
 
-		case *LevelUp:
-			if t.Level > 0 {
-				champ, err := netIDs.Get(t.NetworkID)
-				if err != nil {
-					return nil, err
-				}
-				be = &baseview.LevelUp{
-					Level:         t.Level,
-					ParticipantID: champ.ID,
-				}
-				be.SetType("level_up")
-			}
+	case *OnCreate:
+		if isWardCreate(t.SenderName) {
+			c.wardCreations = append(c.wardCreations, t)
+		}
 
-		case *OnCreate:
-			if isWardCreate(t.SenderName) {
-				wardCreations = append(wardCreations, t)
+	case *Ping:
+		// changes are found in the order the pings were logged, even if this
+		// one has to be deferred.
+		changes, deferred := c.pingChanges[t]
+		if !deferred {
+			c.lastPosition[t.NetworkID] = t.Position
+			changes = c.convertPingChanges(t)
+		}
+		person, err := c.netIDs.GetAt(t.NetworkID, t.Time())
+		if err != nil {
+			c.pingChanges[t] = changes
+			return err
+		}
+		delete(c.pingChanges, t)
+		for _, change := range changes {
+			switch ch := change.(type) {
+			case *baseview.Spawn:
+				ch.ParticipantID = person.ID
+			case *baseview.SlotUpgrade:
+				ch.ParticipantID = person.ID
 			}
+			c.add(t, change)
+		}
+		be = &baseview.StateUpdate{
+			Gold:                 t.Gold,
+			Health:               t.Health,
+			HealthMax:            t.HealthMax,
+			InGrass:              t.InGrass,
+			Mana:                 t.Mana,
+			ManaMax:              t.ManaMax,
+			MinionsKilled:        t.MinionsKilled,
+			NeutralMinionsKilled: t.NeutralMinionsKilled,
+			ParticipantID:        person.ID,
+			Position:             t.Position,
+			UnderOwnTurret:       t.UnderTurret && !t.UnderEnemyTurret,
+			UnderEnemyTurret:     t.UnderEnemyTurret,
+		}
+		be.SetType("state_update")
 
-		case *Ping:
-			person, err := netIDs.Get(t.NetworkID)
-			if err != nil {
-				return nil, err
-			}
-			lastPosition[t.NetworkID] = t.Position
-			be = &baseview.StateUpdate{
-				Gold:                 t.Gold,
-				Health:               t.Health,
-				HealthMax:            t.HealthMax,
-				InGrass:              t.InGrass,
-				Mana:                 t.Mana,
-				ManaMax:              t.ManaMax,
-				MinionsKilled:        t.MinionsKilled,
-				NeutralMinionsKilled: t.NeutralMinionsKilled,
-				ParticipantID:        person.ID,
-				Position:             t.Position,
-				UnderOwnTurret:       t.UnderTurret && !t.UnderEnemyTurret,
-				UnderEnemyTurret:     t.UnderEnemyTurret,
-			}
-			be.SetType("state_update")
-
-		case *SpellCast:
-			if t.WardItemType() != nil {
-				wardSpells = append(wardSpells, t)
-			} else {
-				attacker, err := netIDs.Get(t.NetworkID)
-				if err != nil {
-					return nil, err
-				}
-				target, err := netIDs.Get(t.TargetNetworkID)
-				if err != nil {
-					return nil, err
-				}
-				be = &baseview.Attack{
-					CooldownExpires: getCooldown(events[index:], t.NetworkID, t.Slot),
-					Start:           &t.PositionStart,
-					End:             &t.PositionEnd,
-					Slot:            t.Slot,
-					AttackerID:      attacker.ID,
-					AttackerType:    attacker.Type,
-					TargetID:        target.ID,
-					TargetType:      target.Type,
-				}
-				be.SetType("attack")
+	case *SpellCast:
+		// resolve both ids before converting anything, so that a deferred cast
+		// isn't partially converted twice.
+		caster, err := c.netIDs.GetAt(t.NetworkID, t.Time())
+		if err != nil {
+			return err
+		}
+		target := &eloEntity{}
+		if t.WardItemType() == nil {
+			if target, err = c.netIDs.GetAt(t.TargetNetworkID, t.Time()); err != nil {
+				return err
 			}
+		}
 
-		default:
-			log.Warning(fmt.Sprintf("unhandled event: %s", e.Type()))
+		if isItemSlot(t.Slot) {
+			used := &baseview.ItemUsed{
+				Slot:            t.Slot,
+				CooldownExpires: getCooldown(window, t.NetworkID, t.Slot),
+				ParticipantID:   caster.ID,
+			}
+			used.SetType("item_used")
+			c.add(e, used)
 		}
-		if be != nil {
-			if be.Seconds() == 0 {
-				be.SetSeconds(e.MatchSeconds())
+
+		if t.WardItemType() != nil {
+			c.wardSpells = append(c.wardSpells, t)
+		} else {
+			c.noteAttack(caster, t.TargetNetworkID)
+			be = &baseview.Attack{
+				CooldownExpires: getCooldown(window, t.NetworkID, t.Slot),
+				Start:           &t.PositionStart,
+				End:             &t.PositionEnd,
+				Slot:            t.Slot,
+				AttackerID:      caster.ID,
+				AttackerType:    caster.Type,
+				TargetID:        target.ID,
+				TargetType:      target.Type,
 			}
-			res.Events = append(res.Events, be)
+			be.SetType("attack")
 		}
+
+	default:
+		log.Warning(fmt.Sprintf("unhandled event: %s", e.Type()))
+	}
+	if be != nil {
+		c.add(e, be)
 	}
+	return nil
+}
 
-	wardsPlaced, err := placeWards(wardCreations, wardSpells, netIDs)
-	if err != nil {
-		return res, err
+// add appends a baseview event converted from e, which (unless it already has
+// a time) happens when e did.
+func (c *converter) add(e EloEvent, be baseview.Event) {
+	if be.Seconds() == 0 {
+		be.SetSeconds(e.MatchSeconds())
 	}
-	res.Events = append(res.Events, wardsPlaced...)
+	c.res.Events = append(c.res.Events, be)
+}
 
-	// sort res.Events since we may have modified the relative ordering of
-	// events by adjusting certain event offsets.
-	sort.Stable(byTime(res.Events))
+// noteAttack remembers the attacker (if a hero) as the last to attack the
+// target.
+func (c *converter) noteAttack(attacker *eloEntity, targetNetworkID int64) {
+	if attacker.Type == baseview.ActorHero && targetNetworkID != 0 {
+		c.lastAttacker[targetNetworkID] = attacker.ID
+	}
+}
 
-	return res, nil
+// epicMonsterKill returns the kill of an epic monster, or nil if the monster
+// (by network id) has already been killed. Some logs report the kill both as a
+// DIE, whose killer (the last attacker) is only a guess, and an
+// EPIC_MONSTER_KILL, which names the killer. named is true for the latter,
+// whose killer replaces the guess if the DIE came first.
+func (c *converter) epicMonsterKill(networkID, monsterID, killerID int64, named bool) baseview.Event {
+	if kill := c.epicKills[networkID]; kill != nil {
+		if named && !c.namedKillers[networkID] {
+			kill.KillerID = killerID
+			c.namedKillers[networkID] = true
+		}
+		return nil
+	}
+
+	be := &baseview.EpicMonsterKill{
+		VictimID: monsterID,
+		KillerID: killerID,
+	}
+	be.SetType("epic_monster_kill")
+	c.epicKills[networkID] = be
+	c.namedKillers[networkID] = named
+	return be
+}
+
+// convertPingChanges returns the events we can only detect by comparing a
+// ping to earlier ones: respawns and skill upgrades. Their participant ids are
+// left for the caller to set.
+func (c *converter) convertPingChanges(p *Ping) []baseview.Event {
+	changes := []baseview.Event{}
+	if c.dead[p.NetworkID] && !p.Dead {
+		spawn := &baseview.Spawn{Position: p.Position}
+		spawn.SetType("spawn")
+		changes = append(changes, spawn)
+	}
+	c.dead[p.NetworkID] = p.Dead
+
+	levels := c.slotLevels[p.NetworkID]
+	if levels == nil {
+		levels = &[4]int64{}
+		c.slotLevels[p.NetworkID] = levels
+	}
+	for i, current := range []int64{p.SlotQLevel, p.SlotWLevel, p.SlotELevel, p.SlotRLevel} {
+		// a skill may be upgraded more than once between pings.
+		for ; levels[i] < current; levels[i]++ {
+			upgrade := &baseview.SlotUpgrade{
+				Level: levels[i] + 1,
+				Slot:  skillSlots[i],
+			}
+			upgrade.SetType("slot_upgrade")
+			changes = append(changes, upgrade)
+		}
+	}
+	return changes
+}
+
+// skillSlots are the names of the slots whose levels pings report, in order.
+var skillSlots = []string{"Q", "W", "E", "R"}
+
+// isItemSlot returns true if the spell slot holds a summoner spell or an item
+// (including the trinket).
+func isItemSlot(slot string) bool {
+	switch slot {
+	case "Summoner1", "Summoner2", "Trinket":
+		return true
+	}
+	return strings.HasPrefix(slot, "Item")
 }
 
 // placeWards iterates in order through each ward creation and greedily finds
@@ -211,8 +576,8 @@ func EloToBaseview(events []EloEvent, participants []baseview.Participant) (*bas
 // filtered to just those "ward-creating" casts), returning an error if there
 // is no suitable pairing for all spells and creations.
 // NOTES:
-//  - this is not guaranteed to find a maximally-optimal pairing
-//  - we TYPICALLY see spell_cast slightly before ward_created, but not always
+//   - this is not guaranteed to find a maximally-optimal pairing
+//   - we TYPICALLY see spell_cast slightly before ward_created, but not always
 func placeWards(creations []*OnCreate, spells []*SpellCast, netIDs *networkIDMap) ([]baseview.Event, error) {
 	if len(spells) != len(creations) {
 		return nil, fmt.Errorf("found %d ward spell cast events, but %d ward_created events", len(spells), len(creations))
@@ -234,7 +599,7 @@ func placeWards(creations []*OnCreate, spells []*SpellCast, netIDs *networkIDMap
 		spells = spells[1:]
 
 		itemType := spell.WardItemType()
-		champ, err := netIDs.Get(spell.NetworkID)
+		champ, err := netIDs.GetAt(spell.NetworkID, spell.Time())
 		if err != nil {
 			return nil, err
 		}
@@ -267,7 +632,7 @@ func firstMatching(create *OnCreate, spells []*SpellCast, netIDs *networkIDMap)
 
 	for i := 0; i < len(spells); i++ {
 		spell := spells[i]
-		champ, err := netIDs.Get(spell.NetworkID)
+		champ, err := netIDs.GetAt(spell.NetworkID, spell.Time())
 		if err != nil {
 			return -1, err
 		}
@@ -359,6 +724,13 @@ func getCooldown(events []EloEvent, networkID int64, slotName string) float64 {
 	return first.MatchSeconds()
 }
 
+// int64s is a sort implementation for int64 slices.
+type int64s []int64
+
+func (s int64s) Len() int           { return len(s) }
+func (s int64s) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s int64s) Less(i, j int) bool { return s[i] < s[j] }
+
 func isWardCreate(name string) bool {
 	switch strings.ToLower(name) {
 	case "sightward", "visionward", "jammerdevice":
@@ -89,3 +89,273 @@ func isWardEqual(is *is.Is, wpv baseview.Event, seconds float64, itemType basevi
 	is.Equal(itemType, wp.WardItemType)
 	is.Equal(pID, wp.ParticipantID)
 }
+
+func TestStreamToBaseview(t *testing.T) {
+	is := is.New(t)
+
+	participants := []baseview.Participant{
+		{ParticipantID: 1, SummonerName: "hero1"},
+		{ParticipantID: 6, SummonerName: "hero6"},
+	}
+	events := []EloEvent{
+		&NetworkIDMapping{SenderName: "hero1", NetworkID: 101, EloType: "ID_HERO"},
+		&NetworkIDMapping{SenderName: "hero6", NetworkID: 106, EloType: "ID_HERO"},
+	}
+	// two minutes of pings, with periodic damage and spell casts.
+	for i := 0; i < 240; i++ {
+		secs := float64(i) / 2
+		tick := []EloEvent{}
+		for _, id := range []int64{101, 106} {
+			tick = append(tick, &Ping{
+				NetworkID:        id,
+				Gold:             int64(500 + i),
+				Health:           400,
+				HealthMax:        float64(500 + i),
+				Position:         baseview.Position{X: float64(1000 + i), Y: float64(id)},
+				SlotQCooldownExp: secs + 2,
+			})
+		}
+		if i%10 == 5 {
+			tick = append(tick,
+				&Damage{NetworkID: 101, TargetNetworkID: 106, Damage: 50},
+				&SpellCast{NetworkID: 106, TargetNetworkID: 101, Slot: "Q"},
+			)
+		}
+		if i == 200 {
+			tick = append(tick, &ChampDie{NetworkID: 106})
+		}
+		for _, e := range tick {
+			e.SetTime(secs)
+			e.SetMatchSeconds(secs + 10)
+		}
+		events = append(events, tick...)
+	}
+
+	expected, _, err := EloToBaseview(events, participants)
+	is.NotErr(err)
+	actual, stats, err := StreamToBaseview(NewSliceIterator(events), participants)
+	is.NotErr(err)
+
+	is.Equal(len(events)-2, len(actual.Events))
+	is.Equal(expected.Events, actual.Events)
+	is.Equal(len(events), stats.Events)
+	is.Equal(0, stats.TotalUnresolved())
+}
+
+func TestNetworkIDReuse(t *testing.T) {
+	is := is.New(t)
+
+	netIDs := NewNetworkIDMap()
+	events := []EloEvent{
+		&OnCreate{SenderName: "SightWard", NetworkID: 700},
+		&Die{NetworkID: 700},
+		&OnCreate{SenderName: "Minion_T100L0S0N0", NetworkID: 700},
+		&OnDelete{NetworkID: 700},
+	}
+	for i, e := range events {
+		e.SetTime(float64(10 * i))
+		netIDs.AddEvent(e)
+	}
+
+	ward, err := netIDs.GetAt(700, 5)
+	is.NotErr(err)
+	is.Equal(baseview.ActorWard, ward.Type)
+
+	minion, err := netIDs.GetAt(700, 25)
+	is.NotErr(err)
+	is.Equal(baseview.ActorMinion, minion.Type)
+
+	// between the ward's death and the minion's creation.
+	_, err = netIDs.GetAt(700, 15)
+	is.Err(err)
+
+	// just before the minion was created.
+	minion, err = netIDs.GetAt(700, 19.5)
+	is.NotErr(err)
+	is.Equal(baseview.ActorMinion, minion.Type)
+	is.Equal(1, netIDs.lateMappings)
+
+	_, err = netIDs.GetAt(700, 45)
+	is.Err(err)
+}
+
+func TestStreamToBaseviewUnresolved(t *testing.T) {
+	is := is.New(t)
+
+	participants := []baseview.Participant{{ParticipantID: 1, SummonerName: "hero1"}}
+	events := []EloEvent{
+		// the hero is referenced long before its mapping.
+		&Ping{NetworkID: 101},
+		&BasicAttack{baseEvent: baseEvent{Event: "BASIC_ATTACK"}, NetworkID: 101, TargetNetworkID: 901},
+		&Ping{NetworkID: 101},
+		&NetworkIDMapping{SenderName: "hero1", NetworkID: 101, EloType: "ID_HERO"},
+		&Ping{NetworkID: 101},
+		// the minion is damaged just before it is created.
+		&Damage{NetworkID: 101, TargetNetworkID: 900, Damage: 20},
+		&OnCreate{SenderName: "Minion_T200L0S0N0", NetworkID: 900},
+	}
+	for i, secs := range []float64{0, 5, 20, 30, 40, 59.5, 60} {
+		events[i].SetTime(secs)
+		events[i].SetMatchSeconds(secs)
+	}
+
+	bv, stats, err := StreamToBaseview(NewSliceIterator(events), participants)
+	is.NotErr(err)
+
+	is.Equal(4, len(bv.Events))
+	is.Equal(len(events), stats.Events)
+	is.Equal(1, stats.Deferred)
+	is.Equal(1, stats.LateMappings)
+	is.Equal(map[string]int{"BASIC_ATTACK": 1}, stats.Unresolved)
+	is.Equal([]int64{901}, stats.UnresolvedIDs)
+}
+
+func TestEloToBaseviewDerivedEvents(t *testing.T) {
+	is := is.New(t)
+
+	participants := []baseview.Participant{{ParticipantID: 1, SummonerName: "hero1"}}
+	pings := []*Ping{
+		{NetworkID: 101, SlotQLevel: 1},
+		{NetworkID: 101, SlotQLevel: 1, SlotWLevel: 2},
+		{NetworkID: 101, SlotQLevel: 1, SlotWLevel: 2, Dead: true},
+		{NetworkID: 101, SlotQLevel: 1, SlotWLevel: 2, Position: baseview.Position{X: 400, Y: 420}},
+	}
+	events := []EloEvent{
+		&NetworkIDMapping{SenderName: "hero1", NetworkID: 101, EloType: "ID_HERO"},
+		&OnCreate{SenderName: "SRU_Baron12.1.1", NetworkID: 500},
+		pings[0],
+		&BasicAttack{NetworkID: 101, TargetNetworkID: 500},
+		&SpellCast{NetworkID: 101, Slot: "Summoner1", SpellName: "SummonerSmite", TargetNetworkID: 500},
+		&Die{NetworkID: 500},
+		&EpicMonsterKill{SenderID: 500, KillerID: 101},
+		pings[1],
+		pings[2],
+		pings[3],
+		&NexusDestroyed{Nexus: "HQ_T2"},
+		&GameEnd{},
+	}
+	for i, e := range events {
+		e.SetTime(float64(i))
+		e.SetMatchSeconds(float64(i))
+	}
+
+	bv, _, err := EloToBaseview(events, participants)
+	is.NotErr(err)
+
+	byType := map[string][]baseview.Event{}
+	for _, e := range bv.Events {
+		byType[e.Type()] = append(byType[e.Type()], e)
+	}
+
+	is.Equal(1, len(byType["epic_monster_kill"]))
+	kill := byType["epic_monster_kill"][0].(*baseview.EpicMonsterKill)
+	is.Equal(int64(baseview.MonsterBaron), kill.VictimID)
+	is.Equal(int64(1), kill.KillerID)
+	is.Equal(5.0, kill.Seconds())
+
+	is.Equal(1, len(byType["item_used"]))
+	used := byType["item_used"][0].(*baseview.ItemUsed)
+	is.Equal("Summoner1", used.Slot)
+	is.Equal(int64(1), used.ParticipantID)
+
+	is.Equal(3, len(byType["slot_upgrade"]))
+	upgrade := byType["slot_upgrade"][2].(*baseview.SlotUpgrade)
+	is.Equal("W", upgrade.Slot)
+	is.Equal(int64(2), upgrade.Level)
+	is.Equal(7.0, upgrade.Seconds())
+
+	is.Equal(1, len(byType["spawn"]))
+	spawn := byType["spawn"][0].(*baseview.Spawn)
+	is.Equal(pings[3].Position, spawn.Position)
+	is.Equal(9.0, spawn.Seconds())
+
+	is.Equal(1, len(byType["game_end"]))
+	is.Equal(endNexusDestroyed, byType["game_end"][0].(*baseview.GameEnd).Reason)
+}
+
+func TestEloToBaseviewEpicKiller(t *testing.T) {
+	is := is.New(t)
+
+	participants := []baseview.Participant{
+		{ParticipantID: 1, SummonerName: "hero1"},
+		{ParticipantID: 2, SummonerName: "hero2"},
+	}
+	for _, dieFirst := range []bool{true, false} {
+		// hero2 attacks last, but hero1 smites the dragon.
+		events := []EloEvent{
+			&NetworkIDMapping{SenderName: "hero1", NetworkID: 101, EloType: "ID_HERO"},
+			&NetworkIDMapping{SenderName: "hero2", NetworkID: 102, EloType: "ID_HERO"},
+			&OnCreate{SenderName: "SRU_Dragon_Air6.1.1", NetworkID: 500},
+			&BasicAttack{NetworkID: 102, TargetNetworkID: 500},
+		}
+		die, kill := EloEvent(&Die{NetworkID: 500}), EloEvent(&EpicMonsterKill{SenderID: 500, KillerID: 101})
+		if dieFirst {
+			events = append(events, die, kill)
+		} else {
+			events = append(events, kill, die)
+		}
+		for i, e := range events {
+			e.SetTime(float64(i))
+			e.SetMatchSeconds(float64(i))
+		}
+
+		bv, _, err := EloToBaseview(events, participants)
+		is.NotErr(err)
+		kills := []*baseview.EpicMonsterKill{}
+		for _, e := range bv.Events {
+			if k, ok := e.(*baseview.EpicMonsterKill); ok {
+				kills = append(kills, k)
+			}
+		}
+		is.Equal(1, len(kills))
+		is.Equal(int64(1), kills[0].KillerID)
+		is.Equal(4.0, kills[0].Seconds())
+	}
+}
+
+func TestStreamToBaseviewDeferredOrder(t *testing.T) {
+	is := is.New(t)
+
+	participants := []baseview.Participant{{ParticipantID: 2, SummonerName: "hero2"}}
+	events := []EloEvent{
+		&Ping{NetworkID: 102, Dead: true, Position: baseview.Position{X: 1}},
+		// the minion is created just after this, but the turret is mapped
+		// long after.
+		&Damage{NetworkID: 900, TargetNetworkID: 300, Damage: 20},
+		&OnCreate{SenderName: "Minion_T200L0S0N0", NetworkID: 900},
+		&Ping{NetworkID: 102, SlotQLevel: 1, Position: baseview.Position{X: 2}},
+		&ChampDie{NetworkID: 102},
+		&Ping{NetworkID: 102, SlotQLevel: 1, SlotWLevel: 1, Position: baseview.Position{X: 3}},
+		&NetworkIDMapping{SenderName: "hero2", NetworkID: 102, EloType: "ID_HERO"},
+		&NetworkIDMapping{SenderName: "Turret_T1_L_03_A", NetworkID: 300, EloType: "ID_TURRET"},
+	}
+	for i, secs := range []float64{0, 0.2, 0.5, 5, 6, 40, 45, 50} {
+		events[i].SetTime(secs)
+		events[i].SetMatchSeconds(secs)
+	}
+
+	bv, stats, err := StreamToBaseview(NewSliceIterator(events), participants)
+	is.NotErr(err)
+
+	byType := map[string][]baseview.Event{}
+	for _, e := range bv.Events {
+		byType[e.Type()] = append(byType[e.Type()], e)
+	}
+	// the deferred pings are compared to those around them in the log, not
+	// to the last one.
+	is.Equal(1, len(byType["spawn"]))
+	is.Equal(5.0, byType["spawn"][0].Seconds())
+	is.Equal(2, len(byType["slot_upgrade"]))
+	is.Equal("Q", byType["slot_upgrade"][0].(*baseview.SlotUpgrade).Slot)
+	is.Equal(5.0, byType["slot_upgrade"][0].Seconds())
+	is.Equal(int64(2), byType["slot_upgrade"][0].(*baseview.SlotUpgrade).ParticipantID)
+	is.Equal("W", byType["slot_upgrade"][1].(*baseview.SlotUpgrade).Slot)
+	is.Equal(40.0, byType["slot_upgrade"][1].Seconds())
+	is.Equal(baseview.Position{X: 2}, byType["death"][0].(*baseview.Death).Position)
+
+	// the damage's late minion mapping is counted once, though it was
+	// resolved again when the damage was retried.
+	is.Equal(1, len(byType["damage"]))
+	is.Equal(1, stats.LateMappings)
+	is.Equal(0, stats.TotalUnresolved())
+}
@@ -0,0 +1,479 @@
+package parse
+
+// This file contains hand-written decoders that parse a line of elo text
+// directly into its event, without the regular expressions of getVal or the
+// reflection of mapstructure. They are over ten times faster than fields and
+// EloEvent, which dominate conversion time for long games.
+//
+// The decoders only handle well-formed lines. Anything out of the ordinary (an
+// unparseable value, a value of the wrong type for its field, a key that only
+// matches a field case-insensitively, an event type we ignore or don't know)
+// makes decodeLine report !ok, and the line should be parsed by the general
+// path instead. That keeps the behavior (and error messages) identical to the
+// general path, which FuzzDecodeLine checks.
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/VantageSports/lolelo/event"
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+type valueKind int
+
+const (
+	intValue valueKind = iota
+	floatValue
+	boolValue
+	positionValue
+	stringValue
+)
+
+// value is a single parsed field value. It is the unboxed equivalent of what
+// getVal returns.
+type value struct {
+	kind valueKind
+	i    int64
+	f    float64
+	b    bool
+	s    string
+	pos  baseview.Position
+}
+
+// setter assigns the value of a key to the matching field of an event. It
+// returns false if the value is the wrong type for the field. known is false if
+// the key isn't a field of the event.
+type setter func(key string, v *value) (known, ok bool)
+
+// newDecoder returns an empty event of the type and its setter, or nil if the
+// event type has no fast decoder.
+func newDecoder(eventType string) (event.EloEvent, setter) {
+	switch eventType {
+	case "BASIC_ATTACK":
+		e := &event.BasicAttack{}
+		return e, func(key string, v *value) (bool, bool) {
+			switch key {
+			case "sender":
+				return true, v.str(&e.SenderName)
+			case "network_id":
+				return true, v.int(&e.NetworkID)
+			case "target":
+				return true, v.str(&e.TargetName)
+			case "target_network_id":
+				return true, v.int(&e.TargetNetworkID)
+			case "target_position":
+				return true, v.position(&e.TargetPosition)
+			}
+			return baseField(&e.Event, &e.Time_, &e.MatchSeconds_, key, v)
+		}
+	case "CHAMP_DIE":
+		e := &event.ChampDie{}
+		return e, networkIDSetter(&e.NetworkID, &e.Event, &e.Time_, &e.MatchSeconds_)
+	case "CHAMP_KILL":
+		e := &event.ChampKill{}
+		return e, networkIDSetter(&e.NetworkID, &e.Event, &e.Time_, &e.MatchSeconds_)
+	case "DAMAGE":
+		e := &event.Damage{}
+		return e, func(key string, v *value) (bool, bool) {
+			switch key {
+			case "damage":
+				return true, v.float(&e.Damage)
+			case "sender":
+				return true, v.str(&e.SenderName)
+			case "network_id":
+				return true, v.int(&e.NetworkID)
+			case "target":
+				return true, v.str(&e.TargetName)
+			case "target_network_id":
+				return true, v.int(&e.TargetNetworkID)
+			case "type":
+				return true, v.str(&e.DamageType)
+			}
+			return baseField(&e.Event, &e.Time_, &e.MatchSeconds_, key, v)
+		}
+	case "DIE":
+		e := &event.Die{}
+		return e, networkIDSetter(&e.NetworkID, &e.Event, &e.Time_, &e.MatchSeconds_)
+	case "GAME_END":
+		e := &event.GameEnd{}
+		return e, func(key string, v *value) (bool, bool) {
+			return baseField(&e.Event, &e.Time_, &e.MatchSeconds_, key, v)
+		}
+	case "ID_BARRACKS", "ID_HERO", "ID_TURRET":
+		e := &event.NetworkIDMapping{EloType: eventType}
+		return e, func(key string, v *value) (bool, bool) {
+			switch key {
+			case "name":
+				return true, v.str(&e.SenderName)
+			case "network_id":
+				return true, v.int(&e.NetworkID)
+			case "elo_type":
+				return true, v.str(&e.EloType)
+			}
+			return baseField(&e.Event, &e.Time_, &e.MatchSeconds_, key, v)
+		}
+	case "KILL":
+		e := &event.Kill{}
+		return e, networkIDSetter(&e.NetworkID, &e.Event, &e.Time_, &e.MatchSeconds_)
+	case "LEVEL_UP":
+		e := &event.LevelUp{}
+		return e, func(key string, v *value) (bool, bool) {
+			switch key {
+			case "level":
+				return true, v.int(&e.Level)
+			case "network_id":
+				return true, v.int(&e.NetworkID)
+			case "sender":
+				return true, v.str(&e.SenderName)
+			}
+			return baseField(&e.Event, &e.Time_, &e.MatchSeconds_, key, v)
+		}
+	case "NEXUS_DESTROYED":
+		e := &event.NexusDestroyed{}
+		return e, func(key string, v *value) (bool, bool) {
+			if key == "nexus" {
+				return true, v.str(&e.Nexus)
+			}
+			return baseField(&e.Event, &e.Time_, &e.MatchSeconds_, key, v)
+		}
+	case "ON_CREATE":
+		e := &event.OnCreate{}
+		return e, objectSetter(&e.NetworkID, &e.SenderName, &e.TeamID, &e.Position, &e.Event, &e.Time_, &e.MatchSeconds_)
+	case "ON_DELETE":
+		e := &event.OnDelete{}
+		return e, objectSetter(&e.NetworkID, &e.SenderName, &e.TeamID, &e.Position, &e.Event, &e.Time_, &e.MatchSeconds_)
+	case "PING":
+		e := &event.Ping{}
+		return e, func(key string, v *value) (bool, bool) {
+			switch key {
+			case "champion_id":
+				return true, v.int(&e.ChampionID)
+			case "dead":
+				return true, v.bool(&e.Dead)
+			case "gold":
+				return true, v.int(&e.Gold)
+			case "health":
+				return true, v.float(&e.Health)
+			case "health_max":
+				return true, v.float(&e.HealthMax)
+			case "in_grass":
+				return true, v.bool(&e.InGrass)
+			case "network_id":
+				return true, v.int(&e.NetworkID)
+			case "hero_index":
+				return true, v.int(&e.HeroIndex)
+			case "level":
+				return true, v.int(&e.Level)
+			case "mana":
+				return true, v.float(&e.Mana)
+			case "mana_max":
+				return true, v.float(&e.ManaMax)
+			case "minions_killed":
+				return true, v.int(&e.MinionsKilled)
+			case "neutral_minions_killed":
+				return true, v.int(&e.NeutralMinionsKilled)
+			case "name":
+				return true, v.str(&e.Name)
+			case "position":
+				return true, v.position(&e.Position)
+			case "q_level":
+				return true, v.int(&e.SlotQLevel)
+			case "q_exp":
+				return true, v.float(&e.SlotQCooldownExp)
+			case "w_level":
+				return true, v.int(&e.SlotWLevel)
+			case "w_exp":
+				return true, v.float(&e.SlotWCooldownExp)
+			case "e_level":
+				return true, v.int(&e.SlotELevel)
+			case "e_exp":
+				return true, v.float(&e.SlotECooldownExp)
+			case "r_level":
+				return true, v.int(&e.SlotRLevel)
+			case "r_exp":
+				return true, v.float(&e.SlotRCooldownExp)
+			case "s1_level":
+				return true, v.int(&e.SlotS1Level)
+			case "s1_exp":
+				return true, v.float(&e.SlotS1CooldownExp)
+			case "s2_level":
+				return true, v.int(&e.SlotS2Level)
+			case "s2_exp":
+				return true, v.float(&e.SlotS2CooldownExp)
+			case "under_turret":
+				return true, v.bool(&e.UnderTurret)
+			case "under_enemy_turret":
+				return true, v.bool(&e.UnderEnemyTurret)
+			}
+			return baseField(&e.Event, &e.Time_, &e.MatchSeconds_, key, v)
+		}
+	case "SPELL_CAST":
+		e := &event.SpellCast{}
+		return e, func(key string, v *value) (bool, bool) {
+			switch key {
+			case "start_position":
+				return true, v.position(&e.PositionStart)
+			case "end_position":
+				return true, v.position(&e.PositionEnd)
+			case "level":
+				return true, v.int(&e.Level)
+			case "name":
+				return true, v.str(&e.SpellName)
+			case "sender":
+				return true, v.str(&e.SenderName)
+			case "network_id":
+				return true, v.int(&e.NetworkID)
+			case "slot":
+				return true, v.str(&e.Slot)
+			case "target":
+				return true, v.str(&e.TargetName)
+			case "target_network_id":
+				return true, v.int(&e.TargetNetworkID)
+			}
+			return baseField(&e.Event, &e.Time_, &e.MatchSeconds_, key, v)
+		}
+	}
+	return nil, nil
+}
+
+// baseField sets the fields common to all events.
+func baseField(eventType *string, time, matchSeconds *float64, key string, v *value) (bool, bool) {
+	switch key {
+	case "event":
+		return true, v.str(eventType)
+	case "time":
+		return true, v.float(time)
+	case "match_seconds":
+		return true, v.float(matchSeconds)
+	}
+	return false, true
+}
+
+func networkIDSetter(networkID *int64, eventType *string, time, matchSeconds *float64) setter {
+	return func(key string, v *value) (bool, bool) {
+		if key == "network_id" {
+			return true, v.int(networkID)
+		}
+		return baseField(eventType, time, matchSeconds, key, v)
+	}
+}
+
+func objectSetter(networkID *int64, sender *string, teamID *int64, pos *baseview.Position, eventType *string, time, matchSeconds *float64) setter {
+	return func(key string, v *value) (bool, bool) {
+		switch key {
+		case "network_id":
+			return true, v.int(networkID)
+		case "sender":
+			return true, v.str(sender)
+		case "team_id":
+			return true, v.int(teamID)
+		case "position":
+			return true, v.position(pos)
+		}
+		return baseField(eventType, time, matchSeconds, key, v)
+	}
+}
+
+// decodeLine parses a line of elo text into its event. ok is false if the line
+// should be parsed with fields and EloEvent instead (see above).
+func decodeLine(line string) (e event.EloEvent, ok bool) {
+	line = strings.TrimSpace(line)
+	numTabs := strings.Count(line, "\t")
+	if numTabs%2 != 0 {
+		return nil, false
+	}
+
+	eventType, rest := nextField(line)
+	e, set := newDecoder(eventType)
+	if e == nil {
+		return nil, false
+	}
+	set("event", &value{kind: stringValue, s: eventType})
+
+	v := value{}
+	for i := 0; i < numTabs/2; i++ {
+		var k, val string
+		k, rest = nextField(rest)
+		val, rest = nextField(rest)
+		if !parseValue(val, &v) {
+			return nil, false
+		}
+
+		// A line that overrides its own event type is left to the general path.
+		key := removeQuotes(k)
+		if key == "event" {
+			return nil, false
+		}
+		known, ok := set(key, &v)
+		if !ok {
+			return nil, false
+		}
+		if !known && !isLowerASCII(key) {
+			// mapstructure matches keys case-insensitively when there is no
+			// exact match.
+			return nil, false
+		}
+	}
+	return e, true
+}
+
+// nextField returns the text before the first tab in s, and the text after it.
+func nextField(s string) (field, rest string) {
+	if i := strings.IndexByte(s, '\t'); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
+// isLowerASCII returns true if s can't case-insensitively match a field name
+// without matching it exactly.
+func isLowerASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 0x80 || ('A' <= c && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseValue is the equivalent of getVal. It returns false if getVal would
+// return an error, or if the value is a position that the positionRE matches in
+// a way we don't handle here.
+func parseValue(s string, v *value) bool {
+	switch {
+	case isInt(s):
+		i, err := strconv.ParseInt(s, 10, 64)
+		v.kind, v.i = intValue, i
+		return err == nil
+	case isFloat(s):
+		f, err := strconv.ParseFloat(s, 64)
+		v.kind, v.f = floatValue, f
+		return err == nil
+	}
+
+	switch strings.ToLower(s) {
+	case "true":
+		v.kind, v.b = boolValue, true
+		return true
+	case "false":
+		v.kind, v.b = boolValue, false
+		return true
+	}
+
+	if strings.HasPrefix(s, "X:") {
+		return parsePosition(s, v)
+	}
+
+	v.kind, v.s = stringValue, removeQuotes(s)
+	return true
+}
+
+// isInt matches intRE.
+func isInt(s string) bool {
+	if strings.HasPrefix(s, "-") {
+		s = s[1:]
+	}
+	return s != "" && isDigits(s)
+}
+
+// isFloat matches floatRE (which, note, matches "" and "-").
+func isFloat(s string) bool {
+	if strings.HasPrefix(s, "-") {
+		s = s[1:]
+	}
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+		if frac == "" {
+			return false
+		}
+	}
+	return isDigits(whole) && isDigits(frac)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePosition parses a value that starts with "X:". Only the unambiguous
+// form, with a single " Y:" and " Z:", is handled here.
+func parsePosition(s string, v *value) bool {
+	if strings.Count(s, " Y:") != 1 || strings.Count(s, " Z:") != 1 || strings.ContainsRune(s, '\n') {
+		return false
+	}
+	yi := strings.Index(s, " Y:")
+	zi := strings.Index(s, " Z:")
+	if yi <= 2 || zi <= yi+3 || zi+3 >= len(s) {
+		return false
+	}
+
+	var err error
+	if v.pos.X, err = strconv.ParseFloat(s[2:yi], 64); err != nil {
+		return false
+	}
+	if v.pos.Y, err = strconv.ParseFloat(s[yi+3:zi], 64); err != nil {
+		return false
+	}
+	if v.pos.Z, err = strconv.ParseFloat(s[zi+3:], 64); err != nil {
+		return false
+	}
+	v.kind = positionValue
+	return true
+}
+
+// The conversions below are the ones mapstructure makes (without weakly typed
+// input) from the types getVal returns.
+
+func (v *value) int(dst *int64) bool {
+	switch v.kind {
+	case intValue:
+		*dst = v.i
+	case floatValue:
+		*dst = int64(v.f)
+	default:
+		return false
+	}
+	return true
+}
+
+func (v *value) float(dst *float64) bool {
+	switch v.kind {
+	case intValue:
+		*dst = float64(v.i)
+	case floatValue:
+		*dst = v.f
+	default:
+		return false
+	}
+	return true
+}
+
+func (v *value) bool(dst *bool) bool {
+	if v.kind != boolValue {
+		return false
+	}
+	*dst = v.b
+	return true
+}
+
+func (v *value) str(dst *string) bool {
+	if v.kind != stringValue {
+		return false
+	}
+	*dst = v.s
+	return true
+}
+
+func (v *value) position(dst *baseview.Position) bool {
+	if v.kind != positionValue {
+		return false
+	}
+	*dst = v.pos
+	return true
+}
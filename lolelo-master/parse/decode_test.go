@@ -0,0 +1,123 @@
+package parse
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VantageSports/lolelo/event"
+)
+
+// decodeLines are representative lines of each event type the fast decoders
+// handle, as written by DataSpectator.
+var decodeLines = []string{
+	"BASIC_ATTACK\ttime\t101.25\tsender\t\"hero1\"\tnetwork_id\t1073741863\ttarget\tSRU_Razorbeak3.1.1\ttarget_network_id\t1073742012\ttarget_position\tX:6920.5 Y:5402.1 Z:51.2\t",
+	"CHAMP_DIE\ttime\t400.5\tnetwork_id\t1073741863\t",
+	"CHAMP_KILL\ttime\t400.5\tnetwork_id\t1073741865\t",
+	"DAMAGE\ttime\t2.25\tsender\tTurret_T1_L_03_A\tnetwork_id\t4287283748\tdamage\t152.5\ttarget\t\"hero1\"\ttarget_network_id\t1073741863\ttype\tPhysical\t",
+	"DIE\ttime\t90\tnetwork_id\t1073742012\t",
+	"GAME_END\ttime\t1800.75\t",
+	"ID_HERO\tname\t\"hero1\"\tnetwork_id\t1073741863\t",
+	"ID_TURRET\tname\tTurret_T1_L_03_A\tnetwork_id\t4287283748\t",
+	"ID_BARRACKS\tname\tBarracks_T1_L1\tnetwork_id\t4294954510\t",
+	"KILL\ttime\t90\tnetwork_id\t1073741863\t",
+	"LEVEL_UP\ttime\t125.5\tlevel\t2\tnetwork_id\t1073741863\tsender\t\"hero1\"\t",
+	"NEXUS_DESTROYED\ttime\t1800\tnexus\tHQ_T2\t",
+	"ON_CREATE\ttime\t300.5\tnetwork_id\t1073745000\tsender\tSightWard\tteam_id\t100\tposition\tX:7000 Y:3000 Z:52.1\t",
+	"ON_DELETE\ttime\t480.5\tnetwork_id\t1073745000\tsender\tSightWard\tteam_id\t100\tposition\tX:7000 Y:3000 Z:52.1\t",
+	"PING\ttime\t1.5\tindex\t1\tnetwork_id\t1073741863\tname\t\"hero1\"\tchampion_id\t103\tlevel\t1\tposition\tX:560 Y:580 Z:183\tgold\t500\thealth\t526.44\thealth_max\t526.44\tmana\t418\tmana_max\t418\tdead\tfalse\tin_grass\tFalse\tunder_turret\tTrue\tunder_enemy_turret\tfalse\tminions_killed\t0\tneutral_minions_killed\t0\thero_index\t0\tq_level\t1\tq_exp\t0\tw_level\t0\tw_exp\t0\te_level\t0\te_exp\t0\tr_level\t0\tr_exp\t0\ts1_level\t1\ts1_exp\t0\ts2_level\t1\ts2_exp\t0\t",
+	"SPELL_CAST\ttime\t350.25\tname\tTrinketTotemLvl1\tsender\t\"hero1\"\tnetwork_id\t1073741863\tslot\tTrinket\tlevel\t1\tstart_position\tX:7000 Y:3000 Z:52.1\tend_position\tX:7100 Y:3050 Z:52.1\ttarget\t\"hero2\"\ttarget_network_id\t1073741865\t",
+}
+
+// generalDecode parses a line the way the fast decoders replace.
+func generalDecode(line string) (event.EloEvent, error) {
+	m, err := fields(line)
+	if err != nil {
+		return nil, err
+	}
+	return EloEvent(m)
+}
+
+func TestDecodeLine(t *testing.T) {
+	for _, line := range decodeLines {
+		expected, err := generalDecode(line)
+		if err != nil {
+			t.Fatalf("%q: %v", line, err)
+		}
+		actual, ok := decodeLine(line)
+		if !ok {
+			t.Fatalf("%q: not decoded", line)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("%q:\nexpected %+v\nactual   %+v", line, expected, actual)
+		}
+	}
+
+	// Lines the fast decoders leave to the general path.
+	for _, line := range []string{
+		"",
+		"END_GAME\ttime\t2.1",
+		"CHAMP_LEVEL_UP\ttime\t3.5\tlevel\t2",
+		"PING\ttime",
+		"PING\ttime\t1.5\tgold\tlots",
+		"PING\ttime\t1.5\tGold\t500",
+		"PING\ttime\t1.5\tevent\tDAMAGE",
+		"PING\ttime\t-",
+		"PING\ttime\t99999999999999999999",
+		"PING\tposition\tX:1 Y:2 Y:3 Z:4",
+	} {
+		if _, ok := decodeLine(line); ok {
+			t.Errorf("%q: unexpectedly decoded", line)
+		}
+	}
+}
+
+// FuzzDecodeLine checks that whenever the fast decoders decode a line, the
+// general path decodes it (without error) to the same event.
+func FuzzDecodeLine(f *testing.F) {
+	for _, line := range decodeLines {
+		f.Add(line)
+	}
+	f.Add("PING\ttime\t1.\tgold\t-.5\tposition\tX:1 Y:2 Z:\"3\"")
+	f.Add("DAMAGE\tTIME\t2\tdamage\t7\ttype\ttrue")
+
+	f.Fuzz(func(t *testing.T, line string) {
+		if strings.ContainsAny(line, "\n\r") {
+			// not possible for a line read by a scanner.
+			return
+		}
+		actual, ok := decodeLine(line)
+		if !ok {
+			return
+		}
+		expected, err := generalDecode(line)
+		if err != nil {
+			t.Fatalf("%q: decoded, but general path failed: %v", line, err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("%q:\nexpected %+v\nactual   %+v", line, expected, actual)
+		}
+	})
+}
+
+func BenchmarkDecodeLine(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, line := range decodeLines {
+			if _, ok := decodeLine(line); !ok {
+				b.Fatal(line)
+			}
+		}
+	}
+}
+
+func BenchmarkGeneralDecode(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, line := range decodeLines {
+			if _, err := generalDecode(line); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
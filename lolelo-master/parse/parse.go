@@ -6,7 +6,7 @@ package parse
 import (
 	"bufio"
 	"fmt"
-	"os"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
@@ -15,6 +15,7 @@ import (
 	"github.com/VantageSports/lolelo/event"
 
 	"github.com/mitchellh/mapstructure"
+	"golang.org/x/net/context"
 )
 
 var intRE = regexp.MustCompile(`^-?[0-9]+$`)
@@ -23,36 +24,41 @@ var positionRE = regexp.MustCompile(`^X:(.+) Y:(.+) Z:(.+)$`)
 var quotedStringRE = regexp.MustCompile(`^"([^"]*)"$`)
 
 // LogFile parses an array of event.EloEvent from the text file at the specified
-// path. It does so in two steps.
-// 1) it iterates through all lines looking for mappings from network id -> name
-// 2) it uses that mapping to parse events.
+// path. The file may be gzip-compressed. Callers that don't need every event in
+// memory at once should prefer OpenEventReader.
 func LogFile(filepath string) ([]event.EloEvent, error) {
+	events, _, err := logFile(filepath, false)
+	return events, err
+}
+
+// LogFileLenient is like LogFile, but skips lines that cannot be parsed rather
+// than failing. The returned report describes the skipped lines.
+func LogFileLenient(filepath string) ([]event.EloEvent, *Report, error) {
+	return logFile(filepath, true)
+}
+
+func logFile(filepath string, lenient bool) ([]event.EloEvent, *Report, error) {
 	events := []event.EloEvent{}
 
-	it, err := NewFieldsIterator(filepath)
-	defer it.Close()
+	r, err := OpenEventReader(context.Background(), filepath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	defer r.Close()
+	r.Lenient = lenient
 
 	for {
-		lineNum, m, err := it.Next()
-		if err != nil {
-			return nil, err
-		}
-		if lineNum < 0 {
-			break // EOF
+		e, err := r.Next()
+		if err == io.EOF {
+			break
 		}
-		event, err := EloEvent(m)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing event from line %d: %v", lineNum, err)
-		}
-		if event != nil {
-			events = append(events, event)
+			return nil, r.Report(), err
 		}
+		events = append(events, e)
 	}
 
-	return events, nil
+	return events, r.Report(), nil
 }
 
 // fields returns a map containing the fields in a elogen line.
@@ -106,8 +112,12 @@ func EloEvent(m map[string]interface{}) (event.EloEvent, error) {
 		e = &event.Damage{}
 	case "DIE":
 		e = &event.Die{}
+	case "EPIC_MONSTER_KILL":
+		e = &event.EpicMonsterKill{}
 	case "GAME_END":
 		e = &event.GameEnd{}
+	case "GAME_STALL":
+		e = &event.GameStall{}
 	case "ID_BARRACKS", "ID_HERO", "ID_TURRET":
 		e = &event.NetworkIDMapping{EloType: eventTypeStr}
 	case "KILL":
@@ -124,11 +134,15 @@ func EloEvent(m map[string]interface{}) (event.EloEvent, error) {
 		e = &event.Ping{}
 	case "SPELL_CAST":
 		e = &event.SpellCast{}
-	case "END_GAME", "GAME_STALL", "DAMPENER_RESPAWN", "DAMPENER_RESPAWN_SOON", "SURRENDER_AGREED":
+	case "SURRENDER_AGREED":
+		e = &event.SurrenderAgreed{}
+	case "END_GAME", "DAMPENER_RESPAWN", "DAMPENER_RESPAWN_SOON":
 		// Do nothing
 		return nil, nil
+	case "":
+		return nil, fmt.Errorf("empty event type for event")
 	default:
-		return nil, fmt.Errorf("unepected event type (%s) for event", eventTypeStr)
+		return nil, &UnknownEventTypeError{EventType: eventTypeStr}
 	}
 
 	err := mapstructure.Decode(m, e)
@@ -136,7 +150,7 @@ func EloEvent(m map[string]interface{}) (event.EloEvent, error) {
 }
 
 func removeQuotes(s string) string {
-	if strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+	if len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
 		return s[1 : len(s)-1]
 	}
 	return s
@@ -192,37 +206,43 @@ func parseLocation(strs []string) (map[string]interface{}, error) {
 // i/o utility
 //
 
+// maxLineBytes is the longest line we expect in an elo log. bufio.Scanner's
+// default (64KB) is plenty for today's events, but we leave headroom so that a
+// long line is reported as a parse error rather than silently ending the scan.
+const maxLineBytes = 1024 * 1024
+
 // lineFieldIterator reads lines of elotext, composes generic json maps from
 // the key/value pairs, and returns one (with the line number it came from)
 // with each call to Next()
 type lineFieldIterator struct {
-	fileHandle *os.File
-	scanner    *bufio.Scanner
-	lineNum    int
+	scanner *bufio.Scanner
+	lineNum int
 }
 
-func NewFieldsIterator(filepath string) (*lineFieldIterator, error) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		return nil, err
-	}
-
+func NewFieldsIterator(r io.Reader) *lineFieldIterator {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
 	return &lineFieldIterator{
-		fileHandle: f,
-		scanner:    bufio.NewScanner(f),
-		lineNum:    0,
-	}, nil
+		scanner: scanner,
+		lineNum: 0,
+	}
 }
 
 func (i *lineFieldIterator) Next() (int, map[string]interface{}, error) {
-	if !i.scanner.Scan() {
-		return -1, nil, i.scanner.Err()
+	lineNum, line, err := i.nextLine()
+	if lineNum < 0 {
+		return lineNum, nil, err
 	}
-	i.lineNum++
-	m, err := fields(i.scanner.Text())
-	return i.lineNum, m, err
+	m, err := fields(line)
+	return lineNum, m, err
 }
 
-func (i *lineFieldIterator) Close() error {
-	return i.fileHandle.Close()
+// nextLine returns the next line (and its number) without parsing it, or -1 at
+// the end of the input.
+func (i *lineFieldIterator) nextLine() (int, string, error) {
+	if !i.scanner.Scan() {
+		return -1, "", i.scanner.Err()
+	}
+	i.lineNum++
+	return i.lineNum, i.scanner.Text(), nil
 }
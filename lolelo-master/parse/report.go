@@ -0,0 +1,117 @@
+package parse
+
+import (
+	"fmt"
+	"sort"
+)
+
+// maxSamples is the number of example lines kept for each kind of problem.
+const maxSamples = 5
+
+// maxSampleLen is the longest sample line (in bytes) kept in a report.
+const maxSampleLen = 500
+
+// UnknownEventTypeError is returned when a line's event type is not one we
+// know how to parse (or know to ignore).
+type UnknownEventTypeError struct {
+	EventType string
+}
+
+func (u *UnknownEventTypeError) Error() string {
+	return fmt.Sprintf("unepected event type (%s) for event", u.EventType)
+}
+
+// Report describes the outcome of parsing an elo log: how many lines were
+// read, and which were skipped or could not be parsed. It is useful for
+// spotting changes in the log format after a game patch.
+type Report struct {
+	LinesRead     int            `json:"lines_read"`
+	EventsParsed  int            `json:"events_parsed"`
+	EventsByType  map[string]int `json:"events_by_type"`
+	IgnoredByType map[string]int `json:"ignored_by_type"`
+
+	// MalformedLines are lines whose fields or values could not be parsed.
+	MalformedLines int      `json:"malformed_lines"`
+	Malformed      []Sample `json:"malformed,omitempty"`
+
+	// UnknownTypes are event types we didn't recognize, by type.
+	UnknownTypes map[string]*UnknownType `json:"unknown_types,omitempty"`
+}
+
+// UnknownType describes all occurrences of an unrecognized event type.
+type UnknownType struct {
+	Count     int      `json:"count"`
+	FirstLine int      `json:"first_line"`
+	Samples   []Sample `json:"samples"`
+}
+
+// Sample is a single problematic line of an elo log.
+type Sample struct {
+	Line  int    `json:"line"`
+	Text  string `json:"text"`
+	Error string `json:"error"`
+}
+
+// NewReport returns an empty report.
+func NewReport() *Report {
+	return &Report{
+		EventsByType:  map[string]int{},
+		IgnoredByType: map[string]int{},
+		UnknownTypes:  map[string]*UnknownType{},
+	}
+}
+
+// Skipped returns the total number of lines that were neither parsed into an
+// event nor intentionally ignored.
+func (r *Report) Skipped() int {
+	skipped := r.MalformedLines
+	for _, u := range r.UnknownTypes {
+		skipped += u.Count
+	}
+	return skipped
+}
+
+// UnknownTypeNames returns the sorted names of all unknown event types.
+func (r *Report) UnknownTypeNames() []string {
+	names := []string{}
+	for name := range r.UnknownTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *Report) addEvent(eventType string) {
+	r.EventsParsed++
+	r.EventsByType[eventType]++
+}
+
+func (r *Report) addIgnored(eventType string) {
+	r.IgnoredByType[eventType]++
+}
+
+func (r *Report) addUnknown(eventType string, line int, text string, err error) {
+	u := r.UnknownTypes[eventType]
+	if u == nil {
+		u = &UnknownType{FirstLine: line}
+		r.UnknownTypes[eventType] = u
+	}
+	u.Count++
+	if len(u.Samples) < maxSamples {
+		u.Samples = append(u.Samples, newSample(line, text, err))
+	}
+}
+
+func (r *Report) addMalformed(line int, text string, err error) {
+	r.MalformedLines++
+	if len(r.Malformed) < maxSamples {
+		r.Malformed = append(r.Malformed, newSample(line, text, err))
+	}
+}
+
+func newSample(line int, text string, err error) Sample {
+	if len(text) > maxSampleLen {
+		text = text[:maxSampleLen]
+	}
+	return Sample{Line: line, Text: text, Error: err.Error()}
+}
@@ -0,0 +1,50 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+	"gopkg.in/tylerb/is.v1"
+)
+
+func TestLenientReport(t *testing.T) {
+	is := is.New(t)
+
+	log := sampleLog +
+		"CHAMP_LEVEL_UP\ttime\t3.5\tlevel\t2\t\n" +
+		"PING\ttime\n" +
+		"CHAMP_LEVEL_UP\ttime\t4.5\tlevel\t3\t\n"
+
+	// Strict readers fail on the first unknown event type.
+	r, err := NewEventReader(context.Background(), strings.NewReader(log))
+	is.NotErr(err)
+	for err == nil {
+		_, err = r.Next()
+	}
+	is.True(strings.Contains(err.Error(), "line 7"))
+
+	r, err = NewEventReader(context.Background(), strings.NewReader(log))
+	is.NotErr(err)
+	r.Lenient = true
+	events := readAll(is, r)
+	is.Equal(5, len(events))
+
+	report := r.Report()
+	is.Equal(9, report.LinesRead)
+	is.Equal(5, report.EventsParsed)
+	is.Equal(1, report.EventsByType["DAMAGE"])
+	is.Equal(1, report.IgnoredByType["END_GAME"])
+	is.Equal(3, report.Skipped())
+
+	is.Equal(1, report.MalformedLines)
+	is.Equal(8, report.Malformed[0].Line)
+	is.Equal("PING\ttime", report.Malformed[0].Text)
+
+	is.Equal([]string{"CHAMP_LEVEL_UP"}, report.UnknownTypeNames())
+	unknown := report.UnknownTypes["CHAMP_LEVEL_UP"]
+	is.Equal(2, unknown.Count)
+	is.Equal(7, unknown.FirstLine)
+	is.Equal(2, len(unknown.Samples))
+	is.Equal(9, unknown.Samples[1].Line)
+}
@@ -0,0 +1,167 @@
+package parse
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/VantageSports/lolelo/event"
+
+	"golang.org/x/net/context"
+)
+
+// gzipMagic are the first two bytes of every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// EventReader parses elo events from an elo text log one at a time, so that a
+// full game never needs to be held in memory. It implements event.Iterator.
+type EventReader struct {
+	// Lenient readers skip lines that cannot be parsed (recording them in the
+	// report) rather than returning an error.
+	Lenient bool
+
+	ctx    context.Context
+	fields *lineFieldIterator
+	closer io.Closer
+	report *Report
+}
+
+// NewEventReader returns a reader of the elo text log in r, which may be gzip
+// compressed (detected by content, not file name). Next returns the context's
+// error once ctx is done.
+func NewEventReader(ctx context.Context, r io.Reader) (*EventReader, error) {
+	decompressed, err := Decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	er := &EventReader{
+		ctx:    ctx,
+		fields: NewFieldsIterator(decompressed),
+		report: NewReport(),
+	}
+	if c, ok := decompressed.(io.Closer); ok {
+		er.closer = c
+	}
+	return er, nil
+}
+
+// OpenEventReader returns a reader of the (possibly gzip-compressed) elo text
+// log at the local path. Callers must Close it when done.
+func OpenEventReader(ctx context.Context, filepath string) (*EventReader, error) {
+	f, err := os.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := NewEventReader(ctx, f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r.closer = multiCloser{r.closer, f}
+	return r, nil
+}
+
+// Next returns the next event in the log, skipping event types that we
+// intentionally ignore. It returns io.EOF at the end of the log.
+func (r *EventReader) Next() (event.EloEvent, error) {
+	for {
+		if err := r.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		lineNum, line, err := r.fields.nextLine()
+		if err != nil {
+			return nil, fmt.Errorf("error reading after line %d: %v", r.Line(), err)
+		}
+		if lineNum < 0 {
+			return nil, io.EOF
+		}
+		r.report.LinesRead++
+
+		if e, ok := decodeLine(line); ok {
+			r.report.addEvent(e.Type())
+			return e, nil
+		}
+
+		// Fall back to the general (slower) path, which handles everything the
+		// fast decoders don't, and describes any errors.
+		m, err := fields(line)
+		if err != nil {
+			if !r.Lenient {
+				return nil, fmt.Errorf("error parsing line %d: %v", lineNum, err)
+			}
+			r.report.addMalformed(lineNum, line, err)
+			continue
+		}
+
+		e, err := EloEvent(m)
+		if err != nil {
+			if !r.Lenient {
+				return nil, fmt.Errorf("error parsing event from line %d: %v", lineNum, err)
+			}
+			if u, ok := err.(*UnknownEventTypeError); ok {
+				r.report.addUnknown(u.EventType, lineNum, line, err)
+			} else {
+				r.report.addMalformed(lineNum, line, err)
+			}
+			continue
+		}
+		if e == nil {
+			r.report.addIgnored(fmt.Sprint(m["event"]))
+			continue
+		}
+		r.report.addEvent(e.Type())
+		return e, nil
+	}
+}
+
+// Report returns the report of all lines read so far.
+func (r *EventReader) Report() *Report {
+	return r.report
+}
+
+// Line returns the number of the last line read.
+func (r *EventReader) Line() int {
+	return r.fields.lineNum
+}
+
+// Close releases the underlying decompressor and file (if any).
+func (r *EventReader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// Decompress returns a reader of the uncompressed contents of r. If r does not
+// begin with the gzip magic bytes, its contents are returned unchanged.
+func Decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(head) == len(gzipMagic) && head[0] == gzipMagic[0] && head[1] == gzipMagic[1] {
+		return gzip.NewReader(br)
+	}
+	return br, nil
+}
+
+// multiCloser closes each non-nil closer in order, returning the first error.
+type multiCloser []io.Closer
+
+func (m multiCloser) Close() error {
+	var first error
+	for _, c := range m {
+		if c == nil {
+			continue
+		}
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,85 @@
+package parse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/VantageSports/lolelo/event"
+
+	"golang.org/x/net/context"
+	"gopkg.in/tylerb/is.v1"
+)
+
+const sampleLog = "ID_HERO\tname\t\"hero1\"\tnetwork_id\t1073741863\t\n" +
+	"ID_TURRET\tname\tTurret_T1_L_03_A\tnetwork_id\t4287283748\t\n" +
+	"PING\ttime\t1.5\tindex\t1\tnetwork_id\t1073741863\tname\t\"hero1\"\tlevel\t1\tposition\tX:560 Y:580 Z:183\tgold\t500\tdead\tfalse\t\n" +
+	"END_GAME\ttime\t2.1\t\n" +
+	"DAMAGE\ttime\t2.25\tsender\tTurret_T1_L_03_A\tnetwork_id\t4287283748\tdamage\t152.5\ttarget\t\"hero1\"\ttarget_network_id\t1073741863\ttype\tPhysical\t\n" +
+	"GAME_END\ttime\t3\t\n"
+
+func readAll(is *is.Is, r *EventReader) []event.EloEvent {
+	events := []event.EloEvent{}
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		is.NotErr(err)
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestEventReader(t *testing.T) {
+	is := is.New(t)
+
+	r, err := NewEventReader(context.Background(), strings.NewReader(sampleLog))
+	is.NotErr(err)
+	plain := readAll(is, r)
+	is.Equal(5, len(plain)) // END_GAME is ignored
+	is.Equal(6, r.Line())
+
+	ping, ok := plain[2].(*event.Ping)
+	is.True(ok)
+	is.Equal(int64(500), ping.Gold)
+	is.Equal(560.0, ping.Position.X)
+
+	damage, ok := plain[3].(*event.Damage)
+	is.True(ok)
+	is.Equal(152.5, damage.Damage)
+
+	compressed := &bytes.Buffer{}
+	gz := gzip.NewWriter(compressed)
+	_, err = gz.Write([]byte(sampleLog))
+	is.NotErr(err)
+	is.NotErr(gz.Close())
+
+	r, err = NewEventReader(context.Background(), compressed)
+	is.NotErr(err)
+	is.Equal(plain, readAll(is, r))
+	is.NotErr(r.Close())
+}
+
+func TestEventReaderErrors(t *testing.T) {
+	is := is.New(t)
+
+	bad := sampleLog + "PING\ttime\n"
+	r, err := NewEventReader(context.Background(), strings.NewReader(bad))
+	is.NotErr(err)
+	for err == nil {
+		_, err = r.Next()
+	}
+	is.True(strings.Contains(err.Error(), "line 7"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r, err = NewEventReader(ctx, strings.NewReader(sampleLog))
+	is.NotErr(err)
+	_, err = r.Next()
+	is.NotErr(err)
+	cancel()
+	_, err = r.Next()
+	is.Equal(context.Canceled, err)
+}
@@ -0,0 +1,580 @@
+// Package synth builds riot api style match details from elo logs, for matches
+// whose riot api record is missing, so that basic stats and history can still
+// be generated for them.
+
+package synth
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/VantageSports/common/log"
+	"github.com/VantageSports/lolelo/event"
+	"github.com/VantageSports/lolelo/validate"
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/riot/api"
+)
+
+// assistSeconds is how long before a death that damage to the victim counts
+// towards an assist.
+const assistSeconds = 10.0
+
+// multiKillSeconds is the longest time between kills of a multikill (except
+// the last kill of a pentakill, which may take up to pentaKillSeconds).
+const (
+	multiKillSeconds = 10.0
+	pentaKillSeconds = 30.0
+)
+
+// Options are the details of a match that can't be found in its elo log.
+type Options struct {
+	MatchID       int64
+	PlatformID    string
+	MatchCreation int64 // epoch milliseconds
+	MatchVersion  string
+
+	// BlueSummonerID and RedSummonerID are the summoner ids assigned to the
+	// first participant of each team. The rest of the team are numbered
+	// consecutively.
+	BlueSummonerID int64
+	RedSummonerID  int64
+}
+
+// kill is the death of a champion.
+type kill struct {
+	Seconds  float64
+	KillerID int // 0 if not killed by a champion
+	VictimID int
+	Assists  []int
+	Position baseview.Position
+}
+
+// buildingKill is the destruction of a turret or inhibitor.
+type buildingKill struct {
+	Seconds      float64
+	KillerID     int // 0 if not killed by a champion
+	TeamID       int // the team the building belonged to
+	BuildingType string
+}
+
+// MatchDetail returns the match details of a full game of elo events, along
+// with the baseview converted from them. The match seconds of the events are
+// set to their elo times, since there is nothing else to align them with.
+//
+// Stats that elo logs don't capture (items, healing, damage to anything other
+// than champions, etc) are left empty.
+func MatchDetail(events []event.EloEvent, opts Options) (*api.MatchDetail, *baseview.Baseview, error) {
+	md := newMatchDetail(opts)
+	s := newSynthesizer(md, opts)
+	for _, e := range events {
+		s.add(e)
+	}
+	if len(md.Participants) == 0 {
+		return nil, nil, fmt.Errorf("no heroes found in elo log")
+	}
+	s.finish()
+
+	// Have to go full circle. Use the partial match details to generate the
+	// baseview, then use the baseview for everything the elo events don't
+	// make easy.
+	validate.UpdateMatchSeconds(events, 0.0)
+	participants, err := validate.ParticipantMapMD(events, md)
+	if err != nil {
+		return nil, nil, err
+	}
+	bv, stats, err := event.EloToBaseview(events, participants)
+	if err != nil {
+		return nil, nil, err
+	}
+	if unresolved := stats.TotalUnresolved(); unresolved > 0 {
+		log.Warning(fmt.Sprintf("dropped %d of %d elo events with unresolved network ids (%d late mappings)",
+			unresolved, stats.Events, stats.LateMappings))
+	}
+
+	addBaseviewStats(md, bv)
+	md.Timeline = timeline(bv, s.kills, s.buildingKills, md.MatchDuration)
+	addTimelineDeltas(md)
+
+	return md, bv, nil
+}
+
+func newMatchDetail(opts Options) *api.MatchDetail {
+	return &api.MatchDetail{
+		MapID:                 11,
+		MatchCreation:         opts.MatchCreation,
+		MatchID:               opts.MatchID,
+		MatchMode:             "CLASSIC",
+		MatchType:             "CUSTOM_GAME",
+		MatchVersion:          opts.MatchVersion,
+		ParticipantIdentities: []api.ParticipantIdentity{},
+		Participants:          []api.Participant{},
+		PlatformID:            opts.PlatformID,
+		QueueType:             "CUSTOM",
+		Teams: []api.Team{
+			{TeamID: int(baseview.BlueTeam)},
+			{TeamID: int(baseview.RedTeam)},
+		},
+	}
+}
+
+// synthesizer accumulates the participant and team stats of a match as its
+// elo events are added.
+type synthesizer struct {
+	md   *api.MatchDetail
+	opts Options
+
+	// participant ids by hero network id, and the teams of the buildings by
+	// building network id.
+	heroes    map[int64]int
+	buildings map[int64]building
+
+	// the last KILL event, which names the killer of the DIE that follows.
+	lastKiller     int64
+	lastKillerTime float64
+
+	// when each champion (by participant id) was last damaged by each other
+	// champion, to find assists.
+	lastDamaged map[int]map[int]float64
+
+	lastPosition map[int]baseview.Position
+	goldCurrent  map[int]int64
+	goldSpent    map[int]int64
+
+	// kills since each champion's last death, and the size and time of the
+	// last kill of their current multikill.
+	spree     map[int]int64
+	multiKill map[int]int64
+	lastKill  map[int]float64
+
+	kills         []kill
+	buildingKills []buildingKill
+
+	nexusDestroyed string
+	surrendered    int // the team that surrendered
+
+	// the time of the latest event, which ends the game if the log has no
+	// GAME_END.
+	lastSeconds float64
+}
+
+// building is a turret or inhibitor.
+type building struct {
+	TeamID int
+	Type   string
+}
+
+func newSynthesizer(md *api.MatchDetail, opts Options) *synthesizer {
+	return &synthesizer{
+		md:           md,
+		opts:         opts,
+		heroes:       map[int64]int{},
+		buildings:    map[int64]building{},
+		lastDamaged:  map[int]map[int]float64{},
+		lastPosition: map[int]baseview.Position{},
+		goldCurrent:  map[int]int64{},
+		goldSpent:    map[int]int64{},
+		spree:        map[int]int64{},
+		multiKill:    map[int]int64{},
+		lastKill:     map[int]float64{},
+		kills:        []kill{},
+	}
+}
+
+// participant returns the participant with the (1-based) id.
+func (s *synthesizer) participant(pID int) *api.Participant {
+	return &s.md.Participants[pID-1]
+}
+
+func (s *synthesizer) add(e event.EloEvent) {
+	s.lastSeconds = math.Max(s.lastSeconds, e.Time())
+
+	switch t := e.(type) {
+	case *event.NetworkIDMapping:
+		switch t.EloType {
+		case "ID_HERO":
+			s.addHero(t.SenderName, t.NetworkID)
+		case "ID_TURRET", "ID_BARRACKS":
+			if b, ok := buildingFromName(t.SenderName); ok {
+				s.buildings[t.NetworkID] = b
+			}
+		}
+
+	case *event.GameEnd:
+		s.md.MatchDuration = int64(t.Time())
+
+	case *event.NexusDestroyed:
+		s.nexusDestroyed = t.Nexus
+
+	case *event.SurrenderAgreed:
+		if pID, ok := s.heroes[t.NetworkID]; ok {
+			s.surrendered = int(baseview.TeamID(int64(pID)))
+		}
+
+	case *event.SpellCast:
+		pID, ok := s.heroes[t.NetworkID]
+		if !ok {
+			break
+		}
+		p := s.participant(pID)
+		switch {
+		case t.Slot == "Q" && p.ChampionID == 0:
+			p.ChampionID = QToChampID(t.SpellName)
+		case t.Slot == "Summoner1" && p.Spell1ID == 0:
+			p.Spell1ID = SummonerSpellID(t.SpellName)
+		case t.Slot == "Summoner2" && p.Spell2ID == 0:
+			p.Spell2ID = SummonerSpellID(t.SpellName)
+		}
+
+	case *event.Damage:
+		s.addDamage(t)
+
+	case *event.Kill:
+		s.lastKiller, s.lastKillerTime = t.NetworkID, t.Time()
+
+	case *event.Die:
+		killer := 0
+		if s.lastKillerTime == t.Time() {
+			killer = s.heroes[s.lastKiller]
+		}
+		if pID, ok := s.heroes[t.NetworkID]; ok {
+			s.addKill(t.Time(), killer, pID)
+		} else if b, ok := s.buildings[t.NetworkID]; ok {
+			s.buildingKills = append(s.buildingKills, buildingKill{
+				Seconds:      t.Time(),
+				KillerID:     killer,
+				TeamID:       b.TeamID,
+				BuildingType: b.Type,
+			})
+		}
+
+	case *event.Ping:
+		pID, ok := s.heroes[t.NetworkID]
+		if !ok {
+			break
+		}
+		p := s.participant(pID)
+		if t.ChampionID != 0 {
+			p.ChampionID = int(t.ChampionID)
+		}
+		if t.Level > p.Stats.ChampLevel {
+			p.Stats.ChampLevel = t.Level
+		}
+		if t.Gold < s.goldCurrent[pID] {
+			s.goldSpent[pID] += s.goldCurrent[pID] - t.Gold
+		}
+		s.goldCurrent[pID] = t.Gold
+		s.lastPosition[pID] = t.Position
+
+		p.Stats.MinionsKilled = t.MinionsKilled
+		p.Stats.NeutralMinionsKilled = t.NeutralMinionsKilled
+	}
+}
+
+// addHero adds the next participant (up to ten), in the order the heroes are
+// identified in the log.
+func (s *synthesizer) addHero(name string, networkID int64) {
+	pID := len(s.md.Participants) + 1
+	if pID > 10 {
+		return
+	}
+	summonerID := s.opts.BlueSummonerID + int64(pID-1)
+	if pID > 5 {
+		summonerID = s.opts.RedSummonerID + int64(pID-6)
+	}
+
+	s.md.ParticipantIdentities = append(s.md.ParticipantIdentities, api.ParticipantIdentity{
+		ParticipantID: pID,
+		Player: api.Player{
+			SummonerID:   summonerID,
+			SummonerName: name,
+		},
+	})
+	s.md.Participants = append(s.md.Participants, api.Participant{
+		ParticipantID: pID,
+		TeamID:        int(baseview.TeamID(int64(pID))),
+		Stats:         api.ParticipantStats{ChampLevel: 1},
+	})
+	s.heroes[networkID] = pID
+}
+
+// addDamage adds champion damage (the only damage elo logs record) to the
+// attacker's damage dealt and the victim's damage taken.
+func (s *synthesizer) addDamage(d *event.Damage) {
+	victimID, ok := s.heroes[d.TargetNetworkID]
+	if !ok {
+		return
+	}
+	amount := int64(d.Damage)
+	kind := damageKind(d.DamageType)
+
+	victim := &s.participant(victimID).Stats
+	victim.TotalDamageTaken += amount
+	switch kind {
+	case physicalDamage:
+		victim.PhysicalDamageTaken += amount
+	case magicDamage:
+		victim.MagicDamageTaken += amount
+	case trueDamage:
+		victim.TrueDamageTaken += amount
+	}
+
+	attackerID, ok := s.heroes[d.NetworkID]
+	if !ok {
+		return
+	}
+	// elo logs only damage to champions, so damage dealt is the same as
+	// damage dealt to champions.
+	attacker := &s.participant(attackerID).Stats
+	attacker.TotalDamageDealt += amount
+	attacker.TotalDamageDealtToChampions += amount
+	switch kind {
+	case physicalDamage:
+		attacker.PhysicalDamageDealt += amount
+		attacker.PhysicalDamageDealtToChampions += amount
+	case magicDamage:
+		attacker.MagicDamageDealt += amount
+		attacker.MagicDamageDealtToChanpions += amount
+	case trueDamage:
+		attacker.TrueDamageDealt += amount
+		attacker.TrueDamageDealtToChampions += amount
+	}
+
+	if s.lastDamaged[victimID] == nil {
+		s.lastDamaged[victimID] = map[int]float64{}
+	}
+	s.lastDamaged[victimID][attackerID] = d.Time()
+}
+
+// addKill adds the death of the victim, and the kill (if any) and assists.
+func (s *synthesizer) addKill(seconds float64, killerID, victimID int) {
+	s.participant(victimID).Stats.Deaths++
+	s.spree[victimID] = 0
+
+	k := kill{
+		Seconds:  seconds,
+		KillerID: killerID,
+		VictimID: victimID,
+		Assists:  []int{},
+		Position: s.lastPosition[victimID],
+	}
+	killerTeam := baseview.EnemyTeamID(int64(victimID))
+	for attackerID, t := range s.lastDamaged[victimID] {
+		if attackerID != killerID && seconds-t <= assistSeconds && baseview.TeamID(int64(attackerID)) == killerTeam {
+			k.Assists = append(k.Assists, attackerID)
+		}
+	}
+	sort.Ints(k.Assists)
+	delete(s.lastDamaged, victimID)
+
+	firstBlood := len(s.kills) == 0 && killerID != 0
+	s.kills = append(s.kills, k)
+	for _, a := range k.Assists {
+		stats := &s.participant(a).Stats
+		stats.Assists++
+		stats.FirstBloodAssist = stats.FirstBloodAssist || firstBlood
+	}
+	if killerID == 0 {
+		return
+	}
+
+	stats := &s.participant(killerID).Stats
+	stats.Kills++
+	stats.FirstBloodKill = stats.FirstBloodKill || firstBlood
+	if firstBlood {
+		s.team(int(killerTeam)).FirstBlood = true
+	}
+
+	s.spree[killerID]++
+	if s.spree[killerID] > stats.LargestKillingSpree {
+		stats.LargestKillingSpree = s.spree[killerID]
+	}
+
+	window := multiKillSeconds
+	if s.multiKill[killerID] == 4 {
+		window = pentaKillSeconds
+	}
+	if s.multiKill[killerID] > 0 && seconds-s.lastKill[killerID] <= window && s.multiKill[killerID] < 5 {
+		s.multiKill[killerID]++
+	} else {
+		s.multiKill[killerID] = 1
+	}
+	s.lastKill[killerID] = seconds
+
+	switch s.multiKill[killerID] {
+	case 2:
+		stats.DoubleKills++
+	case 3:
+		stats.TripleKills++
+	case 4:
+		stats.QuadraKills++
+	case 5:
+		stats.PentaKills++
+	}
+	if s.multiKill[killerID] > stats.LargestMultiKill {
+		stats.LargestMultiKill = s.multiKill[killerID]
+	}
+}
+
+// team returns the team with the id.
+func (s *synthesizer) team(teamID int) *api.Team {
+	for i := range s.md.Teams {
+		if s.md.Teams[i].TeamID == teamID {
+			return &s.md.Teams[i]
+		}
+	}
+	return nil
+}
+
+// finish adds the stats that can only be computed once every event has been
+// added: gold, building kills and the winner.
+func (s *synthesizer) finish() {
+	if s.md.MatchDuration == 0 {
+		s.md.MatchDuration = int64(s.lastSeconds)
+	}
+
+	for pID, current := range s.goldCurrent {
+		stats := &s.participant(pID).Stats
+		stats.GoldSpent = s.goldSpent[pID]
+		stats.GoldEarned = s.goldSpent[pID] + current
+	}
+
+	firstOfType := map[string]bool{}
+	for _, b := range s.buildingKills {
+		attackers := s.team(otherTeam(b.TeamID))
+		first := !firstOfType[b.BuildingType]
+		firstOfType[b.BuildingType] = true
+
+		var killer *api.ParticipantStats
+		if b.KillerID != 0 {
+			killer = &s.participant(b.KillerID).Stats
+		}
+		switch b.BuildingType {
+		case "TOWER_BUILDING":
+			attackers.TowerKills++
+			attackers.FirstTower = attackers.FirstTower || first
+			if killer != nil {
+				killer.TowerKills++
+				killer.FirstTowerKill = killer.FirstTowerKill || first
+			}
+		case "INHIBITOR_BUILDING":
+			attackers.InhibitorKills++
+			attackers.FirstInhibitor = attackers.FirstInhibitor || first
+			if killer != nil {
+				killer.InhibitorKills++
+				killer.FirstInhibitorKill = killer.FirstInhibitorKill || first
+			}
+		}
+	}
+
+	loser := s.surrendered
+	if prefix, teamID := splitName(s.nexusDestroyed); prefix == "hq" && teamID != 0 {
+		loser = teamID
+	}
+	if loser == 0 {
+		return
+	}
+	winner := otherTeam(loser)
+	s.team(winner).Winner = true
+	for i := range s.md.Participants {
+		s.md.Participants[i].Stats.Winner = s.md.Participants[i].TeamID == winner
+	}
+}
+
+// addBaseviewStats adds the stats that are easier to find in the baseview:
+// wards placed and epic monster kills.
+func addBaseviewStats(md *api.MatchDetail, bv *baseview.Baseview) {
+	var firstDragon, firstBaron bool
+	for _, e := range bv.Events {
+		switch t := e.(type) {
+		case *baseview.WardPlaced:
+			if t.ParticipantID >= 1 && int(t.ParticipantID) <= len(md.Participants) {
+				md.Participants[t.ParticipantID-1].Stats.WardsPlaced++
+			}
+		case *baseview.EpicMonsterKill:
+			teamID := baseview.TeamID(t.KillerID)
+			if teamID == 0 {
+				continue
+			}
+			team := &md.Teams[0]
+			if teamID == baseview.RedTeam {
+				team = &md.Teams[1]
+			}
+			switch t.VictimID {
+			case baseview.MonsterDragonElemental, baseview.MonsterDragonElder:
+				team.DragonKills++
+				if !firstDragon {
+					team.FirstDragon, firstDragon = true, true
+				}
+			case baseview.MonsterBaron:
+				team.BaronKills++
+				if !firstBaron {
+					team.FirstBaron, firstBaron = true, true
+				}
+			}
+		}
+	}
+}
+
+// buildingFromName returns the turret or inhibitor with the elo name, e.g.
+// Turret_T1_L_03_A or Barracks_T2_C1. Fountain turrets are not buildings.
+func buildingFromName(name string) (building, bool) {
+	prefix, teamID := splitName(name)
+	if teamID == 0 {
+		return building{}, false
+	}
+	switch prefix {
+	case "turret":
+		return building{TeamID: teamID, Type: "TOWER_BUILDING"}, true
+	case "barracks":
+		return building{TeamID: teamID, Type: "INHIBITOR_BUILDING"}, true
+	}
+	return building{}, false
+}
+
+// splitName returns the (lowercase) prefix and the team of names like
+// Turret_T1_L_03_A, Barracks_T2_C1 and HQ_T2. The team is 0 if the name
+// doesn't have one.
+func splitName(name string) (string, int) {
+	parts := strings.Split(strings.ToLower(name), "_")
+	if len(parts) < 2 {
+		return parts[0], 0
+	}
+	switch parts[1] {
+	case "t1":
+		return parts[0], int(baseview.BlueTeam)
+	case "t2":
+		return parts[0], int(baseview.RedTeam)
+	}
+	return parts[0], 0
+}
+
+func otherTeam(teamID int) int {
+	if teamID == int(baseview.BlueTeam) {
+		return int(baseview.RedTeam)
+	}
+	return int(baseview.BlueTeam)
+}
+
+// kinds of damage.
+const (
+	otherDamage = iota
+	physicalDamage
+	magicDamage
+	trueDamage
+)
+
+// damageKind returns the kind of damage described by a DAMAGE event's type,
+// which DataSpectator logs as either the name or number of the damage type.
+func damageKind(damageType string) int {
+	switch strings.ToLower(damageType) {
+	case "physical", "0":
+		return physicalDamage
+	case "magical", "magic", "1":
+		return magicDamage
+	case "true", "2":
+		return trueDamage
+	}
+	return otherDamage
+}
@@ -0,0 +1,144 @@
+package synth
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/VantageSports/lolelo/event"
+
+	"gopkg.in/tylerb/is.v1"
+)
+
+// matchEvents returns the elo events of an 11 minute game, which ends with a
+// GAME_END at 650s after blue destroys red's nexus.
+func matchEvents() []event.EloEvent {
+	events := []event.EloEvent{}
+	add := func(e event.EloEvent, secs float64) {
+		e.SetTime(secs)
+		events = append(events, e)
+	}
+	for i := int64(1); i <= 10; i++ {
+		add(&event.NetworkIDMapping{SenderName: fmt.Sprintf("hero%d", i), NetworkID: 100 + i, EloType: "ID_HERO"}, 0)
+	}
+	add(&event.NetworkIDMapping{SenderName: "Turret_T2_L_03_A", NetworkID: 900, EloType: "ID_TURRET"}, 0)
+
+	// a ping per hero every 30 seconds for 11 minutes, with hero1 earning
+	// (and eventually spending) gold.
+	for secs := 0.0; secs < 660; secs += 30 {
+		for i := int64(1); i <= 10; i++ {
+			gold := int64(500)
+			if i == 1 {
+				gold += int64(secs)
+				if secs > 600 {
+					gold = 0
+				}
+			}
+			add(&event.Ping{NetworkID: 100 + i, ChampionID: 10 + i, Gold: gold, Level: 1 + int64(secs)/120, MinionsKilled: int64(secs) / 30}, secs)
+		}
+	}
+	// hero1 (with help from hero2) kills hero6 and hero7 in quick succession.
+	add(&event.Damage{NetworkID: 102, TargetNetworkID: 106, Damage: 100, DamageType: "Magical"}, 95)
+	add(&event.Damage{NetworkID: 101, TargetNetworkID: 106, Damage: 200, DamageType: "Physical"}, 99)
+	add(&event.Kill{NetworkID: 101}, 100)
+	add(&event.Die{NetworkID: 106}, 100)
+	add(&event.Damage{NetworkID: 101, TargetNetworkID: 107, Damage: 50, DamageType: "True"}, 104)
+	add(&event.Kill{NetworkID: 101}, 105)
+	add(&event.Die{NetworkID: 107}, 105)
+	// a minion finishes off hero1.
+	add(&event.Kill{NetworkID: 5000}, 200)
+	add(&event.Die{NetworkID: 101}, 200)
+	// hero3 takes the first tower.
+	add(&event.Kill{NetworkID: 103}, 300)
+	add(&event.Die{NetworkID: 900}, 300)
+	add(&event.NexusDestroyed{Nexus: "HQ_T2"}, 640)
+	add(&event.GameEnd{}, 650)
+	return events
+}
+
+func TestMatchDetail(t *testing.T) {
+	is := is.New(t)
+
+	events := matchEvents()
+	md, bv, err := MatchDetail(events, Options{MatchID: 7, PlatformID: "NA1", BlueSummonerID: 1000, RedSummonerID: 2000})
+	is.NotErr(err)
+	is.NotNil(bv)
+
+	is.Equal(10, len(md.Participants))
+	is.Equal(int64(650), md.MatchDuration)
+	is.Equal(int64(2000), md.ParticipantIdentities[5].Player.SummonerID)
+	is.Equal(11, md.Participants[0].ChampionID)
+
+	hero1 := md.Participants[0].Stats
+	is.Equal(int64(2), hero1.Kills)
+	is.Equal(int64(1), hero1.Deaths)
+	is.Equal(int64(2), hero1.LargestMultiKill)
+	is.Equal(int64(1), hero1.DoubleKills)
+	is.True(hero1.FirstBloodKill)
+	is.Equal(int64(200), hero1.PhysicalDamageDealtToChampions)
+	is.Equal(int64(50), hero1.TrueDamageDealtToChampions)
+	is.Equal(int64(250), hero1.TotalDamageDealtToChampions)
+	is.Equal(int64(1100), hero1.GoldEarned)
+	is.Equal(int64(1100), hero1.GoldSpent)
+	is.Equal(int64(6), hero1.ChampLevel)
+	is.True(hero1.Winner)
+
+	hero2 := md.Participants[1].Stats
+	is.Equal(int64(1), hero2.Assists)
+	is.True(hero2.FirstBloodAssist)
+	is.Equal(int64(100), hero2.MagicDamageDealtToChanpions)
+
+	is.Equal(int64(300), md.Participants[5].Stats.TotalDamageTaken)
+	is.True(md.Participants[2].Stats.FirstTowerKill)
+	is.Equal(int64(1), md.Participants[2].Stats.TowerKills)
+	is.False(md.Participants[5].Stats.Winner)
+
+	blue, red := md.Teams[0], md.Teams[1]
+	is.True(blue.Winner)
+	is.True(blue.FirstBlood)
+	is.True(blue.FirstTower)
+	is.Equal(1, blue.TowerKills)
+	is.False(red.Winner)
+
+	// frames at every minute, plus the end of the game.
+	frames := md.Timeline.Frames
+	is.Equal(12, len(frames))
+	is.Equal(int64(650000), frames[11].Timestamp)
+
+	kills := 0
+	for _, e := range frames[1].Events {
+		if e.EventType == "CHAMPION_KILL" {
+			kills++
+			is.Equal(1, e.KillerID)
+		}
+	}
+	is.Equal(2, kills)
+	is.Equal([]int{2}, frames[1].Events[0].AssistingParticipantIDs)
+	is.Equal(0, frames[3].Events[0].KillerID)
+	is.Equal("BUILDING_KILL", frames[5].Events[0].EventType)
+
+	is.Equal(500, frames[0].ParticipantFrames["1"].TotalGold)
+	is.Equal(1100, frames[10].ParticipantFrames["1"].TotalGold)
+	is.Equal(60.0, md.Participants[0].Timeline.GoldPerMinDeltas.ZeroToTen)
+	is.Equal(2.0, md.Participants[0].Timeline.CreepsPerMinDeltas.ZeroToTen)
+}
+
+func TestMatchDetailWithoutGameEnd(t *testing.T) {
+	is := is.New(t)
+
+	// the game ends with the last event, the nexus' destruction.
+	events := matchEvents()
+	md, _, err := MatchDetail(events[:len(events)-1], Options{MatchID: 7, PlatformID: "NA1", BlueSummonerID: 1000, RedSummonerID: 2000})
+	is.NotErr(err)
+	is.Equal(int64(640), md.MatchDuration)
+	is.Equal(12, len(md.Timeline.Frames))
+	is.Equal(int64(640000), md.Timeline.Frames[11].Timestamp)
+}
+
+func TestDamageKind(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(physicalDamage, damageKind("Physical"))
+	is.Equal(magicDamage, damageKind("1"))
+	is.Equal(trueDamage, damageKind("TRUE"))
+	is.Equal(otherDamage, damageKind("Mixed"))
+}
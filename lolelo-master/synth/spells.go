@@ -0,0 +1,81 @@
+package synth
+
+// QToChampID returns the id of the champion whose Q spell has the name, or 0
+// if we don't know the spell.
+func QToChampID(spell string) int {
+	switch spell {
+	case "AsheQ":
+		return 22 // Ashe
+	case "BardQ":
+		return 432 // Bard
+	case "CamilleQ":
+		return 164 // Camille
+	case "CassiopeiaQ":
+		return 69 // Cassiopeia
+	case "PhosphorusBomb":
+		return 42 // Corki
+	case "EzrealMysticShot":
+		return 81 // Ezreal
+	case "FioraQ":
+		return 114 // Fiora
+	case "JayceShockBlast":
+		return 126 // Jayce
+	case "JhinQ":
+		return 202 // Jhin
+	case "KalistaMysticShot":
+		return 429 // Kalista
+	case "BlindMonkQOne":
+		return 64 // Lee Sin
+	case "LuluQ":
+		return 117 // Lulu
+	case "MalzaharQ":
+		return 90 // Malzahar
+	case "MaokaiTrunkLine":
+		return 57 // Maokai
+	case "OlafAxeThrowCast":
+		return 2 // Olaf
+	case "RekSaiQ":
+		return 421 // Reksai
+	case "RengarQ":
+		return 107 // Rengar
+	case "RyzeQ":
+		return 13 // Ryze
+	case "PoisonTrail":
+		return 27 // Singed
+	case "SyndraQ":
+		return 134 // Syndra
+	case "VarusQ":
+		return 110 // Varus
+	case "ZacQ":
+		return 154 // Zac
+	case "ZileanQ":
+		return 26 // Zilean
+	case "ZyraQ":
+		return 143 // Zyra
+	default:
+		return 0
+	}
+}
+
+// SummonerSpellID returns the id of the summoner spell with the name, or 0 if
+// we don't know the spell.
+func SummonerSpellID(spell string) int {
+	switch spell {
+	case "SummonerBoost":
+		return 1 // Cleanse
+	case "SummonerExhaust":
+		return 3
+	case "SummonerFlash":
+		return 4
+	case "SummonerHaste":
+		return 6
+	case "SummonerHeal":
+		return 7
+	case "SummonerSmite":
+		return 11
+	case "SummonerTeleport":
+		return 12
+	default:
+		return 0
+	}
+}
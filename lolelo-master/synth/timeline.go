@@ -0,0 +1,206 @@
+package synth
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/riot/api"
+)
+
+// frameSeconds is the time between timeline frames, as in riot's timelines.
+const frameSeconds = 60
+
+// levelXP is the total experience needed to reach each champion level (from
+// 1 to 18). Elo logs don't include experience, so we estimate it from level.
+var levelXP = []int{0, 280, 660, 1140, 1720, 2400, 3180, 4060, 5040, 6120, 7300, 8580, 9960, 11440, 13020, 14700, 16480, 18360}
+
+// skillSlots are the riot skill slot numbers of each baseview slot.
+var skillSlots = map[string]int{"Q": 1, "W": 2, "E": 3, "R": 4}
+
+// monsterTypes are the riot monster types of the epic monsters.
+var monsterTypes = map[int64]string{
+	baseview.MonsterBaron:           "BARON_NASHOR",
+	baseview.MonsterDragonElemental: "DRAGON",
+	baseview.MonsterDragonElder:     "DRAGON",
+	baseview.MonsterRiftHerald:      "RIFTHERALD",
+}
+
+// wardTypes are the riot ward types of each ward item.
+var wardTypes = map[baseview.WardItemType]string{
+	baseview.SightWard:     "SIGHT_WARD",
+	baseview.VisionWard:    "VISION_WARD",
+	baseview.YellowTrinket: "YELLOW_TRINKET",
+	baseview.BlueTrinket:   "YELLOW_TRINKET_UPGRADE",
+}
+
+// timeline returns a timeline with a frame for the start of every minute of
+// the match (and one for its end). Like riot's, each frame holds the state of
+// every participant at the frame's time, and the events from then until the
+// next frame.
+func timeline(bv *baseview.Baseview, kills []kill, buildingKills []buildingKill, durationSecs int64) api.Timeline {
+	frames := []api.Frame{}
+	for secs := int64(0); secs <= durationSecs; secs += frameSeconds {
+		frames = append(frames, api.Frame{Timestamp: secs * 1000, Events: []api.Event{}})
+	}
+	if durationSecs%frameSeconds != 0 {
+		frames = append(frames, api.Frame{Timestamp: durationSecs * 1000, Events: []api.Event{}})
+	}
+
+	// addEvent adds the event to the frame that started most recently before
+	// it (or the last full minute).
+	addEvent := func(seconds float64, e api.Event) {
+		i := int(seconds / frameSeconds)
+		if i > int(durationSecs/frameSeconds) {
+			i = int(durationSecs / frameSeconds)
+		}
+		if i < 0 {
+			i = 0
+		}
+		e.Timestamp = int64(seconds * 1000)
+		frames[i].Events = append(frames[i].Events, e)
+	}
+
+	// the state of each participant as of the event being processed, which is
+	// copied to each frame as the events pass its time.
+	states := map[int]*api.ParticipantFrame{}
+	lastGold := map[int]int64{}
+	for _, p := range bv.Participants {
+		pID := int(p.ParticipantID)
+		states[pID] = &api.ParticipantFrame{ParticipantID: pID, Level: 1}
+	}
+	next := 0
+	snapshot := func(seconds float64) {
+		for ; next < len(frames) && float64(frames[next].Timestamp)/1000 < seconds; next++ {
+			frames[next].ParticipantFrames = map[string]api.ParticipantFrame{}
+			for pID, state := range states {
+				frames[next].ParticipantFrames[strconv.Itoa(pID)] = *state
+			}
+		}
+	}
+
+	for _, e := range bv.Events {
+		snapshot(e.Seconds())
+
+		switch t := e.(type) {
+		case *baseview.StateUpdate:
+			state := states[int(t.ParticipantID)]
+			if state == nil {
+				continue
+			}
+			// gold only goes down when it's spent.
+			state.TotalGold += int(math.Max(0, float64(t.Gold-lastGold[state.ParticipantID])))
+			lastGold[state.ParticipantID] = t.Gold
+			state.CurrentGold = int(t.Gold)
+			state.MinionsKilled = int(t.MinionsKilled)
+			state.JungleMinionsKilled = int(t.NeutralMinionsKilled)
+			state.Position = api.Position{X: int(t.Position.X), Y: int(t.Position.Y)}
+
+		case *baseview.LevelUp:
+			state := states[int(t.ParticipantID)]
+			if state == nil || t.Level < 1 || int(t.Level) > len(levelXP) {
+				continue
+			}
+			state.Level = int(t.Level)
+			state.XP = levelXP[t.Level-1]
+
+		case *baseview.SlotUpgrade:
+			addEvent(t.Seconds(), api.Event{
+				EventType:     "SKILL_LEVEL_UP",
+				LevelUpType:   "NORMAL",
+				ParticipantID: int(t.ParticipantID),
+				SkillSlot:     skillSlots[t.Slot],
+			})
+
+		case *baseview.WardPlaced:
+			addEvent(t.Seconds(), api.Event{
+				EventType: "WARD_PLACED",
+				CreatorID: int(t.ParticipantID),
+				WardType:  wardTypes[t.WardItemType],
+			})
+
+		case *baseview.EpicMonsterKill:
+			addEvent(t.Seconds(), api.Event{
+				EventType:   "ELITE_MONSTER_KILL",
+				KillerID:    int(t.KillerID),
+				MonsterType: monsterTypes[t.VictimID],
+			})
+		}
+	}
+	snapshot(math.Inf(1))
+
+	for _, k := range kills {
+		addEvent(k.Seconds, api.Event{
+			EventType:               "CHAMPION_KILL",
+			AssistingParticipantIDs: k.Assists,
+			KillerID:                k.KillerID,
+			Position:                api.Position{X: int(k.Position.X), Y: int(k.Position.Y)},
+			VictimID:                k.VictimID,
+		})
+	}
+	for _, b := range buildingKills {
+		addEvent(b.Seconds, api.Event{
+			EventType:    "BUILDING_KILL",
+			BuildingType: b.BuildingType,
+			KillerID:     b.KillerID,
+			TeamID:       b.TeamID,
+		})
+	}
+	for i := range frames {
+		sort.Stable(byTimestamp(frames[i].Events))
+	}
+
+	return api.Timeline{FrameInterval: frameSeconds * 1000, Frames: frames}
+}
+
+// addTimelineDeltas adds the per-minute creep, gold and experience deltas of
+// each participant, for each ten minute period of the match (as far as it
+// lasted).
+func addTimelineDeltas(md *api.MatchDetail) {
+	frames := md.Timeline.Frames
+	if len(frames) == 0 {
+		return
+	}
+	for i := range md.Participants {
+		p := &md.Participants[i]
+		key := strconv.Itoa(p.ParticipantID)
+		p.Timeline.CreepsPerMinDeltas = perMinDeltas(frames, func(f api.ParticipantFrame) int { return f.MinionsKilled }, key)
+		p.Timeline.GoldPerMinDeltas = perMinDeltas(frames, func(f api.ParticipantFrame) int { return f.TotalGold }, key)
+		p.Timeline.XpPerMinDeltas = perMinDeltas(frames, func(f api.ParticipantFrame) int { return f.XP }, key)
+	}
+}
+
+// perMinDeltas returns the average per-minute change in a participant's frame
+// value over each ten minute period that the match lasted through.
+func perMinDeltas(frames []api.Frame, value func(api.ParticipantFrame) int, key string) api.ParticipantTimelineData {
+	at := func(i int) float64 {
+		return float64(value(frames[i].ParticipantFrames[key]))
+	}
+	// frame i is at minute i, except (perhaps) the last.
+	last := len(frames) - 1
+	endMinutes := float64(frames[last].Timestamp) / 60000
+
+	data := api.ParticipantTimelineData{}
+	if last >= 10 {
+		data.ZeroToTen = (at(10) - at(0)) / 10
+	}
+	if last >= 20 {
+		data.TenToTwenty = (at(20) - at(10)) / 10
+	}
+	if last >= 30 {
+		data.TwentyToThirty = (at(30) - at(20)) / 10
+	}
+	if endMinutes > 30 {
+		data.ThirtyToEnd = (at(last) - at(30)) / (endMinutes - 30)
+	}
+	return data
+}
+
+// byTimestamp is a sort implementation that permits sorting riot events by
+// timestamp.
+type byTimestamp []api.Event
+
+func (t byTimestamp) Len() int           { return len(t) }
+func (t byTimestamp) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t byTimestamp) Less(i, j int) bool { return t[i].Timestamp < t[j].Timestamp }
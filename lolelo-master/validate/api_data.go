@@ -20,59 +20,21 @@ const maxSecondsDiff = 30.0
 // CheckTruncation returns an error if the elo-determined match duration is
 // not within an acceptable range of the riot-api-determined match duration.
 func CheckTruncation(events []event.EloEvent, matchDurationSecs int64) error {
-	eloSecs := eloDurationSeconds(events, true)
-	matchSecs := float64(matchDurationSecs)
-
-	if math.Abs(eloSecs-matchSecs) > maxSecondsDiff {
-		return fmt.Errorf("elo duration is %.1f, but api match duration is %.1f", eloSecs, matchSecs)
-	}
-	return nil
-}
-
-// eloDurationSeconds returns the duration of the match represented by the
-// specified events. If skipPings is true, pings are skipped for the purpose of
-// determining the 'last' event. This is usually useful because when elodata
-// is tracked for a game in which one team surrendered, there is no event
-// representing the 'end' of the game, and pings last for several minutes beyond
-// the last real game action.
-func eloDurationSeconds(events []event.EloEvent, skip bool) float64 {
-	if len(events) < 1 {
-		return 0.0
-	}
-
-	var lastEvent event.EloEvent
-
-	for i := len(events) - 2; lastEvent == nil && i >= 0; i-- {
-		e := events[i]
-		// skip insignificant events, assume there's a BasicAttack, Damage, ChampKill, etc often enough.
-		if skip {
-			switch e.(type) {
-			case *event.Damage, *event.ChampDie, *event.ChampKill, *event.SpellCast, *event.NexusDestroyed:
-				lastEvent = e
-			}
-		}
-	}
-	if lastEvent == nil {
-		return 0.0
+	summary, err := Summarize(event.NewSliceIterator(events), nil)
+	if err != nil {
+		return err
 	}
-
-	return lastEvent.Time() - events[0].Time()
+	return summary.CheckTruncation(matchDurationSecs)
 }
 
+// AlignAPI sets the match seconds of each event so that the elo deaths line up
+// with the deaths reported in the riot match details.
 func AlignAPI(events []event.EloEvent, matchDetail *api.MatchDetail) error {
-	apiDeaths, err := apiDeaths(matchDetail)
-	if err != nil {
-		return err
-	}
-	nameToParticipantID := map[string]int64{}
-	for _, p := range matchDetail.ParticipantIdentities {
-		nameToParticipantID[p.Player.SummonerName] = int64(p.ParticipantID)
-	}
-	eloDeaths, err := eloDeaths(events, nameToParticipantID)
+	summary, err := Summarize(event.NewSliceIterator(events), matchDetail)
 	if err != nil {
 		return err
 	}
-	offset, err := determineEloToAPIOffset(apiDeaths, eloDeaths)
+	offset, err := summary.APIOffset(matchDetail)
 	if err != nil {
 		return err
 	}
@@ -113,34 +75,6 @@ func apiDeaths(md *api.MatchDetail) ([]participantEvent, error) {
 	return deaths, nil
 }
 
-func eloDeaths(eloEvents []event.EloEvent, nameToPID map[string]int64) ([]participantEvent, error) {
-	participantByNetworkID := map[int64]int64{}
-	deaths := []participantEvent{}
-	for _, e := range eloEvents {
-		switch ev := e.(type) {
-		case *event.NetworkIDMapping:
-			if pID, found := nameToPID[ev.SenderName]; found {
-				participantByNetworkID[ev.NetworkID] = pID
-			}
-		case *event.Die:
-			// Only look at "DIE" events with a hero network id
-			if _, exists := participantByNetworkID[ev.NetworkID]; !exists {
-				continue
-			}
-
-			death := participantEvent{
-				ParticipantID: participantByNetworkID[ev.NetworkID],
-				EloSeconds:    ev.Time(),
-			}
-			if death.ParticipantID < 1 && death.ParticipantID > 10 {
-				return nil, fmt.Errorf("unknown champDie participant (%d) at %.2f (p_id: %d)", death.ParticipantID, ev.Time())
-			}
-			deaths = append(deaths, death)
-		}
-	}
-	return deaths, nil
-}
-
 func determineEloToAPIOffset(apiDeaths, eloDeaths []participantEvent) (float64, error) {
 	if err := validateDeathCounts(apiDeaths, eloDeaths); err != nil {
 		return 0.0, err
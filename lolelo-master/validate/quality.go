@@ -0,0 +1,418 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/VantageSports/lolelo/event"
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/riot/api"
+)
+
+// Names of the quality checks, as reported in findings.
+const (
+	CheckPingGaps     = "ping_gaps"
+	CheckTeleports    = "teleports"
+	CheckNetworkIDs   = "network_ids"
+	CheckKillsDeaths  = "kills_deaths"
+	CheckMonotonicity = "monotonicity"
+	CheckClockDrift   = "clock_drift"
+)
+
+const (
+	maxQualityScore = 100.0
+
+	// pings are emitted every half second.
+	expectedPingInterval = 0.5
+
+	// maxPingGap is the longest (elo) time between two pings of a hero that
+	// we don't consider a gap.
+	maxPingGap = 2.0
+
+	// maxHeroSpeed is the fastest a hero may (apparently) move between pings,
+	// in units per second, unless they recently cast a spell (e.g. flash or
+	// teleport) or recalled.
+	maxHeroSpeed = 2500.0
+
+	// castGraceSeconds is how long after a cast a hero may move impossibly
+	// quickly. Teleport channels for 4 seconds.
+	castGraceSeconds = 5.0
+
+	// maxDriftSeconds is the largest acceptable change in the offset between
+	// elo time and match time over the course of a game.
+	maxDriftSeconds = 5.0
+)
+
+// Quality describes how trustworthy an elo log is. Score is between 0 (junk)
+// and 100 (no problems found). Each finding describes a problem, and how much
+// it lowered the score.
+type Quality struct {
+	Score    float64   `json:"score"`
+	Findings []Finding `json:"findings"`
+}
+
+// Finding is a single problem with an elo log.
+type Finding struct {
+	Check         string  `json:"check"`
+	Message       string  `json:"message"`
+	Penalty       float64 `json:"penalty"`
+	ParticipantID int64   `json:"participant_id,omitempty"`
+}
+
+// QualityChecker scores an elo log as its events are observed, so that it can
+// share a pass over the log with other processing. Checks that compare the log
+// with the riot api are skipped if the checker has no match details.
+type QualityChecker struct {
+	md        *api.MatchDetail
+	nameToPID map[string]int64
+	// geometry is the map the match was played on, or nil if it's not one
+	// that baseview supports.
+	geometry *baseview.Map
+
+	// heroes are the participant ids of heroes by network id.
+	heroes map[int64]int64
+	// mapped are the network ids that have been mapped to an entity, and
+	// referenced are the number of references to each network id.
+	mapped     map[int64]bool
+	referenced map[int64]int
+
+	lastPing map[int64]*event.Ping
+	lastCast map[int64]float64
+
+	// per participant findings.
+	gapCount       map[int64]int
+	gapSeconds     map[int64]float64
+	teleports      map[int64]int
+	levelDecreases map[int64]int
+	goldDecreases  map[int64]int
+
+	// kills and deaths per participant, and elo death times in order.
+	kills      map[int64]int64
+	deaths     map[int64]int64
+	deathTimes map[int64][]float64
+}
+
+// NewQualityChecker returns a checker that compares the log to the match
+// details (which may be nil).
+func NewQualityChecker(md *api.MatchDetail) *QualityChecker {
+	mapID := int64(0)
+	if md != nil {
+		mapID = int64(md.MapID)
+	}
+	q := &QualityChecker{
+		md:             md,
+		nameToPID:      map[string]int64{},
+		geometry:       baseview.MapByID(mapID),
+		heroes:         map[int64]int64{},
+		mapped:         map[int64]bool{},
+		referenced:     map[int64]int{},
+		lastPing:       map[int64]*event.Ping{},
+		lastCast:       map[int64]float64{},
+		gapCount:       map[int64]int{},
+		gapSeconds:     map[int64]float64{},
+		teleports:      map[int64]int{},
+		levelDecreases: map[int64]int{},
+		goldDecreases:  map[int64]int{},
+		kills:          map[int64]int64{},
+		deaths:         map[int64]int64{},
+		deathTimes:     map[int64][]float64{},
+	}
+	if md != nil {
+		for _, p := range md.ParticipantIdentities {
+			q.nameToPID[p.Player.SummonerName] = int64(p.ParticipantID)
+		}
+	}
+	return q
+}
+
+// Observe returns an iterator that yields the same events as it, adding each
+// of them to the checker as they are read.
+func (q *QualityChecker) Observe(it event.Iterator) event.Iterator {
+	return &observer{it: it, q: q}
+}
+
+type observer struct {
+	it event.Iterator
+	q  *QualityChecker
+}
+
+func (o *observer) Next() (event.EloEvent, error) {
+	e, err := o.it.Next()
+	if err == nil {
+		o.q.Add(e)
+	}
+	return e, err
+}
+
+// Add adds the next event of the log to the checker.
+func (q *QualityChecker) Add(e event.EloEvent) {
+	switch t := e.(type) {
+	case *event.NetworkIDMapping:
+		q.mapped[t.NetworkID] = true
+		if t.EloType == "ID_HERO" {
+			// heroes are given a participant id of 0 if we have no details
+			// (or don't recognize the name).
+			q.heroes[t.NetworkID] = q.nameToPID[t.SenderName]
+		}
+	case *event.OnCreate:
+		q.mapped[t.NetworkID] = true
+	case *event.BasicAttack:
+		q.reference(t.NetworkID, t.TargetNetworkID)
+	case *event.Damage:
+		q.reference(t.NetworkID, t.TargetNetworkID)
+	case *event.Die:
+		q.reference(t.NetworkID)
+	case *event.LevelUp:
+		q.reference(t.NetworkID)
+	case *event.SpellCast:
+		q.reference(t.NetworkID, t.TargetNetworkID)
+		q.lastCast[t.NetworkID] = t.Time()
+	case *event.ChampDie:
+		q.reference(t.NetworkID)
+		if pID, ok := q.heroes[t.NetworkID]; ok {
+			q.deaths[pID]++
+			q.deathTimes[pID] = append(q.deathTimes[pID], t.Time())
+		}
+	case *event.ChampKill:
+		q.reference(t.NetworkID)
+		if pID, ok := q.heroes[t.NetworkID]; ok {
+			q.kills[pID]++
+		}
+	case *event.Ping:
+		q.reference(t.NetworkID)
+		q.addPing(t)
+	}
+}
+
+func (q *QualityChecker) reference(networkIDs ...int64) {
+	for _, nID := range networkIDs {
+		if nID != 0 {
+			q.referenced[nID]++
+		}
+	}
+}
+
+func (q *QualityChecker) addPing(p *event.Ping) {
+	prev := q.lastPing[p.NetworkID]
+	q.lastPing[p.NetworkID] = p
+	if prev == nil {
+		return
+	}
+	pID := q.heroes[p.NetworkID]
+
+	elapsed := p.Time() - prev.Time()
+	if elapsed > maxPingGap {
+		q.gapCount[pID]++
+		q.gapSeconds[pID] += elapsed - expectedPingInterval
+	}
+
+	inBase := q.isBase(p.Position) && q.isBase(prev.Position)
+	alive := !p.Dead && !prev.Dead
+
+	speed := p.Position.DistanceXY(prev.Position) / math.Max(elapsed, expectedPingInterval)
+	recentCast := p.Time()-q.lastCast[p.NetworkID] <= castGraceSeconds
+	if alive && speed > maxHeroSpeed && !recentCast && !q.isBase(p.Position) {
+		q.teleports[pID]++
+	}
+
+	if p.Level < prev.Level {
+		q.levelDecreases[pID]++
+	}
+	// gold can only be spent in base (or while dead).
+	if p.Gold < prev.Gold && alive && !inBase {
+		q.goldDecreases[pID]++
+	}
+}
+
+func (q *QualityChecker) isBase(p baseview.Position) bool {
+	if q.geometry == nil {
+		return false
+	}
+	area := q.geometry.AreaID(p)
+	return area == baseview.AreaBlueBase || area == baseview.AreaRedBase
+}
+
+// Result returns the quality of the log observed so far.
+func (q *QualityChecker) Result() *Quality {
+	res := &Quality{Findings: []Finding{}}
+
+	for _, pID := range sortedKeys(q.gapCount) {
+		res.add(Finding{
+			Check:         CheckPingGaps,
+			ParticipantID: pID,
+			Message:       fmt.Sprintf("%d ping gaps missing %.1f seconds", q.gapCount[pID], q.gapSeconds[pID]),
+			Penalty:       math.Min(20, q.gapSeconds[pID]/10),
+		})
+	}
+	for _, pID := range sortedKeys(q.teleports) {
+		res.add(Finding{
+			Check:         CheckTeleports,
+			ParticipantID: pID,
+			Message:       fmt.Sprintf("%d impossibly fast moves", q.teleports[pID]),
+			Penalty:       math.Min(20, 2*float64(q.teleports[pID])),
+		})
+	}
+	q.checkNetworkIDs(res)
+	for _, pID := range sortedKeys(q.levelDecreases) {
+		res.add(Finding{
+			Check:         CheckMonotonicity,
+			ParticipantID: pID,
+			Message:       fmt.Sprintf("level decreased %d times", q.levelDecreases[pID]),
+			Penalty:       math.Min(10, 2*float64(q.levelDecreases[pID])),
+		})
+	}
+	for _, pID := range sortedKeys(q.goldDecreases) {
+		res.add(Finding{
+			Check:         CheckMonotonicity,
+			ParticipantID: pID,
+			Message:       fmt.Sprintf("gold decreased outside of base %d times", q.goldDecreases[pID]),
+			Penalty:       math.Min(10, float64(q.goldDecreases[pID])),
+		})
+	}
+	if q.md != nil {
+		q.checkKillsDeaths(res)
+		q.checkClockDrift(res)
+	}
+
+	res.Score = maxQualityScore
+	for _, f := range res.Findings {
+		res.Score -= f.Penalty
+	}
+	res.Score = math.Max(0, res.Score)
+	return res
+}
+
+func (res *Quality) add(f Finding) {
+	res.Findings = append(res.Findings, f)
+}
+
+func (q *QualityChecker) checkNetworkIDs(res *Quality) {
+	unmapped, refs := 0, 0
+	for nID, count := range q.referenced {
+		if !q.mapped[nID] {
+			unmapped++
+			refs += count
+		}
+	}
+	if unmapped > 0 {
+		res.add(Finding{
+			Check:   CheckNetworkIDs,
+			Message: fmt.Sprintf("%d references to %d unmapped network ids", refs, unmapped),
+			Penalty: math.Min(20, float64(unmapped)),
+		})
+	}
+
+	if q.md == nil {
+		return
+	}
+	found := map[int64]bool{}
+	for _, pID := range q.heroes {
+		found[pID] = true
+	}
+	for _, p := range q.md.Participants {
+		if pID := int64(p.ParticipantID); !found[pID] {
+			res.add(Finding{
+				Check:         CheckNetworkIDs,
+				ParticipantID: pID,
+				Message:       "no hero network id mapping",
+				Penalty:       10,
+			})
+		}
+	}
+}
+
+func (q *QualityChecker) checkKillsDeaths(res *Quality) {
+	for _, p := range q.md.Participants {
+		pID := int64(p.ParticipantID)
+		if diff := q.deaths[pID] - p.Stats.Deaths; diff != 0 {
+			res.add(Finding{
+				Check:         CheckKillsDeaths,
+				ParticipantID: pID,
+				Message:       fmt.Sprintf("elo has %d deaths, api has %d", q.deaths[pID], p.Stats.Deaths),
+				Penalty:       5,
+			})
+		}
+		if diff := q.kills[pID] - p.Stats.Kills; diff != 0 {
+			res.add(Finding{
+				Check:         CheckKillsDeaths,
+				ParticipantID: pID,
+				Message:       fmt.Sprintf("elo has %d kills, api has %d", q.kills[pID], p.Stats.Kills),
+				Penalty:       5,
+			})
+		}
+	}
+}
+
+// checkClockDrift compares the game clock (elo time) to the api timeline, which
+// is wall-clock time since the start of the game. Pairing each participant's
+// elo deaths with their api deaths, the offset between the two should be
+// constant; if it changes over the game, the spectator clock drifted (e.g. the
+// replay paused or skipped).
+func (q *QualityChecker) checkClockDrift(res *Quality) {
+	apiDeaths, err := apiDeaths(q.md)
+	if err != nil {
+		return
+	}
+	apiTimes := map[int64][]float64{}
+	for _, d := range apiDeaths {
+		apiTimes[d.ParticipantID] = append(apiTimes[d.ParticipantID], d.MatchSeconds)
+	}
+
+	xs, ys := []float64{}, []float64{}
+	for pID, eloTimes := range q.deathTimes {
+		for i := 0; i < len(eloTimes) && i < len(apiTimes[pID]); i++ {
+			xs = append(xs, eloTimes[i])
+			ys = append(ys, apiTimes[pID][i]-eloTimes[i])
+		}
+	}
+	if len(xs) < 2 {
+		return
+	}
+
+	slope := regressionSlope(xs, ys)
+	min, max := xs[0], xs[0]
+	for _, x := range xs {
+		min, max = math.Min(min, x), math.Max(max, x)
+	}
+	drift := slope * (max - min)
+	if math.Abs(drift) > maxDriftSeconds {
+		res.add(Finding{
+			Check:   CheckClockDrift,
+			Message: fmt.Sprintf("elo clock drifted %.1f seconds from match time", drift),
+			Penalty: math.Min(20, math.Abs(drift)),
+		})
+	}
+}
+
+// regressionSlope returns the least-squares slope of ys over xs.
+func regressionSlope(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	sumX, sumY, sumXY, sumXX := 0.0, 0.0, 0.0, 0.0
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denom
+}
+
+func sortedKeys(m map[int64]int) []int64 {
+	keys := []int64{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Sort(int64s(keys))
+	return keys
+}
+
+type int64s []int64
+
+func (s int64s) Len() int           { return len(s) }
+func (s int64s) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s int64s) Less(i, j int) bool { return s[i] < s[j] }
@@ -0,0 +1,83 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/VantageSports/lolelo/event"
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/riot/api"
+
+	"gopkg.in/tylerb/is.v1"
+)
+
+func TestQualityChecker(t *testing.T) {
+	is := is.New(t)
+
+	md := &api.MatchDetail{
+		ParticipantIdentities: []api.ParticipantIdentity{
+			{ParticipantID: 1, Player: api.Player{SummonerName: "hero1"}},
+			{ParticipantID: 2, Player: api.Player{SummonerName: "hero2"}},
+		},
+		Participants: []api.Participant{
+			{ParticipantID: 1, Stats: api.ParticipantStats{Deaths: 1}},
+			{ParticipantID: 2, Stats: api.ParticipantStats{Kills: 1}},
+		},
+	}
+
+	q := NewQualityChecker(md)
+	add := func(e event.EloEvent, secs float64) {
+		e.SetTime(secs)
+		q.Add(e)
+	}
+	add(&event.NetworkIDMapping{SenderName: "hero1", NetworkID: 101, EloType: "ID_HERO"}, 0)
+
+	// a steady walk down mid lane, then a 5 second gap, then a jump across the
+	// map, then a level and gold decrease.
+	pos := baseview.Position{X: 5000, Y: 5000}
+	secs := 0.0
+	for ; secs < 10; secs += 0.5 {
+		pos.X += 100
+		add(&event.Ping{NetworkID: 101, Level: 3, Gold: 500, Position: pos}, secs)
+	}
+	secs += 5
+	add(&event.Ping{NetworkID: 101, Level: 3, Gold: 500, Position: pos}, secs)
+	pos.Y += 4000
+	add(&event.Ping{NetworkID: 101, Level: 3, Gold: 500, Position: pos}, secs+0.5)
+	add(&event.Ping{NetworkID: 101, Level: 2, Gold: 400, Position: pos}, secs+1)
+
+	add(&event.Damage{NetworkID: 999, TargetNetworkID: 101}, secs+1)
+	add(&event.ChampDie{NetworkID: 101}, secs+2)
+
+	res := q.Result()
+	byCheck := map[string][]Finding{}
+	for _, f := range res.Findings {
+		byCheck[f.Check] = append(byCheck[f.Check], f)
+	}
+
+	is.Equal(1, len(byCheck[CheckPingGaps]))
+	is.Equal(int64(1), byCheck[CheckPingGaps][0].ParticipantID)
+	is.Equal(1, len(byCheck[CheckTeleports]))
+	is.Equal(2, len(byCheck[CheckMonotonicity]))
+
+	// 999 was never mapped, and hero2 has no mapping at all.
+	is.Equal(2, len(byCheck[CheckNetworkIDs]))
+	is.Equal(int64(2), byCheck[CheckNetworkIDs][1].ParticipantID)
+
+	// hero1's death matches the api, but hero2's kill is missing.
+	is.Equal(1, len(byCheck[CheckKillsDeaths]))
+	is.Equal(int64(2), byCheck[CheckKillsDeaths][0].ParticipantID)
+
+	total := 0.0
+	for _, f := range res.Findings {
+		total += f.Penalty
+	}
+	is.Equal(100-total, res.Score)
+	is.True(res.Score < 100)
+}
+
+func TestRegressionSlope(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(0.5, regressionSlope([]float64{0, 2, 4}, []float64{1, 2, 3}))
+	is.Equal(0.0, regressionSlope([]float64{1, 1}, []float64{1, 2}))
+}
@@ -0,0 +1,134 @@
+package validate
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/VantageSports/lolelo/event"
+	"github.com/VantageSports/riot/api"
+)
+
+// Summary holds the facts about an elo log that are needed to validate it
+// against riot api data. It is built in a single pass over the events, so it
+// can be computed without holding a full game of events in memory.
+type Summary struct {
+	NumEvents int
+	FirstTime float64
+
+	// lastSignificant is the elo time of the last "significant" event (see
+	// isSignificant) seen before the final event, if there was one.
+	lastSignificant    float64
+	hasLastSignificant bool
+
+	// deaths are the hero deaths in the log. They are only collected if the
+	// summary was built with match details.
+	deaths []participantEvent
+}
+
+// Summarize reads every event from the iterator. If md is non-nil, hero deaths
+// are attributed to participants by matching elo hero names with the summoner
+// names in the match details.
+func Summarize(it event.Iterator, md *api.MatchDetail) (*Summary, error) {
+	s := &Summary{}
+
+	nameToPID := map[string]int64{}
+	if md != nil {
+		for _, p := range md.ParticipantIdentities {
+			nameToPID[p.Player.SummonerName] = int64(p.ParticipantID)
+		}
+	}
+	participantByNetworkID := map[int64]int64{}
+
+	// significant is the time of the latest significant event, including the
+	// most recently read one.
+	significant, hasSignificant := 0.0, false
+
+	for {
+		e, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if s.NumEvents == 0 {
+			s.FirstTime = e.Time()
+		}
+		s.NumEvents++
+		// the final event is never considered when determining duration, so
+		// only accept the previous significant time once another event follows.
+		s.lastSignificant, s.hasLastSignificant = significant, hasSignificant
+		if isSignificant(e) {
+			significant, hasSignificant = e.Time(), true
+		}
+
+		switch ev := e.(type) {
+		case *event.NetworkIDMapping:
+			if pID, found := nameToPID[ev.SenderName]; found {
+				participantByNetworkID[ev.NetworkID] = pID
+			}
+		case *event.Die:
+			// Only look at "DIE" events with a hero network id
+			if _, exists := participantByNetworkID[ev.NetworkID]; !exists {
+				continue
+			}
+
+			death := participantEvent{
+				ParticipantID: participantByNetworkID[ev.NetworkID],
+				EloSeconds:    ev.Time(),
+			}
+			if death.ParticipantID < 1 || death.ParticipantID > 10 {
+				return nil, fmt.Errorf("unknown champDie participant (%d) at %.2f", death.ParticipantID, ev.Time())
+			}
+			s.deaths = append(s.deaths, death)
+		}
+	}
+	return s, nil
+}
+
+// isSignificant returns true for events that only occur while the game is
+// actually being played. Pings last for several minutes beyond the last real
+// game action when one team surrenders, so they are not significant. We assume
+// there's a BasicAttack, Damage, ChampKill, etc often enough.
+func isSignificant(e event.EloEvent) bool {
+	switch e.(type) {
+	case *event.Damage, *event.ChampDie, *event.ChampKill, *event.SpellCast, *event.NexusDestroyed:
+		return true
+	}
+	return false
+}
+
+// DurationSeconds returns the duration of the match represented by the events,
+// from the first event to the last significant one (excluding the very last
+// event). It is 0 if there were no significant events.
+func (s *Summary) DurationSeconds() float64 {
+	if s.NumEvents < 1 || !s.hasLastSignificant {
+		return 0.0
+	}
+	return s.lastSignificant - s.FirstTime
+}
+
+// CheckTruncation returns an error if the elo-determined match duration is
+// not within an acceptable range of the riot-api-determined match duration.
+func (s *Summary) CheckTruncation(matchDurationSecs int64) error {
+	eloSecs := s.DurationSeconds()
+	matchSecs := float64(matchDurationSecs)
+
+	if math.Abs(eloSecs-matchSecs) > maxSecondsDiff {
+		return fmt.Errorf("elo duration is %.1f, but api match duration is %.1f", eloSecs, matchSecs)
+	}
+	return nil
+}
+
+// APIOffset returns the number of seconds to add to each elo time to convert
+// it to match seconds, determined by aligning elo deaths with the deaths in
+// the match details. The summary must have been built with the same details.
+func (s *Summary) APIOffset(md *api.MatchDetail) (float64, error) {
+	apiDeaths, err := apiDeaths(md)
+	if err != nil {
+		return 0.0, err
+	}
+	return determineEloToAPIOffset(apiDeaths, s.deaths)
+}
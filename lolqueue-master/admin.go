@@ -0,0 +1,319 @@
+package lolqueue
+
+// This file contains the admin JSON api, which allows privileged users to
+// inspect and manipulate the queue while it is running.
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VantageSports/common/log"
+)
+
+// QueuedPlayer describes a single active player, as reported by the admin api.
+type QueuedPlayer struct {
+	SummonerID        int64     `json:"summoner_id"`
+	SummonerName      string    `json:"summoner_name"`
+	Region            string    `json:"region"`
+	Rank              Rank      `json:"rank"`
+	Criteria          Criteria  `json:"criteria"`
+	InvitationPending bool      `json:"invite_pending"`
+	NumClients        int       `json:"num_clients"`
+	QueuedAt          time.Time `json:"queued_at"`
+	WaitSeconds       float64   `json:"wait_seconds"`
+}
+
+// QueueStats summarizes the state of the queue and the performance of the
+// matcher.
+type QueueStats struct {
+	PlayersInQueue   int       `json:"players_in_queue"`
+	ConnectedPlayers int       `json:"connected_players"`
+	PendingMatches   int       `json:"pending_matches"`
+	MatchesMade      int64     `json:"matches_made"`
+	MatchesFailed    int64     `json:"matches_failed"`
+	LastMatchSearch  time.Time `json:"last_match_search"`
+
+	// Matcher latencies, in milliseconds, over the most recent searches.
+	SearchesSampled int     `json:"searches_sampled"`
+	SearchMeanMs    float64 `json:"search_mean_ms"`
+	SearchP50Ms     float64 `json:"search_p50_ms"`
+	SearchP99Ms     float64 `json:"search_p99_ms"`
+	SearchMaxMs     float64 `json:"search_max_ms"`
+}
+
+func (s *state) queuedPlayers() []QueuedPlayer {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	now := time.Now()
+	res := make([]QueuedPlayer, 0, s.activePlayers.Len())
+	for e := s.activePlayers.Front(); e != nil; e = e.Next() {
+		p, ok := e.Value.(*Player)
+		if !ok {
+			continue
+		}
+		res = append(res, QueuedPlayer{
+			SummonerID:        p.SummonerID,
+			SummonerName:      p.SummonerName,
+			Region:            p.Region,
+			Rank:              p.Rank,
+			Criteria:          p.Criteria,
+			InvitationPending: s.pendingInvitations[p.SummonerID],
+			NumClients:        len(s.clientsByPlayer[p.SummonerID]),
+			QueuedAt:          p.QueuedAt,
+			WaitSeconds:       now.Sub(p.QueuedAt).Seconds(),
+		})
+	}
+	return res
+}
+
+func (s *state) listPendingMatches() []*Match {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	res := make([]*Match, 0, len(s.pendingMatches))
+	for _, m := range s.pendingMatches {
+		res = append(res, copyMatch(m))
+	}
+	sort.Sort(matchesByID(res))
+	return res
+}
+
+func (s *state) pendingMatch(matchID int64) *Match {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if m := s.pendingMatches[matchID]; m != nil {
+		return copyMatch(m)
+	}
+	return nil
+}
+
+// copyMatch returns a copy of the match whose rsvps can be read without holding
+// the state lock.
+func copyMatch(m *Match) *Match {
+	c := *m
+	c.Accepted = make(map[string]bool, len(m.Accepted))
+	for k, v := range m.Accepted {
+		c.Accepted[k] = v
+	}
+	return &c
+}
+
+// cancelMatch withdraws all invitations to a pending match. Invitees remain in
+// the queue in their current order. It returns false if no such match exists.
+func (s *state) cancelMatch(matchID int64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	match := s.pendingMatches[matchID]
+	if match == nil {
+		return false
+	}
+	delete(s.pendingMatches, matchID)
+	s.matchesFailed++
+
+	for _, p := range match.Invited {
+		delete(s.pendingInvitations, p.SummonerID)
+		p.InvitationPending = false
+		for _, client := range s.clientsByPlayer[p.SummonerID] {
+			client.NotifyMadeFail()
+		}
+	}
+	log.Info(fmt.Sprintf("match %d canceled by admin", matchID))
+	return true
+}
+
+// removePlayer takes the player out of the queue and disconnects all of their
+// clients. It returns false if the player is neither queued nor connected.
+func (s *state) removePlayer(summonerID int64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	found := false
+	for e := s.activePlayers.Front(); e != nil; e = e.Next() {
+		if p, ok := e.Value.(*Player); ok && p.SummonerID == summonerID {
+			s.activePlayers.Remove(e)
+			found = true
+			break
+		}
+	}
+
+	for _, client := range s.clientsByPlayer[summonerID] {
+		found = true
+		client.NotifyError("you have been removed from the queue")
+		client.Disconnect(time.Second)
+	}
+
+	if found {
+		log.Info(fmt.Sprintf("summoner %d removed by admin", summonerID))
+	}
+	return found
+}
+
+func (s *state) stats() QueueStats {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	qs := QueueStats{
+		PlayersInQueue:   s.activePlayers.Len(),
+		ConnectedPlayers: len(s.clientsByPlayer),
+		PendingMatches:   len(s.pendingMatches),
+		MatchesMade:      s.matchesMade,
+		MatchesFailed:    s.matchesFailed,
+		LastMatchSearch:  s.lastMatchSearch,
+		SearchesSampled:  len(s.matchSearchTook),
+	}
+	if len(s.matchSearchTook) == 0 {
+		return qs
+	}
+
+	sorted := make([]float64, len(s.matchSearchTook))
+	total := 0.0
+	for i, d := range s.matchSearchTook {
+		sorted[i] = d.Seconds() * 1000
+		total += sorted[i]
+	}
+	sort.Float64s(sorted)
+
+	qs.SearchMeanMs = total / float64(len(sorted))
+	qs.SearchP50Ms = Percentile(sorted, 50)
+	qs.SearchP99Ms = Percentile(sorted, 99)
+	qs.SearchMaxMs = sorted[len(sorted)-1]
+	return qs
+}
+
+// Percentile returns the pth percentile (0-100) of the sorted values, using the
+// nearest-rank method. It returns 0 for an empty slice.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(float64(len(sorted))*p/100+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i]
+}
+
+type matchesByID []*Match
+
+func (m matchesByID) Len() int           { return len(m) }
+func (m matchesByID) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m matchesByID) Less(i, j int) bool { return m[i].ID < m[j].ID }
+
+//
+// HTTP Handlers
+//
+
+// AdminHandler returns the handler for the admin api, which should be mounted
+// at "/admin/". All requests must supply a vantage token (as a bearer token or
+// the "token" query parameter) for a user with admin privileges.
+//
+//	GET    /admin/stats           queue counts and matcher latency
+//	GET    /admin/players         queued players in queue order
+//	DELETE /admin/players/{id}    remove a player (by summoner id)
+//	GET    /admin/matches         pending matches
+//	GET    /admin/matches/{id}    a single pending match
+//	DELETE /admin/matches/{id}    cancel a pending match
+func (s *Server) AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := s.checkAdmin(r); err != nil {
+			log.Warning(fmt.Sprintf("admin request denied: %v", err))
+			writeJSONError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin"), "/")
+		parts := strings.Split(path, "/")
+
+		switch {
+		case path == "stats" && r.Method == "GET":
+			writeJSON(w, s.state.stats())
+
+		case path == "players" && r.Method == "GET":
+			writeJSON(w, s.state.queuedPlayers())
+
+		case len(parts) == 2 && parts[0] == "players" && r.Method == "DELETE":
+			id, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, "invalid summoner id")
+				return
+			}
+			if !s.state.removePlayer(id) {
+				writeJSONError(w, http.StatusNotFound, "player not found")
+				return
+			}
+			writeJSON(w, map[string]interface{}{"removed": id})
+
+		case path == "matches" && r.Method == "GET":
+			writeJSON(w, s.state.listPendingMatches())
+
+		case len(parts) == 2 && parts[0] == "matches":
+			id, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, "invalid match id")
+				return
+			}
+			switch r.Method {
+			case "GET":
+				if m := s.state.pendingMatch(id); m != nil {
+					writeJSON(w, m)
+					return
+				}
+			case "DELETE":
+				if s.state.cancelMatch(id) {
+					writeJSON(w, map[string]interface{}{"canceled": id})
+					return
+				}
+			default:
+				writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+				return
+			}
+			writeJSONError(w, http.StatusNotFound, "match not found")
+
+		default:
+			writeJSONError(w, http.StatusNotFound, "not found")
+		}
+	})
+}
+
+func (s *Server) checkAdmin(r *http.Request) error {
+	token := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if token == "" {
+		return fmt.Errorf("no token supplied")
+	}
+
+	isAdmin, err := s.vantageUtil.IsAdmin(token)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return fmt.Errorf("token lacks admin privileges")
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Warning(fmt.Sprintf("error writing admin response: %v", err))
+	}
+}
+
+func writeJSONError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
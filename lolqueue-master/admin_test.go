@@ -0,0 +1,118 @@
+package lolqueue
+
+import (
+	"container/list"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestServer() *Server {
+	return &Server{
+		state: &state{
+			activePlayers:      list.New(),
+			clientsByPlayer:    map[int64][]*Client{},
+			pendingInvitations: map[int64]bool{},
+			pendingMatches:     map[int64]*Match{},
+			lock:               &sync.RWMutex{},
+		},
+		vantageUtil: &VantageUtil{Fake: true},
+	}
+}
+
+func adminRequest(s *Server, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer test")
+	w := httptest.NewRecorder()
+	s.AdminHandler().ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminPlayersAndMatches(t *testing.T) {
+	s := newTestServer()
+	p1 := &Player{SummonerName: "p1", SummonerID: 1, Region: "na"}
+	p2 := &Player{SummonerName: "p2", SummonerID: 2, Region: "na"}
+	s.state.activate(p1)
+	s.state.activate(p2)
+	s.state.pendingMatches[7] = &Match{ID: 7, Invited: []*Player{p1, p2}, Accepted: map[string]bool{}}
+	s.state.pendingInvitations[1] = true
+	s.state.pendingInvitations[2] = true
+
+	w := adminRequest(s, "GET", "/admin/players")
+	players := []QueuedPlayer{}
+	if err := json.Unmarshal(w.Body.Bytes(), &players); err != nil {
+		t.Fatal(err)
+	}
+	if len(players) != 2 || players[0].SummonerID != 1 || !players[0].InvitationPending {
+		t.Error("expected p1 then p2, both with pending invitations. got:", players)
+	}
+	if players[0].QueuedAt.IsZero() || players[0].QueuedAt.After(time.Now()) {
+		t.Error("expected queued time to be set. got:", players[0].QueuedAt)
+	}
+
+	if w = adminRequest(s, "GET", "/admin/matches/7"); w.Code != http.StatusOK {
+		t.Errorf("expected to find match 7, got %d", w.Code)
+	}
+	if w = adminRequest(s, "DELETE", "/admin/matches/7"); w.Code != http.StatusOK {
+		t.Errorf("expected to cancel match 7, got %d", w.Code)
+	}
+	if len(s.state.pendingMatches) != 0 || len(s.state.pendingInvitations) != 0 {
+		t.Error("expected canceled match and its invitations to be removed")
+	}
+	if w = adminRequest(s, "DELETE", "/admin/matches/7"); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 canceling a missing match, got %d", w.Code)
+	}
+
+	if w = adminRequest(s, "DELETE", "/admin/players/2"); w.Code != http.StatusOK {
+		t.Errorf("expected to remove p2, got %d", w.Code)
+	}
+	if s.state.activePlayers.Len() != 1 {
+		t.Errorf("expected 1 player remaining, found %d", s.state.activePlayers.Len())
+	}
+
+	stats := QueueStats{}
+	w = adminRequest(s, "GET", "/admin/stats")
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.PlayersInQueue != 1 || stats.MatchesFailed != 1 {
+		t.Error("unexpected stats:", stats)
+	}
+}
+
+func TestAdminRequiresToken(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest("GET", "/admin/stats", nil)
+	w := httptest.NewRecorder()
+	s.AdminHandler().ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", w.Code)
+	}
+}
+
+func TestCanceledMatchTimeout(t *testing.T) {
+	s := newTestServer()
+	p1 := &Player{SummonerName: "p1", SummonerID: 1, Region: "na"}
+	p2 := &Player{SummonerName: "p2", SummonerID: 2, Region: "na"}
+	canceled := &Match{ID: 7, Invited: []*Player{p1, p2}, Accepted: map[string]bool{}}
+	s.state.pendingMatches[7] = canceled
+	if w := adminRequest(s, "DELETE", "/admin/matches/7"); w.Code != http.StatusOK {
+		t.Fatalf("expected to cancel match 7, got %d", w.Code)
+	}
+
+	// the players are re-invited before the canceled match times out.
+	next := &Match{ID: 8, Invited: []*Player{p1, p2}, Accepted: map[string]bool{}}
+	s.state.pendingMatches[8] = next
+	expireMatch(s.state, canceled)
+	if len(next.Accepted) != 0 || len(canceled.Accepted) != 0 {
+		t.Error("expected the canceled match's timeout to be ignored. got:", next.Accepted, canceled.Accepted)
+	}
+
+	expireMatch(s.state, next)
+	if len(next.Accepted) != 2 || next.Accepted[p1.Key()] {
+		t.Error("expected both players to decline the timed out match. got:", next.Accepted)
+	}
+}
@@ -4,14 +4,17 @@ import (
 	"net/http"
 	"os"
 
+	"cloud.google.com/go/pubsub"
 	"golang.org/x/net/websocket"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/grpclog"
 
 	"github.com/VantageSports/common/certs"
+	"github.com/VantageSports/common/credentials/google"
 	"github.com/VantageSports/common/env"
 	"github.com/VantageSports/common/log"
+	"github.com/VantageSports/common/queue"
 	"github.com/VantageSports/lolqueue"
 	"github.com/VantageSports/lolusers"
 	"github.com/VantageSports/payment"
@@ -26,6 +29,8 @@ var (
 	addrRiotProxy = os.Getenv("ADDR_RIOT_PROXY")
 	addrPayment   = os.Getenv("ADDR_PAYMENT")
 	addrUsersV2   = os.Getenv("ADDR_USERS_V2")
+	googProjectID = os.Getenv("GOOG_PROJECT_ID")
+	tMatchMade    = os.Getenv("TOPIC_LOL_QUEUE_MATCH_MADE")
 	tlsCertPath   = os.Getenv("TLS_CERT")
 	tlsKeyPath    = os.Getenv("TLS_KEY")
 
@@ -39,6 +44,7 @@ func init() {
 
 func main() {
 	vutil := &lolqueue.VantageUtil{Fake: fake}
+	var matchMadeTopic *pubsub.Topic
 	if !fake {
 		vutil = &lolqueue.VantageUtil{
 			Users:    mustUsersClient(),
@@ -46,11 +52,13 @@ func main() {
 			Riot:     mustRiotProxyClient(),
 			Payment:  mustPaymentClient(),
 		}
+		matchMadeTopic = mustMatchMadeTopic()
 	}
-	s := lolqueue.NewServer(vutil)
+	s := lolqueue.NewServer(vutil, matchMadeTopic)
 
 	http.Handle("/connect", websocket.Handler(s.OnConnect))
 	http.HandleFunc("/debug", s.Debug)
+	http.Handle("/admin/", s.AdminHandler())
 
 	log.Info("listening on " + port)
 	if tlsCertPath != "" && tlsKeyPath != "" {
@@ -60,6 +68,25 @@ func main() {
 	}
 }
 
+// mustMatchMadeTopic returns the topic that made matches are published to, or
+// nil if no topic is configured.
+func mustMatchMadeTopic() *pubsub.Topic {
+	if tMatchMade == "" {
+		log.Warning("TOPIC_LOL_QUEUE_MATCH_MADE not set, made matches will not be published")
+		return nil
+	}
+	creds := google.MustEnvCreds(googProjectID, pubsub.ScopePubSub)
+	client, err := queue.InitClient(creds)
+	if err != nil {
+		log.Fatal(err)
+	}
+	topic, err := queue.EnsureTopic(client, tMatchMade)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return topic
+}
+
 func mustUsersClient() users.AuthCheckClient {
 	c, err := certs.ClientTLS(tlsCertPath, certs.Insecure(insecureGRPC))
 	if err != nil {
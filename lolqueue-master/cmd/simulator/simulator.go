@@ -0,0 +1,296 @@
+package main
+
+// simulator drives a lolqueue server (running with FAKE=true) with many
+// synthetic websocket clients, each joining the queue as a distinct summoner.
+// Clients arrive according to a configurable distribution, accept or decline
+// invitations with a configurable probability and delay, and give up after a
+// configurable amount of patience. Once all clients have finished, it reports
+// the match rate, wait-time percentiles and the server's matcher latency.
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/VantageSports/lolqueue"
+)
+
+var (
+	addr        = flag.String("addr", "localhost:9095", "host:port of the lolqueue server")
+	numClients  = flag.Int("clients", 1000, "number of synthetic clients to run")
+	arrival     = flag.String("arrival", "poisson", "arrival distribution: poisson, uniform or burst")
+	arrivalRate = flag.Float64("rate", 10, "mean client arrivals per second (ignored for burst)")
+	acceptProb  = flag.Float64("accept", 0.9, "probability that a client accepts an invitation")
+	acceptDelay = flag.Duration("accept-delay", time.Second*5, "max delay before responding to an invitation")
+	patience    = flag.Duration("patience", time.Minute*5, "how long a client waits for a match before leaving")
+	anyProb     = flag.Float64("any", 0.3, "probability that a client will play any position")
+	minPlayers  = flag.Int("min-players", 2, "min players criteria for each client")
+	region      = flag.String("region", "na", "region of every synthetic player")
+	adminToken  = flag.String("admin-token", "simulator", "token used to query the admin api")
+	seed        = flag.Int64("seed", time.Now().UnixNano(), "random seed")
+)
+
+var (
+	tiers     = []string{"bronze", "silver", "gold", "platinum", "diamond"}
+	divisions = []string{"v", "iv", "iii", "ii", "i"}
+	positions = []string{"top", "mid", "jungle", "adc", "support"}
+)
+
+// outcome describes how a single synthetic client left the queue.
+type outcome string
+
+const (
+	made     outcome = "made"
+	declined outcome = "declined"
+	gaveUp   outcome = "gave_up"
+	failed   outcome = "error"
+)
+
+type result struct {
+	Outcome     outcome
+	Wait        time.Duration
+	Invitations int
+}
+
+func main() {
+	flag.Parse()
+	rand.Seed(*seed)
+
+	results := make(chan result, *numClients)
+	wg := &sync.WaitGroup{}
+	start := time.Now()
+
+	for i := 0; i < *numClients; i++ {
+		time.Sleep(nextArrival())
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			results <- runClient(id, newSimClient(id))
+		}(i)
+	}
+
+	wg.Wait()
+	close(results)
+
+	report(results, time.Since(start))
+}
+
+// nextArrival returns the delay before the next client connects.
+func nextArrival() time.Duration {
+	mean := float64(time.Second) / *arrivalRate
+	switch *arrival {
+	case "burst":
+		return 0
+	case "uniform":
+		return time.Duration(mean)
+	case "poisson":
+		return time.Duration(rand.ExpFloat64() * mean)
+	default:
+		log.Fatalln("unknown arrival distribution: " + *arrival)
+	}
+	return 0
+}
+
+// simClient is the synthetic player and behavior of a single client.
+type simClient struct {
+	player      *lolqueue.Player
+	criteria    map[string]interface{}
+	acceptProb  float64
+	acceptDelay time.Duration
+	patience    time.Duration
+}
+
+// newSimClient returns a client with a random rank and position preferences.
+func newSimClient(id int) *simClient {
+	rank := lolqueue.Rank{
+		Tier:     tiers[rand.Intn(len(tiers))],
+		Division: divisions[rand.Intn(len(divisions))],
+	}
+
+	myPositions := []string{"any"}
+	if rand.Float64() >= *anyProb {
+		perm := rand.Perm(len(positions))
+		myPositions = []string{}
+		for _, i := range perm[:1+rand.Intn(2)] {
+			myPositions = append(myPositions, positions[i])
+		}
+	}
+
+	return &simClient{
+		player: &lolqueue.Player{
+			SummonerName: fmt.Sprintf("sim%d", id),
+			SummonerID:   int64(1000000 + id),
+			Region:       *region,
+			Rank:         rank,
+		},
+		criteria: map[string]interface{}{
+			"region":       *region,
+			"my_positions": myPositions,
+			"min_players":  *minPlayers,
+			"mix_rank":     lolqueue.Rank{Tier: "bronze", Division: "v"},
+			"max_rank":     lolqueue.Rank{Tier: "challenger", Division: "i"},
+		},
+		acceptProb:  *acceptProb,
+		acceptDelay: *acceptDelay,
+		patience:    *patience,
+	}
+}
+
+// runClient connects, joins the queue and responds to invitations until the
+// client's match is made, it declines, or it runs out of patience.
+func runClient(id int, c *simClient) result {
+	res := result{Outcome: failed}
+
+	ws, err := websocket.Dial("ws://"+*addr+"/connect", "", "http://"+*addr)
+	if err != nil {
+		log.Printf("client %d: %v", id, err)
+		return res
+	}
+	defer ws.Close()
+
+	token, err := lolqueue.FakeToken(c.player)
+	if err != nil {
+		log.Printf("client %d: %v", id, err)
+		return res
+	}
+	if err = websocket.JSON.Send(ws, lolqueue.Token{MsgType: "token", Token: token}); err != nil {
+		log.Printf("client %d: %v", id, err)
+		return res
+	}
+
+	activate := map[string]interface{}{"type": "activate", "active": true, "criteria": c.criteria}
+	if err = websocket.JSON.Send(ws, activate); err != nil {
+		log.Printf("client %d: %v", id, err)
+		return res
+	}
+
+	queued := time.Now()
+	ws.SetReadDeadline(queued.Add(c.patience))
+	rsvpd := map[int64]bool{}
+
+	for {
+		msg := map[string]interface{}{}
+		if err = websocket.JSON.Receive(ws, &msg); err != nil {
+			if time.Since(queued) >= c.patience {
+				res.Outcome = gaveUp
+			}
+			res.Wait = time.Since(queued)
+			return res
+		}
+
+		switch msg["type"] {
+		case "invite":
+			invite := lolqueue.MatchInvite{}
+			if !decode(msg, &invite) || invite.Match == nil || rsvpd[invite.Match.ID] {
+				continue
+			}
+			rsvpd[invite.Match.ID] = true
+			res.Invitations++
+
+			accept := rand.Float64() < c.acceptProb
+			go func(matchID int64) {
+				time.Sleep(time.Duration(rand.Int63n(int64(c.acceptDelay) + 1)))
+				websocket.JSON.Send(ws, lolqueue.MatchRSVP{MsgType: "rsvp", MatchID: matchID, Accept: accept})
+			}(invite.Match.ID)
+			if !accept {
+				res.Outcome = declined
+				res.Wait = time.Since(queued)
+				// give the rsvp a chance to be sent before closing.
+				time.Sleep(c.acceptDelay + time.Second)
+				return res
+			}
+
+		case "made":
+			res.Outcome = made
+			res.Wait = time.Since(queued)
+			return res
+
+		case "error":
+			log.Printf("client %d: server error: %v", id, msg["text"])
+			return res
+		}
+	}
+}
+
+// decode re-encodes a generic message into the specified type.
+func decode(msg map[string]interface{}, v interface{}) bool {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+func report(results <-chan result, took time.Duration) {
+	counts := map[outcome]int{}
+	waits := []float64{}
+	invitations := 0
+	total := 0
+
+	for r := range results {
+		total++
+		counts[r.Outcome]++
+		invitations += r.Invitations
+		if r.Outcome == made {
+			waits = append(waits, r.Wait.Seconds())
+		}
+	}
+	sort.Float64s(waits)
+
+	fmt.Printf("simulated %d clients in %v\n", total, took)
+	fmt.Printf("  made:     %d (%.1f%%)\n", counts[made], pct(counts[made], total))
+	fmt.Printf("  declined: %d (%.1f%%)\n", counts[declined], pct(counts[declined], total))
+	fmt.Printf("  gave up:  %d (%.1f%%)\n", counts[gaveUp], pct(counts[gaveUp], total))
+	fmt.Printf("  errors:   %d (%.1f%%)\n", counts[failed], pct(counts[failed], total))
+	fmt.Printf("  invitations received: %d\n", invitations)
+	fmt.Printf("wait until made (seconds): p50=%.1f p90=%.1f p99=%.1f max=%.1f\n",
+		lolqueue.Percentile(waits, 50),
+		lolqueue.Percentile(waits, 90),
+		lolqueue.Percentile(waits, 99),
+		lolqueue.Percentile(waits, 100))
+
+	stats, err := fetchStats()
+	if err != nil {
+		log.Printf("unable to fetch matcher stats: %v", err)
+		return
+	}
+	fmt.Printf("matcher latency over %d searches (ms): mean=%.1f p50=%.1f p99=%.1f max=%.1f\n",
+		stats.SearchesSampled, stats.SearchMeanMs, stats.SearchP50Ms, stats.SearchP99Ms, stats.SearchMaxMs)
+	fmt.Printf("server totals: made=%d failed=%d still queued=%d\n",
+		stats.MatchesMade, stats.MatchesFailed, stats.PlayersInQueue)
+}
+
+func fetchStats() (*lolqueue.QueueStats, error) {
+	req, err := http.NewRequest("GET", "http://"+*addr+"/admin/stats", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+*adminToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("admin api returned %s", resp.Status)
+	}
+
+	stats := &lolqueue.QueueStats{}
+	return stats, json.NewDecoder(resp.Body).Decode(stats)
+}
+
+func pct(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
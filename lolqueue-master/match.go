@@ -82,6 +82,9 @@ type Player struct {
 	Rank              Rank     `json:"rank"`
 	Criteria          Criteria `json:"criteria"`
 	InvitationPending bool     `json:"invite_pending"`
+
+	// QueuedAt is when the player was last activated (joined the queue).
+	QueuedAt time.Time `json:"-"`
 }
 
 func (p *Player) Key() string {
@@ -0,0 +1,90 @@
+package lolqueue
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"golang.org/x/net/context"
+
+	"github.com/VantageSports/common/log"
+)
+
+// MatchMadeEvent is published to the "match made" topic whenever a group of
+// queued players accepts a match. Downstream consumers use it to prioritize
+// observing the roster's summoners, to tag the resulting game as a Vantage
+// Queue game, and to generate team-level reports for the group.
+type MatchMadeEvent struct {
+	QueueMatchID int64          `json:"queue_match_id"`
+	Region       string         `json:"region"`
+	Invited      time.Time      `json:"invited"`
+	Made         time.Time      `json:"made"`
+	Roster       []RosterMember `json:"roster"`
+}
+
+// RosterMember is a single player that accepted a made match, along with the
+// position they were assigned ("any" if they had no preference).
+type RosterMember struct {
+	SummonerID   int64    `json:"summoner_id"`
+	SummonerName string   `json:"summoner_name"`
+	Region       string   `json:"region"`
+	Rank         Rank     `json:"rank"`
+	Position     position `json:"position"`
+}
+
+// NewMatchMadeEvent builds the event for a pending match that has been made.
+// The made match holds the players that accepted and their positions, which
+// are assigned in the same order as its invited players.
+func NewMatchMadeEvent(pending, made *Match) *MatchMadeEvent {
+	event := &MatchMadeEvent{
+		QueueMatchID: pending.ID,
+		Invited:      pending.Started,
+		Made:         made.Started,
+		Roster:       make([]RosterMember, 0, len(made.Invited)),
+	}
+
+	for i, p := range made.Invited {
+		pos := any
+		if i < len(made.Positions) {
+			pos = made.Positions[i]
+		}
+		if event.Region == "" {
+			event.Region = p.Region
+		}
+		event.Roster = append(event.Roster, RosterMember{
+			SummonerID:   p.SummonerID,
+			SummonerName: p.SummonerName,
+			Region:       p.Region,
+			Rank:         p.Rank,
+			Position:     pos,
+		})
+	}
+	return event
+}
+
+// publishMatchMade sends the event to the match made topic, retrying a few
+// times before giving up. It is a nop if no topic is configured.
+func publishMatchMade(topic *pubsub.Topic, event *MatchMadeEvent) error {
+	if topic == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		_, err = topic.Publish(ctx, &pubsub.Message{Data: data})
+		cancel()
+		if err == nil {
+			log.Info(fmt.Sprintf("published match made event for queue match %d", event.QueueMatchID))
+			return nil
+		}
+		log.Warning(fmt.Sprintf("error publishing match made event (attempt %d): %v", attempt, err))
+		time.Sleep(time.Second * time.Duration(attempt))
+	}
+	return err
+}
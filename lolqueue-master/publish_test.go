@@ -0,0 +1,36 @@
+package lolqueue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewMatchMadeEvent(t *testing.T) {
+	p1 := &Player{SummonerName: "p1", SummonerID: 1, Region: "na", Rank: r("gold", "iii")}
+	p2 := &Player{SummonerName: "p2", SummonerID: 2, Region: "na", Rank: r("silver", "i")}
+	p3 := &Player{SummonerName: "p3", SummonerID: 3, Region: "na", Rank: r("silver", "v")}
+
+	invited := time.Now().Add(-time.Second * 10)
+	pending := &Match{ID: 42, Started: invited, Invited: []*Player{p1, p2, p3}}
+	made := &Match{ID: -1, Started: time.Now(), Invited: []*Player{p1, p3}, Positions: []position{Jungle, any}}
+
+	event := NewMatchMadeEvent(pending, made)
+	if event.QueueMatchID != 42 {
+		t.Errorf("expected queue match id 42, got %d", event.QueueMatchID)
+	}
+	if event.Region != "na" {
+		t.Errorf("expected region na, got %s", event.Region)
+	}
+	if !event.Invited.Equal(invited) || !event.Made.Equal(made.Started) {
+		t.Error("expected invite and made times to come from the pending and made matches")
+	}
+	if len(event.Roster) != 2 {
+		t.Fatalf("expected 2 roster members, got %d", len(event.Roster))
+	}
+	if event.Roster[0].SummonerID != 1 || event.Roster[0].Position != Jungle {
+		t.Error("expected p1 to play jungle. got:", event.Roster[0])
+	}
+	if event.Roster[1].SummonerID != 3 || event.Roster[1].Position != any {
+		t.Error("expected p3 to play any. got:", event.Roster[1])
+	}
+}
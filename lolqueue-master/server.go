@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"cloud.google.com/go/pubsub"
 	"golang.org/x/net/websocket"
 
 	"github.com/VantageSports/common/log"
@@ -18,12 +19,25 @@ type state struct {
 	pendingInvitations map[int64]bool      // by summonerID
 	pendingMatches     map[int64]*Match    // by matchID
 
+	// matchMadeTopic receives a MatchMadeEvent for every made match. It may be
+	// nil, in which case no events are published.
+	matchMadeTopic *pubsub.Topic
+
 	// housekeeping
 	lock            *sync.RWMutex
 	matchID         int64
 	lastMatchSearch time.Time
+
+	// counters reported by the admin api.
+	matchesMade     int64
+	matchesFailed   int64
+	matchSearchTook []time.Duration // most recent searches, oldest first
 }
 
+// maxSearchTimings is the number of recent match searches whose durations are
+// kept for reporting matcher latency.
+const maxSearchTimings = 100
+
 func (s *state) clientsForPlayer(player *Player) []*Client {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -95,11 +109,22 @@ func (s *state) rsvp(p *Player, accept bool) {
 		log.Warning(fmt.Sprintf("no match found for player:", p.SummonerName))
 		return
 	}
+	s.rsvpMatch(match, p, accept)
+}
+
+// rsvpMatch records the player's answer to the match, unless it is no longer
+// pending (e.g. it was canceled, and the player may since have been invited
+// to another).
+func (s *state) rsvpMatch(match *Match, p *Player, accept bool) {
 	defer s.checkIfMatchMade(match.ID)
 
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	if s.pendingMatches[match.ID] != match {
+		log.Info(fmt.Sprintf("ignoring rsvp for match %d by %s: no longer pending", match.ID, p.SummonerName))
+		return
+	}
 	log.Info(fmt.Sprintf("rsvp received for match %d by %s: %t", match.ID, p.SummonerName, accept))
 
 	match.Accepted[p.Key()] = accept
@@ -140,6 +165,17 @@ func (s *state) checkIfMatchMade(matchID int64) {
 	// we don't need to worry about dealing with the people that did not
 	// rsvp since they will have been disconnected automatically anyway.
 	madeMatch := IsViableMatch(participants)
+	if madeMatch == nil {
+		s.matchesFailed++
+	} else {
+		s.matchesMade++
+		event := NewMatchMadeEvent(match, madeMatch)
+		go func() {
+			if err := publishMatchMade(s.matchMadeTopic, event); err != nil {
+				log.Error(fmt.Sprintf("failed to publish match made event for queue match %d: %v", event.QueueMatchID, err))
+			}
+		}()
+	}
 	for _, p := range participants {
 		for _, client := range s.clientsByPlayer[p.SummonerID] {
 			if madeMatch != nil {
@@ -167,6 +203,7 @@ func (s *state) activate(p *Player) {
 
 	s.activePlayers.PushBack(p)
 	p.InvitationPending = false
+	p.QueuedAt = time.Now()
 }
 
 // lookForMatches should be run in a goroutine. It attempts to find matches,
@@ -185,8 +222,8 @@ func lookForMatches(s *state) {
 func (s *state) lookForMatch() bool {
 	start := time.Now()
 	defer func() {
-		s.lastMatchSearch = time.Now()
 		took := time.Since(start)
+		s.recordSearch(took)
 		if took.Seconds() > 10 {
 			log.Info(fmt.Sprintf("finished looking for matches in %v", took))
 		}
@@ -226,16 +263,41 @@ func (s *state) lookForMatch() bool {
 	return true
 }
 
+// recordSearch notes the completion of a match search that took the specified
+// duration.
+func (s *state) recordSearch(took time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.lastMatchSearch = time.Now()
+	s.matchSearchTook = append(s.matchSearchTook, took)
+	if len(s.matchSearchTook) > maxSearchTimings {
+		s.matchSearchTook = s.matchSearchTook[len(s.matchSearchTook)-maxSearchTimings:]
+	}
+}
+
 func handleMatchTimeout(s *state, match *Match) {
 	time.Sleep(time.Second * 15)
+	expireMatch(s, match)
+}
 
-	for _, p := range match.Invited {
-		key := p.Key()
-		// RSVP "no" for everyone that hasn't yet responded.
-		if _, found := match.Accepted[key]; !found {
-			s.rsvp(p, false)
+// expireMatch RSVPs "no" for everyone that hasn't yet responded to the match,
+// if it is still pending.
+func expireMatch(s *state, match *Match) {
+	s.lock.RLock()
+	unanswered := []*Player{}
+	if s.pendingMatches[match.ID] == match {
+		for _, p := range match.Invited {
+			if _, found := match.Accepted[p.Key()]; !found {
+				unanswered = append(unanswered, p)
+			}
 		}
 	}
+	s.lock.RUnlock()
+
+	for _, p := range unanswered {
+		s.rsvpMatch(match, p, false)
+	}
 }
 
 //
@@ -247,13 +309,17 @@ type Server struct {
 	vantageUtil *VantageUtil
 }
 
-func NewServer(vutil *VantageUtil) *Server {
+// NewServer returns a server that starts looking for matches immediately. If
+// matchMadeTopic is non-nil, an event describing each made match is published
+// to it.
+func NewServer(vutil *VantageUtil, matchMadeTopic *pubsub.Topic) *Server {
 	s := &Server{
 		state: &state{
 			activePlayers:      list.New(),            // fifo ordering
 			clientsByPlayer:    map[int64][]*Client{}, // by summonerID
 			pendingInvitations: map[int64]bool{},      // by summonerID
 			pendingMatches:     map[int64]*Match{},    // by matchID
+			matchMadeTopic:     matchMadeTopic,
 			lock:               &sync.RWMutex{},
 		},
 		vantageUtil: vutil,
@@ -4,12 +4,14 @@ package lolqueue
 // internal vantage APIs to ascertain identity information.
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"golang.org/x/net/context"
 
+	"github.com/VantageSports/common/constants/privileges"
 	"github.com/VantageSports/lolusers"
 	"github.com/VantageSports/payment"
 	"github.com/VantageSports/riot"
@@ -40,6 +42,20 @@ func (vu *VantageUtil) UserID(token string) (string, error) {
 	return claimsRes.Claims.Sub, nil
 }
 
+// IsAdmin returns true iff the token belongs to a user with god privileges,
+// which are required to use the admin api.
+func (vu *VantageUtil) IsAdmin(token string) (bool, error) {
+	if vu.Fake {
+		return true, nil
+	}
+
+	claimsRes, err := vu.Users.CheckToken(context.Background(), &users.TokenRequest{Token: token})
+	if err != nil {
+		return false, err
+	}
+	return claimsRes.Claims.Privileges[privileges.VantageGod], nil
+}
+
 // HasAccess returns true iff the user is paying for elite.
 func (vu *VantageUtil) HasAccess(token, userID string) (bool, error) {
 	if vu.Fake {
@@ -72,9 +88,29 @@ var fakeSummoners = []*Player{
 	{SummonerName: "Scotty2Hotty10", SummonerID: 530774, Rank: Rank{Tier: "Bronze", Division: "V"}, Region: "na"},
 }
 
+// fakeTokenPrefix marks tokens that describe a synthetic player, which are
+// only honored when running in fake mode.
+const fakeTokenPrefix = "fake:"
+
+// FakeToken returns a token that, when sent to a server running in fake mode,
+// identifies the client as the supplied player. It allows simulated clients
+// to join the queue as many distinct summoners.
+func FakeToken(p *Player) (string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return fakeTokenPrefix + string(data), nil
+}
+
 // Vantage Util Functions
 func (vu *VantageUtil) Player(token, userID string) (*Player, error) {
 	if vu.Fake {
+		if strings.HasPrefix(token, fakeTokenPrefix) {
+			p := &Player{}
+			err := json.Unmarshal([]byte(strings.TrimPrefix(token, fakeTokenPrefix)), p)
+			return p, err
+		}
 		fakeIndex = (fakeIndex + 1) % len(fakeSummoners)
 		return fakeSummoners[fakeIndex], nil
 	}
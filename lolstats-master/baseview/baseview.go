@@ -51,74 +51,79 @@ type Participant struct {
 }
 
 type Baseview struct {
+	// Version is the version of the schema the baseview conforms to. See
+	// CurrentVersion.
+	Version  int       `json:"version"`
+	Producer *Producer `json:"producer,omitempty"`
+	// MapID is the map the match was played on, e.g. MapSummonersRift.
+	MapID        int64         `json:"map_id"`
 	LastUpdated  time.Time     `json:"last_updated"`
 	Participants []Participant `json:"participants"`
 	Events       []Event       `json:"events"`
 }
 
+// UnmarshalJSON decodes the baseview, upgrading documents of older versions
+// of the schema. Documents of newer versions are decoded as far as possible;
+// use Read with the Strict option to reject them instead.
 func (b *Baseview) UnmarshalJSON(data []byte) error {
-	in := struct {
-		Updated      time.Time         `json:"last_updated"`
-		Participants []Participant     `json:"participants"`
-		Events       []json.RawMessage `json:"events"`
-	}{}
-	if err := json.Unmarshal(data, &in); err != nil {
-		return err
-	}
-	events := []Event{}
-	for _, msg := range in.Events {
-		event, err := parseEvent(msg)
-		if err != nil {
-			return err
-		}
-		events = append(events, event)
-	}
-	b.LastUpdated = in.Updated
-	b.Participants = in.Participants
-	b.Events = events
-	return nil
+	return unmarshalDocument(data, b, false)
 }
 
-func parseEvent(msg json.RawMessage) (Event, error) {
-	m := map[string]interface{}{}
-	if err := json.Unmarshal(msg, &m); err != nil {
-		return nil, err
-	}
-	var e Event
-	switch m["event"].(string) {
+// newEvent returns an empty event of the event type, or nil if the type is
+// unknown.
+func newEvent(eventType string) Event {
+	switch eventType {
 	case "attack":
-		e = &Attack{}
+		return &Attack{}
 	case "building_kill":
-		e = &BuildingKill{}
+		return &BuildingKill{}
 	case "damage":
-		e = &Damage{}
+		return &Damage{}
 	case "death":
-		e = &Death{}
+		return &Death{}
 	case "epic_monster_kill":
-		e = &EpicMonsterKill{}
+		return &EpicMonsterKill{}
 	case "game_end":
-		e = &GameEnd{}
+		return &GameEnd{}
 	case "item_used":
-		e = &ItemUsed{}
+		return &ItemUsed{}
 	case "level_up":
-		e = &LevelUp{}
+		return &LevelUp{}
 	case "slot_upgrade":
-		e = &SlotUpgrade{}
+		return &SlotUpgrade{}
 	case "state_update":
-		e = &StateUpdate{}
+		return &StateUpdate{}
 	case "spawn":
-		e = &Spawn{}
+		return &Spawn{}
 	case "ward_placed":
-		e = &WardPlaced{}
+		return &WardPlaced{}
 	case "ward_death":
-		e = &WardDeath{}
-	default:
-		return nil, fmt.Errorf("unknown event type: %v", m["event"])
+		return &WardDeath{}
+	}
+	return nil
+}
+
+func parseEvent(msg json.RawMessage) (Event, error) {
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(msg, &m); err != nil {
+		return nil, err
+	}
+	e := newEvent(fmt.Sprint(m["event"]))
+	if e == nil {
+		return nil, &unknownEventError{m["event"]}
 	}
 	err := json.Unmarshal(msg, e)
 	return e, err
 }
 
+type unknownEventError struct {
+	eventType interface{}
+}
+
+func (e *unknownEventError) Error() string {
+	return fmt.Sprintf("unknown event type: %v", e.eventType)
+}
+
 func MatchDuration(events []Event) float64 {
 	for i := len(events) - 1; i >= 0; i-- {
 		switch t := events[i].(type) {
@@ -136,17 +141,24 @@ type Event interface {
 	SetType(string)
 	Seconds() float64
 	SetSeconds(float64)
+	// Source is where the event came from (e.g. SourceOCR) in a baseview
+	// merged from several, and empty otherwise.
+	Source() string
+	SetSource(string)
 }
 
 type baseEvent struct {
 	EventType_ string  `json:"event" mapstructure:"event"`
 	Seconds_   float64 `json:"seconds" mapstructure:"seconds"`
+	Source_    string  `json:"source,omitempty" mapstructure:"source"`
 }
 
 func (be *baseEvent) Type() string         { return be.EventType_ }
 func (be *baseEvent) SetType(s string)     { be.EventType_ = s }
 func (be *baseEvent) Seconds() float64     { return be.Seconds_ }
 func (be *baseEvent) SetSeconds(s float64) { be.Seconds_ = s }
+func (be *baseEvent) Source() string       { return be.Source_ }
+func (be *baseEvent) SetSource(s string)   { be.Source_ = s }
 
 type Attack struct {
 	baseEvent
@@ -198,6 +210,7 @@ type ItemUsed struct {
 	baseEvent
 	Slot            string  `json:"slot"`
 	CooldownExpires float64 `json:"cooldown_expires"`
+	ParticipantID   int64   `json:"participant_id"`
 }
 
 type LevelUp struct {
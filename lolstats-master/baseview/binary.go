@@ -0,0 +1,795 @@
+package baseview
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+)
+
+// The binary encoding is a compact alternative to json. Most of a baseview
+// is state updates, which repeat the same fields for every participant on
+// every tick, so:
+//
+//   - numbers are written as varint deltas from the previous value of the
+//     same field (state updates keep their previous values per participant).
+//   - floats that are a whole number of hundredths are written as (deltas of)
+//     those hundredths, and otherwise as float32s or float64s, whichever is
+//     exact. Decoding always yields the identical float64.
+//   - strings (event types, slots, ward types, ...) are written once, and
+//     then referred to by their index in a string table.
+//
+// Fields that aren't delta encoded (like the ids of an attack) are written as
+// varint deltas from zero.
+//
+// Files start with binaryMagic followed by the version of the encoding.
+// Version 1 didn't include the schema version or producer of the baseview,
+// version 2 didn't include the map id, version 3 didn't include the sources
+// of events, and up to version 4 each field that isn't delta encoded was
+// written relative to the previous such field of the event (or participant),
+// rather than to zero.
+
+const (
+	binaryMagic   = "VSBV"
+	binaryVersion = 5
+
+	// the longest encoding of a time.Time (with a location offset).
+	maxTimeLength = 16
+)
+
+// float modes, stored in the low bits of a float's header.
+const (
+	floatHundredths = 0
+	floatFloat32    = 1
+	floatFloat64    = 2
+	floatModeBits   = 2
+)
+
+// event kinds, one per event struct.
+const (
+	kindAttack byte = iota + 1
+	kindBuildingKill
+	kindDamage
+	kindDeath
+	kindEpicMonsterKill
+	kindGameEnd
+	kindItemUsed
+	kindLevelUp
+	kindSlotUpgrade
+	kindStateUpdate
+	kindSpawn
+	kindWardPlaced
+	kindWardDeath
+
+	// kindSourced is set on the kind of events with a source, which follows
+	// the event type.
+	kindSourced byte = 0x80
+)
+
+// state update flags.
+const (
+	flagInGrass byte = 1 << iota
+	flagUnderOwnTurret
+	flagUnderEnemyTurret
+)
+
+// attack flags, set when the corresponding position is present. Positions
+// are written in this order.
+const (
+	flagStart byte = 1 << iota
+	flagEnd
+	flagTargetPosition
+)
+
+// IsBinary returns true if data starts like a binary encoded baseview.
+func IsBinary(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(binaryMagic))
+}
+
+// MarshalBinary encodes the baseview in the compact binary encoding.
+func (b *Baseview) MarshalBinary() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := WriteBinary(buf, b); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a baseview encoded by MarshalBinary, upgrading
+// older versions of the schema like UnmarshalJSON.
+func (b *Baseview) UnmarshalBinary(data []byte) error {
+	return readBinary(bufio.NewReader(bytes.NewReader(data)), b, false)
+}
+
+// Clone returns a deep copy of the baseview.
+func (b *Baseview) Clone() (*Baseview, error) {
+	data, err := b.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	c := &Baseview{}
+	return c, c.UnmarshalBinary(data)
+}
+
+// WriteBinary writes the baseview to w in the compact binary encoding.
+func WriteBinary(w io.Writer, b *Baseview) error {
+	bw := bufio.NewWriter(w)
+	e := newEncoder(bw)
+	e.bytes([]byte(binaryMagic))
+	e.uvarint(binaryVersion)
+
+	updated, err := b.LastUpdated.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	e.uvarint(uint64(len(updated)))
+	e.bytes(updated)
+
+	e.uvarint(uint64(b.Version))
+	if b.Producer == nil {
+		e.bool(false)
+	} else {
+		e.bool(true)
+		e.string(b.Producer.Source)
+		e.string(b.Producer.ConverterVersion)
+		e.string(b.Producer.GamePatch)
+	}
+	e.varint(b.MapID)
+
+	e.uvarint(uint64(len(b.Participants)))
+	for _, p := range b.Participants {
+		e.participant(p)
+	}
+	e.uvarint(uint64(len(b.Events)))
+	for _, event := range b.Events {
+		if err := e.event(event); err != nil {
+			return err
+		}
+	}
+	if e.err != nil {
+		return e.err
+	}
+	return bw.Flush()
+}
+
+// ReadOption configures how Read decodes baseviews.
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	strict bool
+}
+
+// Strict makes Read reject baseviews of versions newer than CurrentVersion,
+// rather than dropping what it doesn't know.
+func Strict() ReadOption {
+	return func(o *readOptions) { o.strict = true }
+}
+
+// Read decodes a baseview from r, in either the json or the binary encoding.
+// Older versions of the schema are upgraded to CurrentVersion.
+func Read(r io.Reader, options ...ReadOption) (*Baseview, error) {
+	o := &readOptions{}
+	for _, option := range options {
+		option(o)
+	}
+	br := bufio.NewReader(r)
+	b := &Baseview{}
+	prefix, err := br.Peek(len(binaryMagic))
+	if err == nil && IsBinary(prefix) {
+		return b, readBinary(br, b, o.strict)
+	}
+	data, err := ioutil.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	return b, unmarshalDocument(data, b, o.strict)
+}
+
+// ReadFile decodes the baseview in the file, like Read.
+func ReadFile(filename string, options ...ReadOption) (*Baseview, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f, options...)
+}
+
+func readBinary(r *bufio.Reader, b *Baseview, strict bool) error {
+	d := newDecoder(r)
+	magic := make([]byte, len(binaryMagic))
+	d.read(magic)
+	if d.err == nil && string(magic) != binaryMagic {
+		return errors.New("not a binary baseview")
+	}
+	version := d.uvarint()
+	d.version = version
+	if d.err == nil && (version < 1 || version > binaryVersion) {
+		return fmt.Errorf("unsupported binary baseview version: %d", version)
+	}
+
+	n := d.length()
+	if d.err == nil && n > maxTimeLength {
+		return fmt.Errorf("invalid time length: %d", n)
+	}
+	updated := make([]byte, n)
+	d.read(updated)
+	if d.err == nil {
+		if err := b.LastUpdated.UnmarshalBinary(updated); err != nil {
+			return err
+		}
+	}
+
+	b.Version = legacyVersion
+	b.Producer = nil
+	if version >= 2 {
+		b.Version = d.length()
+		if d.byte() != 0 {
+			b.Producer = &Producer{Source: d.string(), ConverterVersion: d.string(), GamePatch: d.string()}
+		}
+	}
+	b.MapID = 0
+	if version >= 3 {
+		b.MapID = d.varint()
+	}
+
+	n = d.length()
+	b.Participants = make([]Participant, 0, preallocated(n))
+	for i := 0; i < n && d.err == nil; i++ {
+		b.Participants = append(b.Participants, d.participant())
+	}
+	n = d.length()
+	b.Events = make([]Event, 0, preallocated(n))
+	for i := 0; i < n && d.err == nil; i++ {
+		b.Events = append(b.Events, d.event())
+	}
+	if d.err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if d.err != nil {
+		return d.err
+	}
+	return b.upgrade(strict)
+}
+
+// preallocated limits how much is allocated up front for n elements, so that
+// a corrupt length fails at the end of the data rather than running out of
+// memory.
+func preallocated(n int) int {
+	if n > 1<<16 {
+		return 1 << 16
+	}
+	return n
+}
+
+// stateFields are the previous values of a participant's state update, that
+// the next one is delta encoded against. Floats are kept as hundredths.
+type stateFields struct {
+	gold, minions, neutralMinions int64
+	health, healthMax             int64
+	mana, manaMax                 int64
+	x, y, z                       int64
+}
+
+// fields are the previous values shared by both the encoder and decoder.
+type fields struct {
+	strings map[string]uint64
+	table   []string
+
+	seconds int64
+	state   map[int64]*stateFields
+}
+
+func newFields() fields {
+	return fields{strings: map[string]uint64{}, state: map[int64]*stateFields{}}
+}
+
+func (f *fields) stateOf(participantID int64) *stateFields {
+	s := f.state[participantID]
+	if s == nil {
+		s = &stateFields{}
+		f.state[participantID] = s
+	}
+	return s
+}
+
+type encoder struct {
+	fields
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func newEncoder(w *bufio.Writer) *encoder {
+	return &encoder{fields: newFields(), w: w}
+}
+
+func (e *encoder) bytes(b []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(b)
+	}
+}
+
+func (e *encoder) uvarint(v uint64) {
+	e.bytes(e.buf[:binary.PutUvarint(e.buf[:], v)])
+}
+
+func (e *encoder) varint(v int64) {
+	e.bytes(e.buf[:binary.PutVarint(e.buf[:], v)])
+}
+
+// int writes the delta of v from the previous value of the field.
+func (e *encoder) int(prev *int64, v int64) {
+	e.varint(v - *prev)
+	*prev = v
+}
+
+// value writes v (of a field that isn't delta encoded) relative to zero.
+func (e *encoder) value(v int64) {
+	var zero int64
+	e.int(&zero, v)
+}
+
+// floatValue writes v (of a field that isn't delta encoded) relative to zero.
+func (e *encoder) floatValue(v float64) {
+	var zero int64
+	e.float(&zero, v)
+}
+
+// float writes v as the delta of its hundredths from the previous value of
+// the field if that is exact, or else as a float32 or float64.
+func (e *encoder) float(prev *int64, v float64) {
+	if h, ok := hundredths(v); ok {
+		e.varint((h - *prev) << floatModeBits)
+		*prev = h
+		return
+	}
+	if f32 := float32(v); math.Float64bits(float64(f32)) == math.Float64bits(v) {
+		e.varint(floatFloat32)
+		binary.LittleEndian.PutUint32(e.buf[:4], math.Float32bits(f32))
+		e.bytes(e.buf[:4])
+		return
+	}
+	e.varint(floatFloat64)
+	binary.LittleEndian.PutUint64(e.buf[:8], math.Float64bits(v))
+	e.bytes(e.buf[:8])
+}
+
+// hundredths returns v as a whole number of hundredths, if that's exact.
+// It is limited to 2^52, so that deltas always fit alongside the mode bits.
+func hundredths(v float64) (int64, bool) {
+	h := math.Round(v * 100)
+	if math.IsNaN(h) || math.Abs(h) > 1<<52 {
+		return 0, false
+	}
+	n := int64(h)
+	return n, math.Float64bits(float64(n)/100) == math.Float64bits(v)
+}
+
+// string writes the index of s in the string table, followed by s itself
+// the first time it is written.
+func (e *encoder) string(s string) {
+	if i, ok := e.strings[s]; ok {
+		e.uvarint(i)
+		return
+	}
+	i := uint64(len(e.table))
+	e.strings[s] = i
+	e.table = append(e.table, s)
+	e.uvarint(i)
+	e.uvarint(uint64(len(s)))
+	e.bytes([]byte(s))
+}
+
+func (e *encoder) bool(b bool) {
+	if b {
+		e.bytes([]byte{1})
+	} else {
+		e.bytes([]byte{0})
+	}
+}
+
+func (e *encoder) position(prev *[3]int64, p Position) {
+	e.float(&prev[0], p.X)
+	e.float(&prev[1], p.Y)
+	e.float(&prev[2], p.Z)
+}
+
+func (e *encoder) participant(p Participant) {
+	e.value(p.ParticipantID)
+	e.value(p.SummonerID)
+	e.string(p.SummonerName)
+	e.value(p.ChampionID)
+	e.string(p.Tier)
+	e.string(p.Division)
+	e.string(p.Spell1)
+	e.string(p.Spell2)
+}
+
+func (e *encoder) event(event Event) error {
+	kind, err := kindOf(event)
+	if err != nil {
+		return err
+	}
+	if event.Source() != "" {
+		kind |= kindSourced
+	}
+	e.bytes([]byte{kind})
+	e.string(event.Type())
+	if event.Source() != "" {
+		e.string(event.Source())
+	}
+	e.float(&e.seconds, event.Seconds())
+
+	var pos [3]int64
+	switch t := event.(type) {
+	case *Attack:
+		var flags byte
+		if t.Start != nil {
+			flags |= flagStart
+		}
+		if t.End != nil {
+			flags |= flagEnd
+		}
+		if t.TargetPosition != nil {
+			flags |= flagTargetPosition
+		}
+		e.bytes([]byte{flags})
+		e.floatValue(t.CooldownExpires)
+		for _, p := range []*Position{t.Start, t.End, t.TargetPosition} {
+			if p != nil {
+				e.position(&pos, *p)
+			}
+		}
+		e.value(t.AttackerID)
+		e.string(string(t.AttackerType))
+		e.string(t.Slot)
+		e.value(t.TargetID)
+		e.string(string(t.TargetType))
+	case *BuildingKill:
+		e.string(string(t.BuildingType))
+		e.value(t.BuildingID)
+	case *Damage:
+		e.floatValue(t.Total)
+		e.floatValue(t.Percent)
+		e.value(t.AttackerID)
+		e.string(string(t.AttackerType))
+		e.value(t.VictimID)
+		e.string(string(t.VictimType))
+	case *Death:
+		e.position(&pos, t.Position)
+		e.value(t.VictimID)
+	case *EpicMonsterKill:
+		e.value(t.VictimID)
+		e.value(t.KillerID)
+	case *GameEnd:
+		e.string(t.Reason)
+	case *ItemUsed:
+		e.string(t.Slot)
+		e.floatValue(t.CooldownExpires)
+		e.value(t.ParticipantID)
+	case *LevelUp:
+		e.value(t.Level)
+		e.value(t.ParticipantID)
+	case *SlotUpgrade:
+		e.value(t.Level)
+		e.string(t.Slot)
+		e.value(t.ParticipantID)
+	case *StateUpdate:
+		e.value(t.ParticipantID)
+		s := e.stateOf(t.ParticipantID)
+		var flags byte
+		if t.InGrass {
+			flags |= flagInGrass
+		}
+		if t.UnderOwnTurret {
+			flags |= flagUnderOwnTurret
+		}
+		if t.UnderEnemyTurret {
+			flags |= flagUnderEnemyTurret
+		}
+		e.bytes([]byte{flags})
+		e.int(&s.gold, t.Gold)
+		e.int(&s.minions, t.MinionsKilled)
+		e.int(&s.neutralMinions, t.NeutralMinionsKilled)
+		e.float(&s.health, t.Health)
+		e.float(&s.healthMax, t.HealthMax)
+		e.float(&s.mana, t.Mana)
+		e.float(&s.manaMax, t.ManaMax)
+		e.float(&s.x, t.Position.X)
+		e.float(&s.y, t.Position.Y)
+		e.float(&s.z, t.Position.Z)
+	case *Spawn:
+		e.value(t.ParticipantID)
+		e.position(&pos, t.Position)
+	case *WardPlaced:
+		e.string(t.WardType)
+		e.string(string(t.WardItemType))
+		e.value(t.ParticipantID)
+		e.position(&pos, t.Position)
+		e.value(t.TeamID)
+		e.value(t.WardID)
+	case *WardDeath:
+		e.value(t.WardID)
+	}
+	return e.err
+}
+
+func kindOf(event Event) (byte, error) {
+	switch event.(type) {
+	case *Attack:
+		return kindAttack, nil
+	case *BuildingKill:
+		return kindBuildingKill, nil
+	case *Damage:
+		return kindDamage, nil
+	case *Death:
+		return kindDeath, nil
+	case *EpicMonsterKill:
+		return kindEpicMonsterKill, nil
+	case *GameEnd:
+		return kindGameEnd, nil
+	case *ItemUsed:
+		return kindItemUsed, nil
+	case *LevelUp:
+		return kindLevelUp, nil
+	case *SlotUpgrade:
+		return kindSlotUpgrade, nil
+	case *StateUpdate:
+		return kindStateUpdate, nil
+	case *Spawn:
+		return kindSpawn, nil
+	case *WardPlaced:
+		return kindWardPlaced, nil
+	case *WardDeath:
+		return kindWardDeath, nil
+	}
+	return 0, fmt.Errorf("unknown event: %T", event)
+}
+
+// decoder mirrors the encoder. Once it has failed, it returns zero values
+// and keeps the first error.
+type decoder struct {
+	fields
+	r   *bufio.Reader
+	buf [8]byte
+	err error
+
+	// version is the version of the encoding, and chained the previous field
+	// that isn't delta encoded, which up to version 4 the next one was written
+	// relative to.
+	version uint64
+	chained int64
+}
+
+func newDecoder(r *bufio.Reader) *decoder {
+	return &decoder{fields: newFields(), r: r}
+}
+
+func (d *decoder) read(b []byte) {
+	if d.err == nil {
+		_, d.err = io.ReadFull(d.r, b)
+	}
+}
+
+func (d *decoder) byte() byte {
+	d.read(d.buf[:1])
+	return d.buf[0]
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	d.err = err
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(d.r)
+	d.err = err
+	return v
+}
+
+// length reads the length of a string or list.
+func (d *decoder) length() int {
+	n := d.uvarint()
+	if d.err == nil && n > math.MaxInt32 {
+		d.err = fmt.Errorf("invalid length: %d", n)
+	}
+	if d.err != nil {
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) int(prev *int64) int64 {
+	*prev += d.varint()
+	return *prev
+}
+
+// value reads a field that isn't delta encoded.
+func (d *decoder) value() int64 {
+	return d.int(d.base())
+}
+
+// floatValue reads a float field that isn't delta encoded.
+func (d *decoder) floatValue() float64 {
+	return d.float(d.base())
+}
+
+// base returns what a field that isn't delta encoded was written relative to:
+// zero, or before version 5 the previous such field.
+func (d *decoder) base() *int64 {
+	if d.version < 5 {
+		return &d.chained
+	}
+	var zero int64
+	return &zero
+}
+
+func (d *decoder) float(prev *int64) float64 {
+	header := d.varint()
+	switch header & (1<<floatModeBits - 1) {
+	case floatHundredths:
+		*prev += header >> floatModeBits
+		return float64(*prev) / 100
+	case floatFloat32:
+		d.read(d.buf[:4])
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(d.buf[:4])))
+	case floatFloat64:
+		d.read(d.buf[:8])
+		return math.Float64frombits(binary.LittleEndian.Uint64(d.buf[:8]))
+	}
+	if d.err == nil {
+		d.err = fmt.Errorf("invalid float header: %d", header)
+	}
+	return 0
+}
+
+func (d *decoder) string() string {
+	i := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if i < uint64(len(d.table)) {
+		return d.table[i]
+	}
+	if i > uint64(len(d.table)) {
+		d.err = fmt.Errorf("invalid string index: %d", i)
+		return ""
+	}
+	b := make([]byte, d.length())
+	d.read(b)
+	s := string(b)
+	d.table = append(d.table, s)
+	return s
+}
+
+func (d *decoder) position(prev *[3]int64) Position {
+	return Position{X: d.float(&prev[0]), Y: d.float(&prev[1]), Z: d.float(&prev[2])}
+}
+
+func (d *decoder) positionPtr(prev *[3]int64) *Position {
+	p := d.position(prev)
+	return &p
+}
+
+func (d *decoder) participant() Participant {
+	d.chained = 0
+	return Participant{
+		ParticipantID: d.value(),
+		SummonerID:    d.value(),
+		SummonerName:  d.string(),
+		ChampionID:    d.value(),
+		Tier:          d.string(),
+		Division:      d.string(),
+		Spell1:        d.string(),
+		Spell2:        d.string(),
+	}
+}
+
+func (d *decoder) event() Event {
+	kind := d.byte()
+	eventType := d.string()
+	source := ""
+	if kind&kindSourced != 0 {
+		kind &^= kindSourced
+		source = d.string()
+	}
+	seconds := d.float(&d.seconds)
+
+	d.chained = 0
+	var pos [3]int64
+	var e Event
+	switch kind {
+	case kindAttack:
+		flags := d.byte()
+		t := &Attack{CooldownExpires: d.floatValue()}
+		if flags&flagStart != 0 {
+			t.Start = d.positionPtr(&pos)
+		}
+		if flags&flagEnd != 0 {
+			t.End = d.positionPtr(&pos)
+		}
+		if flags&flagTargetPosition != 0 {
+			t.TargetPosition = d.positionPtr(&pos)
+		}
+		t.AttackerID = d.value()
+		t.AttackerType = ActorType(d.string())
+		t.Slot = d.string()
+		t.TargetID = d.value()
+		t.TargetType = ActorType(d.string())
+		e = t
+	case kindBuildingKill:
+		e = &BuildingKill{BuildingType: ActorType(d.string()), BuildingID: d.value()}
+	case kindDamage:
+		e = &Damage{
+			Total:        d.floatValue(),
+			Percent:      d.floatValue(),
+			AttackerID:   d.value(),
+			AttackerType: ActorType(d.string()),
+			VictimID:     d.value(),
+			VictimType:   ActorType(d.string()),
+		}
+	case kindDeath:
+		e = &Death{Position: d.position(&pos), VictimID: d.value()}
+	case kindEpicMonsterKill:
+		e = &EpicMonsterKill{VictimID: d.value(), KillerID: d.value()}
+	case kindGameEnd:
+		e = &GameEnd{Reason: d.string()}
+	case kindItemUsed:
+		e = &ItemUsed{Slot: d.string(), CooldownExpires: d.floatValue(), ParticipantID: d.value()}
+	case kindLevelUp:
+		e = &LevelUp{Level: d.value(), ParticipantID: d.value()}
+	case kindSlotUpgrade:
+		e = &SlotUpgrade{Level: d.value(), Slot: d.string(), ParticipantID: d.value()}
+	case kindStateUpdate:
+		t := &StateUpdate{ParticipantID: d.value()}
+		s := d.stateOf(t.ParticipantID)
+		flags := d.byte()
+		t.InGrass = flags&flagInGrass != 0
+		t.UnderOwnTurret = flags&flagUnderOwnTurret != 0
+		t.UnderEnemyTurret = flags&flagUnderEnemyTurret != 0
+		t.Gold = d.int(&s.gold)
+		t.MinionsKilled = d.int(&s.minions)
+		t.NeutralMinionsKilled = d.int(&s.neutralMinions)
+		t.Health = d.float(&s.health)
+		t.HealthMax = d.float(&s.healthMax)
+		t.Mana = d.float(&s.mana)
+		t.ManaMax = d.float(&s.manaMax)
+		t.Position = Position{X: d.float(&s.x), Y: d.float(&s.y), Z: d.float(&s.z)}
+		e = t
+	case kindSpawn:
+		e = &Spawn{ParticipantID: d.value(), Position: d.position(&pos)}
+	case kindWardPlaced:
+		e = &WardPlaced{
+			WardType:      d.string(),
+			WardItemType:  WardItemType(d.string()),
+			ParticipantID: d.value(),
+			Position:      d.position(&pos),
+			TeamID:        d.value(),
+			WardID:        d.value(),
+		}
+	case kindWardDeath:
+		e = &WardDeath{WardID: d.value()}
+	default:
+		if d.err == nil {
+			d.err = fmt.Errorf("unknown event kind: %d", kind)
+		}
+		return nil
+	}
+	e.SetType(eventType)
+	e.SetSeconds(seconds)
+	e.SetSource(source)
+	return e
+}
@@ -0,0 +1,239 @@
+package baseview
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/tylerb/is.v1"
+)
+
+func event(e Event, eventType string, seconds float64) Event {
+	e.SetType(eventType)
+	e.SetSeconds(seconds)
+	return e
+}
+
+// change to true if you want to regenerate the golden files.
+var rewriteGolden = false
+
+// The golden encodings of roundTripBaseview. lolelo vendors this package, and
+// checks that its copy reads and writes them the same way.
+const (
+	goldenBinaryPath = "testdata/roundtrip.baseview.bin"
+	goldenJSONPath   = "testdata/roundtrip.baseview.json"
+)
+
+// roundTripBaseview returns a baseview with every type of event, and values
+// that are awkward to encode.
+func roundTripBaseview() *Baseview {
+	bv := &Baseview{
+		Version:     CurrentVersion,
+		MapID:       MapHowlingAbyss,
+		Producer:    &Producer{Source: SourceElo, ConverterVersion: "v2", GamePatch: "7.1.165.3566"},
+		LastUpdated: time.Date(2017, 2, 3, 4, 5, 6, 789, time.UTC),
+		Participants: []Participant{
+			{ParticipantID: 1, SummonerID: 123456, SummonerName: "someone", ChampionID: 64, Tier: "GOLD", Division: "II", Spell1: "Flash", Spell2: "Smite"},
+			{ParticipantID: 6, SummonerID: -1, SummonerName: "someone else", Spell1: "Flash"},
+		},
+		Events: []Event{
+			event(&Spawn{ParticipantID: 1, Position: Position{X: 400, Y: 400, Z: 182.13}}, "spawn", 0),
+			event(&StateUpdate{
+				Gold: 500, Health: 580.5, HealthMax: 580.5, Mana: 1.0 / 3, ManaMax: 300,
+				ParticipantID: 1, Position: Position{X: float64(float32(401.37)), Y: -0.0, Z: 1e300},
+				InGrass: true, UnderEnemyTurret: true,
+			}, "state_update", 1.5),
+			event(&StateUpdate{Gold: 480, MinionsKilled: 1, ParticipantID: 1, Position: Position{X: 380, Y: 400}}, "state_update", 2),
+			event(&StateUpdate{Gold: 500, ParticipantID: 6, UnderOwnTurret: true}, "state_update", 2),
+			event(&Attack{AttackerID: 1, AttackerType: ActorHero, Slot: "q", Start: &Position{X: 1, Y: 2}, TargetPosition: &Position{X: 3, Y: 4}, CooldownExpires: 12.25}, "attack", 3),
+			event(&Attack{AttackerID: 1, AttackerType: ActorHero, Slot: "basic", TargetID: 6, TargetType: ActorHero}, "attack", 3),
+			event(&Damage{Total: 123.456, Percent: 0.2, AttackerID: 1, AttackerType: ActorHero, VictimID: 6, VictimType: ActorHero}, "damage", 3.01),
+			event(&Death{Position: Position{X: 5000, Y: 5000}, VictimID: 6}, "death", 4),
+			event(&BuildingKill{BuildingType: ActorTurret, BuildingID: TurretRedMidOuter}, "building_kill", 600),
+			event(&EpicMonsterKill{VictimID: 10, KillerID: 1}, "epic_monster_kill", 700),
+			event(&ItemUsed{Slot: "item_1", CooldownExpires: 790, ParticipantID: 6}, "item_used", 710),
+			event(&LevelUp{Level: 2, ParticipantID: 1}, "level_up", 5),
+			event(&SlotUpgrade{Level: 1, Slot: "w", ParticipantID: 1}, "slot_upgrade", 5),
+			event(&WardPlaced{WardType: "YellowTrinket", WardItemType: YellowTrinket, ParticipantID: 1, Position: Position{X: 10, Y: 20}, TeamID: BlueTeam, WardID: 99}, "ward_placed", 100),
+			event(&WardDeath{WardID: 99}, "ward_death", 190),
+			event(&GameEnd{Reason: "nexus destroyed"}, "game_end", 1800),
+		},
+	}
+
+	bv.Events[4].SetSource(SourceOCR)
+	bv.Events[5].SetSource(SourceElo)
+	return bv
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	is := is.New(t)
+
+	bv := roundTripBaseview()
+	data, err := bv.MarshalBinary()
+	is.NotErr(err)
+	is.True(IsBinary(data))
+
+	decoded := &Baseview{}
+	is.NotErr(decoded.UnmarshalBinary(data))
+	is.True(reflect.DeepEqual(bv, decoded))
+	is.Equal(math.Float64bits(-0.0), math.Float64bits(decoded.Events[1].(*StateUpdate).Position.Y))
+
+	// Read accepts either encoding.
+	jsonData, err := json.Marshal(bv)
+	is.NotErr(err)
+	is.False(IsBinary(jsonData))
+	for _, encoded := range [][]byte{data, jsonData} {
+		read, err := Read(bytes.NewReader(encoded))
+		is.NotErr(err)
+		readJSON, err := json.Marshal(read)
+		is.NotErr(err)
+		is.Equal(string(jsonData), string(readJSON))
+	}
+
+	// truncated data is an error, rather than a partial baseview.
+	for _, n := range []int{0, 3, len(data) / 2, len(data) - 1} {
+		is.Err((&Baseview{}).UnmarshalBinary(data[:n]))
+	}
+
+	clone, err := bv.Clone()
+	is.NotErr(err)
+	is.True(reflect.DeepEqual(bv, clone))
+	clone.Events[0].SetSeconds(100)
+	is.NotEqual(100.0, bv.Events[0].Seconds())
+}
+
+func TestBinaryGolden(t *testing.T) {
+	is := is.New(t)
+
+	data, err := roundTripBaseview().MarshalBinary()
+	is.NotErr(err)
+	jsonData, err := json.MarshalIndent(roundTripBaseview(), "", "  ")
+	is.NotErr(err)
+	if rewriteGolden {
+		is.NotErr(ioutil.WriteFile(goldenBinaryPath, data, 0664))
+		is.NotErr(ioutil.WriteFile(goldenJSONPath, jsonData, 0664))
+		t.Error("rewrote golden files. test is invalid")
+	}
+
+	golden, err := ioutil.ReadFile(goldenBinaryPath)
+	is.NotErr(err)
+	is.True(bytes.Equal(golden, data))
+	golden, err = ioutil.ReadFile(goldenJSONPath)
+	is.NotErr(err)
+	is.Equal(string(golden), string(jsonData))
+}
+
+func TestBinaryUnknownEvent(t *testing.T) {
+	is := is.New(t)
+
+	type custom struct{ baseEvent }
+	_, err := (&Baseview{Events: []Event{&custom{}}}).MarshalBinary()
+	is.Err(err)
+}
+
+func TestBinaryValueBase(t *testing.T) {
+	is := is.New(t)
+
+	var buf []byte
+	for _, v := range []int64{3, 2, -1} {
+		b := make([]byte, binary.MaxVarintLen64)
+		buf = append(buf, b[:binary.PutVarint(b, v)]...)
+	}
+	read := func(version uint64) []int64 {
+		d := newDecoder(bufio.NewReader(bytes.NewReader(buf)))
+		d.version = version
+		return []int64{d.value(), d.value(), d.value()}
+	}
+	// up to version 4, each value was written relative to the previous one.
+	is.Equal([]int64{3, 5, 4}, read(4))
+	is.Equal([]int64{3, 2, -1}, read(binaryVersion))
+}
+
+// testBaseview returns a baseview the size of a 30 minute match, with a state
+// update for each participant every second.
+func testBaseview() *Baseview {
+	r := rand.New(rand.NewSource(1))
+	bv := &Baseview{Version: CurrentVersion, LastUpdated: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)}
+	states := map[int64]*StateUpdate{}
+	for id := int64(1); id <= 10; id++ {
+		bv.Participants = append(bv.Participants, Participant{ParticipantID: id, SummonerID: 1000 + id, SummonerName: "summoner", ChampionID: id})
+		states[id] = &StateUpdate{ParticipantID: id, Health: 600, HealthMax: 600, Mana: 300, ManaMax: 300, Gold: 500}
+	}
+	for second := 1; second <= 1800; second++ {
+		for id := int64(1); id <= 10; id++ {
+			s := *states[id]
+			s.Position.X = float64(float32(s.Position.X + r.Float64()*300))
+			s.Position.Y = float64(float32(s.Position.Y + r.Float64()*300))
+			s.Position.Z = float64(float32(r.Float64() * 100))
+			s.Health = math.Min(s.HealthMax, math.Floor(s.Health+r.Float64()*20-10))
+			s.Gold += int64(r.Intn(5))
+			s.InGrass = r.Intn(10) == 0
+			states[id] = &s
+			bv.Events = append(bv.Events, event(&s, "state_update", float64(second)))
+		}
+		if second%10 == 0 {
+			bv.Events = append(bv.Events, event(&Attack{AttackerID: 1, AttackerType: ActorHero, Slot: "basic", TargetID: 6, TargetType: ActorHero}, "attack", float64(second)+0.25))
+			bv.Events = append(bv.Events, event(&Damage{Total: r.Float64() * 100, Percent: r.Float64(), AttackerID: 1, AttackerType: ActorHero, VictimID: 6, VictimType: ActorHero}, "damage", float64(second)+0.5))
+		}
+	}
+	return bv
+}
+
+func TestBinaryRoundTripMatch(t *testing.T) {
+	is := is.New(t)
+
+	bv := testBaseview()
+	data, err := bv.MarshalBinary()
+	is.NotErr(err)
+	jsonData, err := json.Marshal(bv)
+	is.NotErr(err)
+	is.True(len(data) < len(jsonData)/5)
+
+	decoded := &Baseview{}
+	is.NotErr(decoded.UnmarshalBinary(data))
+	is.True(reflect.DeepEqual(bv, decoded))
+}
+
+func BenchmarkDecodeJSON(b *testing.B) {
+	data, err := json.Marshal(testBaseview())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportMetric(float64(len(data)), "bytes")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Read(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeBinary(b *testing.B) {
+	data, err := testBaseview().MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportMetric(float64(len(data)), "bytes")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Read(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeBinary(b *testing.B) {
+	bv := testBaseview()
+	for i := 0; i < b.N; i++ {
+		if _, err := bv.MarshalBinary(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
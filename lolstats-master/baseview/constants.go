@@ -60,31 +60,10 @@ const (
 	InhibitorRedBot = 232
 )
 
-// Approximate Turret Locations (can't find an actual source for this)
-var TurretPositions map[int64]Position = map[int64]Position{
-	101: {X: 1364.939, Y: 6837.4, Z: 52.83815},
-	102: {X: 994, Y: 10434, Z: 52.8381},
-	103: {X: 1784.683, Y: 2325.73, Z: 95.74805},
-	104: {X: 2219.66, Y: 1777.356, Z: 95.74808},
-	105: {X: 3649.316, Y: 3670.658, Z: 95.74804},
-	106: {X: 5038.548, Y: 4957.532, Z: 50.23169},
-	107: {X: 5902, Y: 6336, Z: 51.79154},
-	108: {X: 1198, Y: 4278, Z: 95.74805},
-	109: {X: 4274, Y: 1258, Z: 95.74808},
-	110: {X: 6844.213, Y: 1544.085, Z: 49.4502},
-	111: {X: 10420.48, Y: 1048, Z: 51.35432},
-	201: {X: 7940, Y: 13376, Z: 52.8381},
-	202: {X: 4320, Y: 13854, Z: 52.8381},
-	203: {X: 13078, Y: 12584, Z: 91.42981},
-	204: {X: 12564, Y: 13084, Z: 91.42981},
-	205: {X: 11110.61, Y: 11183.44, Z: 91.42981},
-	206: {X: 9012, Y: 10066, Z: 52.3063},
-	207: {X: 8900, Y: 8576, Z: 54.04472},
-	208: {X: 10472.67, Y: 13598.79, Z: 91.79472},
-	209: {X: 13604, Y: 10578, Z: 91.42978},
-	210: {X: 13346, Y: 8340, Z: 52.3063},
-	211: {X: 13854.49, Y: 4426.352, Z: 52.80367},
-}
+// TurretPositions are the approximate positions of the turrets on summoner's
+// rift (can't find an actual source for these). Use the Turrets of a Map for
+// other maps.
+var TurretPositions map[int64]Position = SummonersRift().Turrets
 
 //
 // Monsters
@@ -0,0 +1,256 @@
+// Package fixture cuts baseviews down to small, anonymous fixtures that can
+// be shared and checked in as test data, e.g. to reproduce a bug in one of
+// the analyzers in ingest.
+//
+// Slice, KeepParticipants and Downsample return baseviews that share their
+// events with the original. Renumber and Anonymize change the baseview they
+// are given, so clone it first if the original is still needed.
+
+package fixture
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// downsampleIntervals are the intervals that Minimize tries to downsample
+// state updates to, in order.
+var downsampleIntervals = []float64{1, 2, 5, 10, 30}
+
+// shallow returns a copy of the baseview without its events.
+func shallow(bv *baseview.Baseview) *baseview.Baseview {
+	c := *bv
+	c.Participants = append([]baseview.Participant{}, bv.Participants...)
+	c.Events = []baseview.Event{}
+	return &c
+}
+
+// Slice returns the events from from to to seconds (inclusive). The times of
+// the events are kept, since analyses depend on the time into the game.
+func Slice(bv *baseview.Baseview, from, to float64) *baseview.Baseview {
+	res := shallow(bv)
+	for _, e := range bv.Events {
+		if e.Seconds() >= from && e.Seconds() <= to {
+			res.Events = append(res.Events, e)
+		}
+	}
+	return res
+}
+
+// KeepParticipants returns the participants with the ids, and the events that
+// don't refer to anyone else.
+func KeepParticipants(bv *baseview.Baseview, ids []int64) *baseview.Baseview {
+	keep := map[int64]bool{}
+	for _, id := range ids {
+		keep[id] = true
+	}
+
+	res := shallow(bv)
+	res.Participants = res.Participants[:0]
+	for _, p := range bv.Participants {
+		if keep[p.ParticipantID] {
+			res.Participants = append(res.Participants, p)
+		}
+	}
+	for _, e := range bv.Events {
+		kept := true
+		for _, id := range baseview.ParticipantRefs(e) {
+			kept = kept && keep[*id]
+		}
+		if kept {
+			res.Events = append(res.Events, e)
+		}
+	}
+	return res
+}
+
+// Renumber numbers the participants of each team from the first id of the
+// team (1 and 6) in order, and the wards from 1 in the order they appear,
+// returning the new participant ids by the old.
+func Renumber(bv *baseview.Baseview) map[int64]int64 {
+	sort.Sort(byParticipantID(bv.Participants))
+	participants := map[int64]int64{}
+	next := map[int64]int64{baseview.BlueTeam: 1, baseview.RedTeam: 6}
+	for i, p := range bv.Participants {
+		to := p.ParticipantID
+		if team := baseview.TeamID(p.ParticipantID); team != 0 {
+			to = next[team]
+			next[team]++
+		}
+		participants[p.ParticipantID] = to
+		bv.Participants[i].ParticipantID = to
+	}
+
+	wards := map[int64]int64{}
+	for _, e := range bv.Events {
+		for _, id := range baseview.ParticipantRefs(e) {
+			if to, found := participants[*id]; found {
+				*id = to
+			}
+		}
+		for _, id := range baseview.WardRefs(e) {
+			if _, found := wards[*id]; !found {
+				wards[*id] = int64(len(wards) + 1)
+			}
+			*id = wards[*id]
+		}
+	}
+	return participants
+}
+
+// Anonymize replaces the names and ids of the summoners, returning the new
+// summoner ids by the old. With a salt, they are replaced by hashes, so a
+// summoner is the same in every fixture anonymized with the salt. Without,
+// names are removed and each summoner id becomes the participant id.
+func Anonymize(bv *baseview.Baseview, salt string) map[int64]int64 {
+	ids := map[int64]int64{}
+	for i := range bv.Participants {
+		p := &bv.Participants[i]
+		to := p.ParticipantID
+		if salt != "" {
+			to = int64(hash(salt, fmt.Sprint(p.SummonerID))>>33) + 1
+			if p.SummonerName != "" {
+				p.SummonerName = fmt.Sprintf("summoner-%08x", uint32(hash(salt, p.SummonerName)))
+			}
+		} else {
+			p.SummonerName = ""
+		}
+		ids[p.SummonerID] = to
+		p.SummonerID = to
+	}
+	return ids
+}
+
+func hash(salt, s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(salt + "/" + s))
+	return h.Sum64()
+}
+
+// Downsample returns the baseview with the state updates of each participant
+// at least interval seconds apart, except those where a participant dies or
+// comes back to life.
+func Downsample(bv *baseview.Baseview, interval float64) *baseview.Baseview {
+	res := shallow(bv)
+	last := map[int64]*baseview.StateUpdate{}
+	for _, e := range bv.Events {
+		if t, ok := e.(*baseview.StateUpdate); ok {
+			prev := last[t.ParticipantID]
+			if prev != nil && t.Seconds()-prev.Seconds() < interval && (prev.Health > 0) == (t.Health > 0) {
+				continue
+			}
+			last[t.ParticipantID] = t
+		}
+		res.Events = append(res.Events, e)
+	}
+	return res
+}
+
+// Minimize returns the smallest baseview it can find, within maxTests calls of
+// reproduces, that still reproduces something (e.g. the output of an
+// analyzer). It trims the start and end of the match, downsamples state
+// updates, and then removes other events a chunk at a time.
+func Minimize(bv *baseview.Baseview, reproduces func(*baseview.Baseview) bool, maxTests int) (*baseview.Baseview, error) {
+	if !reproduces(bv) {
+		return nil, fmt.Errorf("the baseview doesn't reproduce it to begin with")
+	}
+	tests := 1
+	test := func(c *baseview.Baseview) bool {
+		if tests >= maxTests {
+			return false
+		}
+		tests++
+		return reproduces(c)
+	}
+	if len(bv.Events) == 0 {
+		return bv, nil
+	}
+
+	// the end, then the start, to the second.
+	from, to := bv.Events[0].Seconds(), bv.Events[len(bv.Events)-1].Seconds()
+	for lo, hi := from, to; hi-lo > 1 && tests < maxTests; {
+		mid := math.Floor((lo + hi) / 2)
+		if test(Slice(bv, from, mid)) {
+			hi, to = mid, mid
+		} else {
+			lo = mid
+		}
+	}
+	for lo, hi := from, to; hi-lo > 1 && tests < maxTests; {
+		mid := math.Ceil((lo + hi) / 2)
+		if test(Slice(bv, mid, to)) {
+			lo, from = mid, mid
+		} else {
+			hi = mid
+		}
+	}
+	bv = Slice(bv, from, to)
+
+	for _, interval := range downsampleIntervals {
+		c := Downsample(bv, interval)
+		if !test(c) {
+			break
+		}
+		bv = c
+	}
+
+	// remove other events in chunks, halving the chunks whenever none of
+	// them can be removed.
+	others := []baseview.Event{}
+	for _, e := range bv.Events {
+		if _, ok := e.(*baseview.StateUpdate); !ok {
+			others = append(others, e)
+		}
+	}
+	removed := map[baseview.Event]bool{}
+	without := func() *baseview.Baseview {
+		res := shallow(bv)
+		for _, e := range bv.Events {
+			if !removed[e] {
+				res.Events = append(res.Events, e)
+			}
+		}
+		return res
+	}
+	for chunks := 2; len(others) > 0 && tests < maxTests; {
+		size := (len(others) + chunks - 1) / chunks
+		reduced := false
+		for start := 0; start < len(others); start += size {
+			end := start + size
+			if end > len(others) {
+				end = len(others)
+			}
+			for _, e := range others[start:end] {
+				removed[e] = true
+			}
+			if test(without()) {
+				others = append(others[:start:start], others[end:]...)
+				reduced = true
+				break
+			}
+			for _, e := range others[start:end] {
+				delete(removed, e)
+			}
+		}
+		if reduced {
+			if chunks > 2 {
+				chunks--
+			}
+		} else if size == 1 {
+			break
+		} else {
+			chunks *= 2
+		}
+	}
+	return without(), nil
+}
+
+type byParticipantID []baseview.Participant
+
+func (a byParticipantID) Len() int           { return len(a) }
+func (a byParticipantID) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byParticipantID) Less(i, j int) bool { return a[i].ParticipantID < a[j].ParticipantID }
@@ -0,0 +1,184 @@
+package fixture
+
+import (
+	"testing"
+
+	"github.com/VantageSports/lolstats/baseview"
+
+	"gopkg.in/tylerb/is.v1"
+)
+
+func event(e baseview.Event, eventType string, seconds float64) baseview.Event {
+	e.SetType(eventType)
+	e.SetSeconds(seconds)
+	return e
+}
+
+// testBaseview returns a one minute match with state updates every half
+// second, and participant 3 dying at 30 seconds.
+func testBaseview() *baseview.Baseview {
+	bv := &baseview.Baseview{Version: baseview.CurrentVersion}
+	for id := int64(1); id <= 10; id++ {
+		bv.Participants = append(bv.Participants, baseview.Participant{
+			ParticipantID: id,
+			SummonerID:    1000 + id,
+			SummonerName:  "someone",
+			ChampionID:    100 + id,
+		})
+	}
+	for second := 0.0; second <= 60; second += 0.5 {
+		for id := int64(1); id <= 10; id++ {
+			health := 500.0
+			if id == 3 && second >= 30 && second < 40 {
+				health = 0
+			}
+			bv.Events = append(bv.Events, event(&baseview.StateUpdate{ParticipantID: id, Health: health, HealthMax: 500}, "state_update", second))
+		}
+		if second == 10 || second == 20 {
+			bv.Events = append(bv.Events, event(&baseview.WardPlaced{ParticipantID: 8, WardID: int64(second)}, "ward_placed", second))
+		}
+		if second == 29.5 {
+			bv.Events = append(bv.Events,
+				event(&baseview.Attack{AttackerID: 8, AttackerType: baseview.ActorHero, TargetID: 20, TargetType: baseview.ActorWard}, "attack", second),
+				event(&baseview.Damage{AttackerID: 7, AttackerType: baseview.ActorHero, VictimID: 3, VictimType: baseview.ActorHero}, "damage", second))
+		}
+		if second == 30 {
+			bv.Events = append(bv.Events, event(&baseview.Death{VictimID: 3}, "death", second))
+		}
+		if second == 50 {
+			bv.Events = append(bv.Events,
+				event(&baseview.EpicMonsterKill{VictimID: baseview.MonsterDragonElemental, KillerID: 7}, "epic_monster_kill", second),
+				// not killed by a champion.
+				event(&baseview.EpicMonsterKill{VictimID: baseview.MonsterBaron}, "epic_monster_kill", second))
+		}
+	}
+	return bv
+}
+
+func count(bv *baseview.Baseview, eventType string) int {
+	n := 0
+	for _, e := range bv.Events {
+		if e.Type() == eventType {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSlice(t *testing.T) {
+	is := is.New(t)
+
+	bv := testBaseview()
+	s := Slice(bv, 10, 20)
+	is.Equal(210, count(s, "state_update"))
+	is.Equal(2, count(s, "ward_placed"))
+	is.Equal(10.0, s.Events[0].Seconds())
+	is.Equal(20.0, s.Events[len(s.Events)-1].Seconds())
+	is.Equal(10, len(s.Participants))
+	is.Equal(1210, count(bv, "state_update"))
+}
+
+func TestKeepParticipants(t *testing.T) {
+	is := is.New(t)
+
+	bv, err := testBaseview().Clone()
+	is.NotErr(err)
+	bv = KeepParticipants(bv, []int64{3, 7, 8})
+	is.Equal(3, len(bv.Participants))
+	is.Equal(3*121, count(bv, "state_update"))
+	is.Equal(1, count(bv, "damage"))
+	is.Equal(2, count(bv, "epic_monster_kill"))
+
+	renumbered := Renumber(bv)
+	is.Equal(int64(1), renumbered[3])
+	is.Equal(int64(6), renumbered[7])
+	is.Equal(int64(7), renumbered[8])
+	for _, e := range bv.Events {
+		switch t := e.(type) {
+		case *baseview.Damage:
+			is.Equal(int64(6), t.AttackerID)
+			is.Equal(int64(1), t.VictimID)
+		case *baseview.Death:
+			is.Equal(int64(1), t.VictimID)
+		case *baseview.WardPlaced:
+			is.Equal(int64(7), t.ParticipantID)
+			is.True(t.WardID == 1 || t.WardID == 2)
+		case *baseview.Attack:
+			// the ward placed at 20 seconds.
+			is.Equal(int64(2), t.TargetID)
+		}
+	}
+
+	// dropping participant 7 drops the damage they did.
+	bv = KeepParticipants(testBaseview(), []int64{3, 8})
+	is.Equal(0, count(bv, "damage"))
+	is.Equal(1, count(bv, "death"))
+	// and their kill, but not the one without a champion killer.
+	is.Equal(1, count(bv, "epic_monster_kill"))
+}
+
+func TestAnonymize(t *testing.T) {
+	is := is.New(t)
+
+	bv := testBaseview()
+	ids := Anonymize(bv, "")
+	is.Equal(int64(3), ids[1003])
+	is.Equal(int64(3), bv.Participants[2].SummonerID)
+	is.Equal("", bv.Participants[2].SummonerName)
+
+	bv, other := testBaseview(), testBaseview()
+	ids = Anonymize(bv, "salt")
+	Anonymize(other, "salt")
+	is.True(ids[1003] > 0)
+	is.True(ids[1003] != ids[1004])
+	is.Equal(ids[1003], bv.Participants[2].SummonerID)
+	is.Equal(bv.Participants, other.Participants)
+	is.NotEqual("someone", bv.Participants[2].SummonerName)
+	is.Equal(bv.Participants[2].SummonerName, bv.Participants[3].SummonerName)
+
+	Anonymize(other, "pepper")
+	is.NotEqual(bv.Participants, other.Participants)
+}
+
+func TestDownsample(t *testing.T) {
+	is := is.New(t)
+
+	bv := Downsample(testBaseview(), 7)
+	// every 7 seconds, and participant 3 dying at 30 and coming back at 40.
+	is.Equal(9*9+10, count(bv, "state_update"))
+	is.Equal(1, count(bv, "death"))
+	is.Equal(2, count(bv, "ward_placed"))
+}
+
+func TestMinimize(t *testing.T) {
+	is := is.New(t)
+
+	// reproduce a death of participant 3 that followed damage.
+	reproduces := func(bv *baseview.Baseview) bool {
+		damaged := false
+		for _, e := range bv.Events {
+			switch t := e.(type) {
+			case *baseview.Damage:
+				damaged = damaged || t.VictimID == 3
+			case *baseview.Death:
+				if t.VictimID == 3 && damaged {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	bv := testBaseview()
+	min, err := Minimize(bv, reproduces, 1000)
+	is.NotErr(err)
+	is.True(reproduces(min))
+	is.Equal(1, count(min, "damage"))
+	is.Equal(1, count(min, "death"))
+	is.Equal(0, count(min, "ward_placed"))
+	is.Equal(0, count(min, "attack"))
+	is.True(len(min.Events) <= 22)
+
+	_, err = Minimize(Slice(bv, 0, 10), reproduces, 1000)
+	is.Err(err)
+}
@@ -0,0 +1,206 @@
+package baseview
+
+import (
+	"sort"
+)
+
+// maxInterpolationGap is the longest gap between two state updates that a
+// position is interpolated across. Heroes ping every half second, so longer
+// gaps are usually recalls, teleports or deaths.
+const maxInterpolationGap = 2.0
+
+// Index answers questions about the state of a match at any moment, without
+// rescanning its events. Build it once per baseview with NewIndex, and share
+// it between analyzers. The baseview must not be modified afterwards.
+//
+// Queries at time t include every event at (or before) t. Events are assumed
+// to be in order of time, as the linter checks.
+type Index struct {
+	events  []Event
+	seconds []float64
+
+	// event indexes of each type.
+	byType map[string][]int
+
+	// state updates, deaths and participant ids, in order.
+	states       map[int64][]*StateUpdate
+	deaths       map[int64][]float64
+	participants []int64
+}
+
+// NewIndex indexes the events of the baseview.
+func NewIndex(bv *Baseview) *Index {
+	x := &Index{
+		events:  bv.Events,
+		seconds: make([]float64, len(bv.Events)),
+		byType:  map[string][]int{},
+		states:  map[int64][]*StateUpdate{},
+		deaths:  map[int64][]float64{},
+	}
+	seen := map[int64]bool{}
+	addParticipant := func(id int64) {
+		if !seen[id] {
+			seen[id] = true
+			x.participants = append(x.participants, id)
+		}
+	}
+	for _, p := range bv.Participants {
+		addParticipant(p.ParticipantID)
+	}
+
+	for i, e := range bv.Events {
+		x.seconds[i] = e.Seconds()
+		x.byType[e.Type()] = append(x.byType[e.Type()], i)
+		switch t := e.(type) {
+		case *StateUpdate:
+			addParticipant(t.ParticipantID)
+			x.states[t.ParticipantID] = append(x.states[t.ParticipantID], t)
+		case *Death:
+			x.deaths[t.VictimID] = append(x.deaths[t.VictimID], t.Seconds())
+		}
+	}
+	sort.Sort(int64s(x.participants))
+	return x
+}
+
+// Participants returns the ids of the participants, in order.
+func (x *Index) Participants() []int64 {
+	return x.participants
+}
+
+// LastState returns the participant's last state update at or before t, or
+// nil if there wasn't one.
+func (x *Index) LastState(participantID int64, t float64) *StateUpdate {
+	states := x.states[participantID]
+	i := sort.Search(len(states), func(i int) bool { return states[i].Seconds() > t })
+	if i == 0 {
+		return nil
+	}
+	return states[i-1]
+}
+
+// StateAt returns the participant's last known state at t, with their position
+// interpolated between the state updates either side of t (unless they were
+// too far apart, or the participant died in between). ok is false if there
+// were no state updates at or before t.
+func (x *Index) StateAt(participantID int64, t float64) (state StateUpdate, ok bool) {
+	states := x.states[participantID]
+	i := sort.Search(len(states), func(i int) bool { return states[i].Seconds() > t })
+	if i == 0 {
+		return state, false
+	}
+	prev := states[i-1]
+	state = *prev
+	if i == len(states) {
+		return state, true
+	}
+	next := states[i]
+	gap := next.Seconds() - prev.Seconds()
+	if gap <= 0 || gap > maxInterpolationGap || x.diedBetween(participantID, prev.Seconds(), next.Seconds()) {
+		return state, true
+	}
+	f := (t - prev.Seconds()) / gap
+	state.Position = Position{
+		X: prev.Position.X + f*(next.Position.X-prev.Position.X),
+		Y: prev.Position.Y + f*(next.Position.Y-prev.Position.Y),
+		Z: prev.Position.Z + f*(next.Position.Z-prev.Position.Z),
+	}
+	return state, true
+}
+
+// AliveAt returns true unless the participant's last death at or before t
+// hasn't been followed by a state update with health (i.e. a respawn).
+func (x *Index) AliveAt(participantID int64, t float64) bool {
+	deaths := x.deaths[participantID]
+	i := sort.Search(len(deaths), func(i int) bool { return deaths[i] > t })
+	if i == 0 {
+		return true
+	}
+	death := deaths[i-1]
+	states := x.states[participantID]
+	for j := sort.Search(len(states), func(j int) bool { return states[j].Seconds() > death }); j < len(states) && states[j].Seconds() <= t; j++ {
+		if states[j].Health > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (x *Index) diedBetween(participantID int64, from, to float64) bool {
+	deaths := x.deaths[participantID]
+	i := sort.SearchFloat64s(deaths, from)
+	return i < len(deaths) && deaths[i] <= to
+}
+
+// EventsBetween returns the events from t1 to t2 (inclusive) of the types, or
+// of any type if none are given, in order.
+func (x *Index) EventsBetween(t1, t2 float64, types ...string) []Event {
+	events := []Event{}
+	if len(types) == 0 {
+		from := sort.SearchFloat64s(x.seconds, t1)
+		for i := from; i < len(x.events) && x.seconds[i] <= t2; i++ {
+			events = append(events, x.events[i])
+		}
+		return events
+	}
+
+	indexes := []int{}
+	for _, eventType := range types {
+		byType := x.byType[eventType]
+		from := sort.Search(len(byType), func(i int) bool { return x.seconds[byType[i]] >= t1 })
+		for i := from; i < len(byType) && x.seconds[byType[i]] <= t2; i++ {
+			indexes = append(indexes, byType[i])
+		}
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		events = append(events, x.events[i])
+	}
+	return events
+}
+
+// Neighbor is a participant, and their distance from a position.
+type Neighbor struct {
+	ParticipantID int64
+	Position      Position
+	Distance      float64
+}
+
+// NearestParticipants returns the live participants at t, ordered by their
+// distance (on the XY plane) from the position.
+func (x *Index) NearestParticipants(p Position, t float64) []Neighbor {
+	neighbors := []Neighbor{}
+	for _, id := range x.participants {
+		state, ok := x.StateAt(id, t)
+		if !ok || !x.AliveAt(id, t) {
+			continue
+		}
+		neighbors = append(neighbors, Neighbor{
+			ParticipantID: id,
+			Position:      state.Position,
+			Distance:      p.DistanceXY(state.Position),
+		})
+	}
+	sort.Stable(byDistance(neighbors))
+	return neighbors
+}
+
+// ParticipantsWithin returns the live participants within the distance of the
+// position at t, nearest first.
+func (x *Index) ParticipantsWithin(p Position, t, distance float64) []Neighbor {
+	neighbors := x.NearestParticipants(p, t)
+	n := sort.Search(len(neighbors), func(i int) bool { return neighbors[i].Distance > distance })
+	return neighbors[:n]
+}
+
+type byDistance []Neighbor
+
+func (a byDistance) Len() int           { return len(a) }
+func (a byDistance) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byDistance) Less(i, j int) bool { return a[i].Distance < a[j].Distance }
+
+type int64s []int64
+
+func (a int64s) Len() int           { return len(a) }
+func (a int64s) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a int64s) Less(i, j int) bool { return a[i] < a[j] }
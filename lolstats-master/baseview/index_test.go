@@ -0,0 +1,76 @@
+package baseview
+
+import (
+	"testing"
+
+	"gopkg.in/tylerb/is.v1"
+)
+
+func TestIndex(t *testing.T) {
+	is := is.New(t)
+
+	state := func(id int64, seconds, x, health float64) Event {
+		return event(&StateUpdate{ParticipantID: id, Position: Position{X: x}, Health: health, Gold: int64(seconds)}, "state_update", seconds)
+	}
+	bv := &Baseview{
+		Participants: []Participant{{ParticipantID: 2}, {ParticipantID: 1}},
+		Events: []Event{
+			state(1, 0, 0, 100),
+			state(2, 0, 1000, 100),
+			state(1, 1, 100, 100),
+			state(2, 1, 1000, 100),
+			event(&Damage{VictimID: 1, VictimType: ActorHero}, "damage", 1.5),
+			state(1, 2, 200, 100),
+			event(&Death{VictimID: 2}, "death", 2),
+			state(2, 2, 1000, 0),
+			state(1, 5, 500, 100),
+			state(2, 5, 0, 0),
+			state(2, 6, 0, 100),
+		},
+	}
+	x := NewIndex(bv)
+	is.Equal([]int64{1, 2}, x.Participants())
+
+	// positions are interpolated between updates.
+	s, ok := x.StateAt(1, 0.5)
+	is.True(ok)
+	is.Equal(50.0, s.Position.X)
+	is.Equal(int64(0), s.Gold)
+	is.Equal(0.0, s.Seconds())
+	is.Equal(int64(1), x.LastState(1, 1.9).Gold)
+
+	// but not across long gaps, or deaths.
+	s, _ = x.StateAt(1, 3)
+	is.Equal(200.0, s.Position.X)
+	s, _ = x.StateAt(2, 5.5)
+	is.Equal(0.0, s.Position.X)
+	s, _ = x.StateAt(2, 1.5)
+	is.Equal(1000.0, s.Position.X)
+
+	// nor before the first update, or after the last.
+	_, ok = x.StateAt(1, -1)
+	is.False(ok)
+	is.Nil(x.LastState(1, -1))
+	s, _ = x.StateAt(1, 100)
+	is.Equal(500.0, s.Position.X)
+
+	is.True(x.AliveAt(2, 1.9))
+	is.False(x.AliveAt(2, 2))
+	is.False(x.AliveAt(2, 5.9))
+	is.True(x.AliveAt(2, 6))
+	is.True(x.AliveAt(1, 6))
+
+	is.Equal(6, len(x.EventsBetween(1, 2)))
+	is.Equal(2, len(x.EventsBetween(1, 2, "death", "damage")))
+	is.Equal("damage", x.EventsBetween(0, 10, "death", "damage")[0].Type())
+	is.Equal(0, len(x.EventsBetween(3, 4)))
+	is.Equal(0, len(x.EventsBetween(0, 10, "ward_placed")))
+
+	// participant 2 is dead, so not near anyone.
+	neighbors := x.NearestParticipants(Position{X: 900}, 1)
+	is.Equal(2, len(neighbors))
+	is.Equal(int64(2), neighbors[0].ParticipantID)
+	is.Equal(100.0, neighbors[0].Distance)
+	is.Equal(1, len(x.NearestParticipants(Position{X: 900}, 3)))
+	is.Equal(1, len(x.ParticipantsWithin(Position{X: 900}, 1, 500)))
+}
@@ -0,0 +1,364 @@
+// Package lint checks baseviews for the invariants that the analyzers in
+// ingest assume, but that nothing else enforces: e.g. that there are ten
+// participants, that events are in order, and that participants have regular
+// state updates. Bad baseviews otherwise only surface as odd stats.
+
+package lint
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// Names of the checks, as reported in violations.
+const (
+	CheckParticipants = "participants"
+	CheckEventTypes   = "event_types"
+	CheckTimestamps   = "timestamps"
+	CheckReferences   = "references"
+	CheckValues       = "values"
+	CheckDeaths       = "deaths"
+	CheckStateUpdates = "state_updates"
+)
+
+// Severity is how badly a violation affects the stats computed from a
+// baseview. Errors make stats wrong, warnings make them suspect.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+const (
+	// maxParticipants is the most participants a match has, five a side.
+	maxParticipants = 10
+
+	// deathDamageSeconds is how long before a death the victim must have
+	// taken damage. Matches the window ingest attributes deaths within.
+	deathDamageSeconds = 13.0
+
+	// maxStateGap is the longest a live participant may go without a state
+	// update. Elo logs ping every half second.
+	maxStateGap = 5.0
+
+	// healthSlack allows for rounding of health and mana values.
+	healthSlack = 1.0
+
+	// maxPerCheck is the most violations reported for each check. The rest
+	// are only counted.
+	maxPerCheck = 25
+)
+
+// Violation is a single broken invariant.
+type Violation struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+
+	// EventIndex is the index of the offending event in the baseview's
+	// events, or -1 if the violation isn't about a single event.
+	EventIndex    int   `json:"event_index"`
+	ParticipantID int64 `json:"participant_id,omitempty"`
+}
+
+func (v Violation) String() string {
+	where := ""
+	if v.EventIndex >= 0 {
+		where = fmt.Sprintf(" (event %d)", v.EventIndex)
+	}
+	return fmt.Sprintf("%s: %s: %s%s", v.Severity, v.Check, v.Message, where)
+}
+
+// Report is the result of linting a baseview. Counts include the violations
+// that weren't reported because a check had too many.
+type Report struct {
+	Violations []Violation    `json:"violations"`
+	Counts     map[string]int `json:"counts"`
+	Errors     int            `json:"errors"`
+	Warnings   int            `json:"warnings"`
+}
+
+// Err returns an error describing the first error found, or nil if there
+// were only warnings (or no violations at all).
+func (r *Report) Err() error {
+	if r.Errors == 0 {
+		return nil
+	}
+	for _, v := range r.Violations {
+		if v.Severity == SeverityError {
+			return fmt.Errorf("baseview has %d lint errors, e.g. %v", r.Errors, v)
+		}
+	}
+	return fmt.Errorf("baseview has %d lint errors", r.Errors)
+}
+
+func (r *Report) add(v Violation) {
+	r.Counts[v.Check]++
+	if v.Severity == SeverityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+	if r.Counts[v.Check] <= maxPerCheck {
+		r.Violations = append(r.Violations, v)
+	}
+}
+
+func (r *Report) event(check string, severity Severity, index int, participantID int64, format string, args ...interface{}) {
+	r.add(Violation{
+		Check:         check,
+		Severity:      severity,
+		Message:       fmt.Sprintf(format, args...),
+		EventIndex:    index,
+		ParticipantID: participantID,
+	})
+}
+
+func (r *Report) baseview(check string, severity Severity, participantID int64, format string, args ...interface{}) {
+	r.event(check, severity, -1, participantID, format, args...)
+}
+
+// linter holds the state of the checks that span events.
+type linter struct {
+	r            *Report
+	participants map[int64]bool
+	// numParticipants is the number of participants of the match, whose ids
+	// are 1 to numParticipants.
+	numParticipants int64
+	// views are the participants expected to have state updates, or nil for
+	// all of them.
+	views map[int64]bool
+
+	lastSeconds float64
+	// the time of each participant's last state update and death, and of the
+	// last damage they took.
+	lastState  map[int64]float64
+	lastDeath  map[int64]float64
+	lastDamage map[int64]float64
+}
+
+// Lint checks the baseview, returning all of the violations found.
+func Lint(bv *baseview.Baseview) *Report {
+	return lint(bv, nil)
+}
+
+// LintViews is Lint for baseviews that only follow some participants, e.g.
+// one OCR'd from a participant's video: the others aren't expected to have
+// state updates.
+func LintViews(bv *baseview.Baseview, participantIDs []int64) *Report {
+	views := map[int64]bool{}
+	for _, id := range participantIDs {
+		views[id] = true
+	}
+	return lint(bv, views)
+}
+
+func lint(bv *baseview.Baseview, views map[int64]bool) *Report {
+	l := &linter{
+		r:            &Report{Violations: []Violation{}, Counts: map[string]int{}},
+		participants: map[int64]bool{},
+		views:        views,
+		lastSeconds:  math.Inf(-1),
+		lastState:    map[int64]float64{},
+		lastDeath:    map[int64]float64{},
+		lastDamage:   map[int64]float64{},
+	}
+	l.checkParticipants(bv.Participants)
+	for i, e := range bv.Events {
+		l.checkEvent(i, e)
+	}
+	l.checkStateUpdates(baseview.MatchDuration(bv.Events))
+	return l.r
+}
+
+func (l *linter) checkParticipants(participants []baseview.Participant) {
+	// teams are the same size, e.g. three a side on the twisted treeline.
+	l.numParticipants = int64(len(participants))
+	if l.numParticipants == 0 || l.numParticipants%2 != 0 || l.numParticipants > maxParticipants {
+		l.r.baseview(CheckParticipants, SeverityError, 0, "%d participants, not an even number up to %d", l.numParticipants, maxParticipants)
+	}
+	for _, p := range participants {
+		id := p.ParticipantID
+		switch {
+		case id < 1 || id > l.numParticipants:
+			l.r.baseview(CheckParticipants, SeverityError, id, "participant id %d not between 1 and %d", id, l.numParticipants)
+		case l.participants[id]:
+			l.r.baseview(CheckParticipants, SeverityError, id, "participant id %d is duplicated", id)
+		case p.ChampionID == 0:
+			l.r.baseview(CheckParticipants, SeverityWarning, id, "participant %d has no champion", id)
+		}
+		l.participants[id] = true
+	}
+}
+
+func (l *linter) checkEvent(i int, e baseview.Event) {
+	seconds := e.Seconds()
+	switch {
+	case math.IsNaN(seconds) || math.IsInf(seconds, 0):
+		l.r.event(CheckTimestamps, SeverityError, i, 0, "invalid time %v", seconds)
+		return
+	case seconds < l.lastSeconds:
+		l.r.event(CheckTimestamps, SeverityError, i, 0, "time %.2f is before the previous event (%.2f)", seconds, l.lastSeconds)
+	case seconds < 0:
+		l.r.event(CheckTimestamps, SeverityWarning, i, 0, "negative time %.2f", seconds)
+	}
+	l.lastSeconds = math.Max(l.lastSeconds, seconds)
+
+	if expected := eventType(e); expected == "" {
+		l.r.event(CheckEventTypes, SeverityError, i, 0, "unknown event %T", e)
+	} else if e.Type() != expected {
+		l.r.event(CheckEventTypes, SeverityError, i, 0, "%T has type %q, not %q", e, e.Type(), expected)
+	}
+
+	switch t := e.(type) {
+	case *baseview.Attack:
+		l.hero(i, t.AttackerID, t.AttackerType, "attacker")
+		l.hero(i, t.TargetID, t.TargetType, "target")
+	case *baseview.Damage:
+		l.hero(i, t.AttackerID, t.AttackerType, "attacker")
+		l.hero(i, t.VictimID, t.VictimType, "victim")
+		l.finite(i, t.VictimID, "damage", t.Total, t.Percent)
+		if t.Total < 0 {
+			l.r.event(CheckValues, SeverityWarning, i, t.VictimID, "negative damage %.2f", t.Total)
+		}
+		if t.VictimType == baseview.ActorHero {
+			l.lastDamage[t.VictimID] = seconds
+		}
+	case *baseview.Death:
+		l.participant(i, t.VictimID, "victim")
+		if last, ok := l.lastDamage[t.VictimID]; !ok || seconds-last > deathDamageSeconds {
+			l.r.event(CheckDeaths, SeverityWarning, i, t.VictimID, "participant %d died without taking damage in the previous %.0f seconds", t.VictimID, deathDamageSeconds)
+		}
+		l.lastDeath[t.VictimID] = seconds
+	case *baseview.ItemUsed:
+		l.participant(i, t.ParticipantID, "item user")
+	case *baseview.LevelUp:
+		l.participant(i, t.ParticipantID, "participant")
+		if t.Level < 1 || t.Level > 18 {
+			l.r.event(CheckValues, SeverityWarning, i, t.ParticipantID, "level %d", t.Level)
+		}
+	case *baseview.SlotUpgrade:
+		l.participant(i, t.ParticipantID, "participant")
+	case *baseview.Spawn:
+		l.participant(i, t.ParticipantID, "participant")
+	case *baseview.StateUpdate:
+		l.checkStateUpdate(i, t)
+	case *baseview.WardPlaced:
+		l.participant(i, t.ParticipantID, "ward owner")
+		if t.TeamID != baseview.BlueTeam && t.TeamID != baseview.RedTeam {
+			l.r.event(CheckValues, SeverityError, i, t.ParticipantID, "ward placed by team %d", t.TeamID)
+		}
+	}
+}
+
+func (l *linter) checkStateUpdate(i int, t *baseview.StateUpdate) {
+	id := t.ParticipantID
+	if !l.participant(i, id, "participant") {
+		return
+	}
+	l.finite(i, id, "state", t.Health, t.HealthMax, t.Mana, t.ManaMax, t.Position.X, t.Position.Y, t.Position.Z)
+	if t.Health > t.HealthMax+healthSlack {
+		l.r.event(CheckValues, SeverityWarning, i, id, "health %.1f is more than max health %.1f", t.Health, t.HealthMax)
+	}
+	if t.Mana > t.ManaMax+healthSlack {
+		l.r.event(CheckValues, SeverityWarning, i, id, "mana %.1f is more than max mana %.1f", t.Mana, t.ManaMax)
+	}
+	if t.Gold < 0 || t.MinionsKilled < 0 || t.NeutralMinionsKilled < 0 {
+		l.r.event(CheckValues, SeverityError, i, id, "negative gold or minions killed")
+	}
+	if t.UnderOwnTurret && t.UnderEnemyTurret {
+		l.r.event(CheckValues, SeverityWarning, i, id, "under both own and enemy turrets")
+	}
+
+	seconds := t.Seconds()
+	if last, ok := l.lastState[id]; ok && seconds-last > maxStateGap && !l.diedBetween(id, last, seconds) {
+		l.r.event(CheckStateUpdates, SeverityWarning, i, id, "no state update for participant %d for %.1f seconds", id, seconds-last)
+	}
+	l.lastState[id] = seconds
+}
+
+// checkStateUpdates checks that every participant had state updates, until
+// (nearly) the end of the match.
+func (l *linter) checkStateUpdates(duration float64) {
+	for id := int64(1); id <= l.numParticipants; id++ {
+		if !l.participants[id] || (l.views != nil && !l.views[id]) {
+			continue
+		}
+		last, ok := l.lastState[id]
+		switch {
+		case !ok:
+			l.r.baseview(CheckStateUpdates, SeverityError, id, "no state updates for participant %d", id)
+		case duration-last > maxStateGap && !l.diedBetween(id, last, duration):
+			l.r.baseview(CheckStateUpdates, SeverityWarning, id, "last state update for participant %d is %.1f seconds before the end", id, duration-last)
+		}
+	}
+}
+
+// diedBetween returns true if the participant's last death was between the
+// times. Dead participants don't have state updates.
+func (l *linter) diedBetween(participantID int64, from, to float64) bool {
+	death, ok := l.lastDeath[participantID]
+	return ok && from <= death && death <= to
+}
+
+// participant checks that the id is a participant's.
+func (l *linter) participant(i int, id int64, role string) bool {
+	if l.participants[id] {
+		return true
+	}
+	l.r.event(CheckReferences, SeverityError, i, 0, "%s %d is not a participant", role, id)
+	return false
+}
+
+// hero checks that the id is a participant's, if it refers to a hero.
+func (l *linter) hero(i int, id int64, actorType baseview.ActorType, role string) {
+	if actorType == baseview.ActorHero {
+		l.participant(i, id, role)
+	}
+}
+
+func (l *linter) finite(i int, participantID int64, what string, values ...float64) {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			l.r.event(CheckValues, SeverityError, i, participantID, "invalid %s value %v", what, v)
+			return
+		}
+	}
+}
+
+// eventType returns the type that each kind of event should have, or "" if
+// the kind of event is unknown.
+func eventType(e baseview.Event) string {
+	switch e.(type) {
+	case *baseview.Attack:
+		return "attack"
+	case *baseview.BuildingKill:
+		return "building_kill"
+	case *baseview.Damage:
+		return "damage"
+	case *baseview.Death:
+		return "death"
+	case *baseview.EpicMonsterKill:
+		return "epic_monster_kill"
+	case *baseview.GameEnd:
+		return "game_end"
+	case *baseview.ItemUsed:
+		return "item_used"
+	case *baseview.LevelUp:
+		return "level_up"
+	case *baseview.SlotUpgrade:
+		return "slot_upgrade"
+	case *baseview.StateUpdate:
+		return "state_update"
+	case *baseview.Spawn:
+		return "spawn"
+	case *baseview.WardPlaced:
+		return "ward_placed"
+	case *baseview.WardDeath:
+		return "ward_death"
+	}
+	return ""
+}
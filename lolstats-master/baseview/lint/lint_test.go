@@ -0,0 +1,131 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/VantageSports/lolstats/baseview"
+
+	"gopkg.in/tylerb/is.v1"
+)
+
+func event(e baseview.Event, eventType string, seconds float64) baseview.Event {
+	e.SetType(eventType)
+	e.SetSeconds(seconds)
+	return e
+}
+
+// validBaseview returns a one minute match without violations.
+func validBaseview() *baseview.Baseview {
+	bv := &baseview.Baseview{Version: baseview.CurrentVersion}
+	for id := int64(1); id <= 10; id++ {
+		bv.Participants = append(bv.Participants, baseview.Participant{ParticipantID: id, ChampionID: 100 + id})
+	}
+	for second := 0; second <= 60; second++ {
+		for id := int64(1); id <= 10; id++ {
+			bv.Events = append(bv.Events, event(&baseview.StateUpdate{ParticipantID: id, Health: 500, HealthMax: 500}, "state_update", float64(second)))
+		}
+	}
+	return bv
+}
+
+func TestLintValid(t *testing.T) {
+	is := is.New(t)
+
+	r := Lint(validBaseview())
+	is.Equal(0, len(r.Violations))
+	is.NotErr(r.Err())
+}
+
+func TestLint(t *testing.T) {
+	is := is.New(t)
+
+	bv := validBaseview()
+	bv.Participants[9].ParticipantID = 11
+	bv.Events = append(bv.Events[:100],
+		event(&baseview.Damage{AttackerID: 2, AttackerType: baseview.ActorHero, VictimID: 1, VictimType: baseview.ActorHero, Total: 100}, "damage", 9.5),
+		event(&baseview.Death{VictimID: 1}, "death", 9.6),
+		event(&baseview.Death{VictimID: 2}, "death", 9.7),
+		event(&baseview.LevelUp{ParticipantID: 3, Level: 2}, "level_down", 9.7),
+		event(&baseview.WardPlaced{ParticipantID: 3, TeamID: 300}, "ward_placed", 9.8),
+		event(&baseview.Attack{AttackerID: 12, AttackerType: baseview.ActorHero, TargetType: baseview.ActorMinion}, "attack", 9.0),
+		event(&baseview.BuildingKill{BuildingType: baseview.ActorTurret}, "building_kill", 60),
+	)
+	r := Lint(bv)
+
+	byCheck := map[string][]Violation{}
+	for _, v := range r.Violations {
+		byCheck[v.Check] = append(byCheck[v.Check], v)
+	}
+	is.Equal(1, len(byCheck[CheckParticipants]))
+	is.Equal(1, len(byCheck[CheckEventTypes]))
+	is.Equal(103, byCheck[CheckEventTypes][0].EventIndex)
+	is.Equal(1, len(byCheck[CheckTimestamps]))
+	is.Equal(105, byCheck[CheckTimestamps][0].EventIndex)
+	// the state updates of participant 10, and the attack.
+	is.Equal(11, len(byCheck[CheckReferences]))
+	is.Equal(1, len(byCheck[CheckValues]))
+
+	// only participant 2 died without taking damage.
+	is.Equal(1, len(byCheck[CheckDeaths]))
+	is.Equal(int64(2), byCheck[CheckDeaths][0].ParticipantID)
+
+	// the updates stop at 9 seconds, but 1 and 2 died, and 10 isn't a
+	// participant at all.
+	is.Equal(7, len(byCheck[CheckStateUpdates]))
+	is.Err(r.Err())
+}
+
+func TestLintLimit(t *testing.T) {
+	is := is.New(t)
+
+	bv := validBaseview()
+	for i := 0; i < 100; i++ {
+		bv.Events = append(bv.Events, event(&baseview.LevelUp{ParticipantID: 20, Level: 2}, "level_up", 60))
+	}
+	r := Lint(bv)
+	is.Equal(maxPerCheck, len(r.Violations))
+	is.Equal(100, r.Counts[CheckReferences])
+	is.Equal(100, r.Errors)
+}
+
+func TestLintViews(t *testing.T) {
+	is := is.New(t)
+
+	// only participant 7's state updates were recorded.
+	bv := validBaseview()
+	events := bv.Events[:0]
+	for _, e := range bv.Events {
+		if e.(*baseview.StateUpdate).ParticipantID == 7 {
+			events = append(events, e)
+		}
+	}
+	bv.Events = events
+
+	is.Equal(9, Lint(bv).Counts[CheckStateUpdates])
+	r := LintViews(bv, []int64{7})
+	is.Equal(0, len(r.Violations))
+	is.NotErr(r.Err())
+	is.Equal(1, LintViews(bv, []int64{6, 7}).Errors)
+}
+
+func TestLintTeamSize(t *testing.T) {
+	is := is.New(t)
+
+	// three a side, as on the twisted treeline.
+	bv := validBaseview()
+	bv.Participants = bv.Participants[:6]
+	events := bv.Events[:0]
+	for _, e := range bv.Events {
+		if e.(*baseview.StateUpdate).ParticipantID <= 6 {
+			events = append(events, e)
+		}
+	}
+	bv.Events = events
+	r := Lint(bv)
+	is.Equal(0, len(r.Violations))
+	is.NotErr(r.Err())
+
+	// and an uneven one.
+	bv.Participants = bv.Participants[:5]
+	is.Equal(1, Lint(bv).Counts[CheckParticipants])
+}
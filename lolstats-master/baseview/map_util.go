@@ -49,6 +49,12 @@ func AreaToRegion(id int64) MapRegion {
 	return RegionOther
 }
 
+// Map ids, as riot's match details report them.
+const (
+	MapSummonersRift int64 = 11
+	MapHowlingAbyss  int64 = 12
+)
+
 const (
 	// Summoners rift constants
 	// Derived from https://developer.riotgames.com/docs/game-constants
@@ -60,8 +66,10 @@ const (
 	SRYMax float64 = 14980
 )
 
-// GetArea returns the id associated with the provided position, or -1 if it
-// can't be discerned.
+// AreaID returns the id of the area of summoner's rift that the position is
+// in, by coarse heuristics, or 0 if it can't be discerned. Existing stats
+// (time management, role positions) are calibrated on these areas, so they
+// are kept as they were: SummonersRift().AreaAt is the accurate lookup.
 func AreaID(p Position) int64 {
 	transposed := !isBlue(p)
 	if transposed {
@@ -0,0 +1,554 @@
+// Package merge combines baseviews of the same match from different sources.
+// Each source has gaps that another may cover: ocr misses fights that are off
+// screen, and elo logs can be truncated. The merged baseview tags each event
+// with its source, and is otherwise like any other, so the analyzers in
+// ingest consume it unchanged.
+
+package merge
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// Kinds of disagreement between sources.
+const (
+	DisagreeMap          = "map"
+	DisagreeParticipants = "participants"
+	DisagreePositions    = "positions"
+	DisagreeStates       = "states"
+	DisagreeWards        = "wards"
+)
+
+const (
+	// positionSlack is how far apart two sources may place the same event
+	// (or participant) before they disagree.
+	positionSlack = 500.0
+
+	// maxAlignOffset is the largest difference between the clocks of two
+	// sources that alignment looks for.
+	maxAlignOffset = 120.0
+
+	// maxStateGap is the longest gap in the state updates of a participant
+	// that isn't filled from the other source. Elo logs ping every half
+	// second.
+	maxStateGap = 2.0
+
+	// maxPerKind is the most disagreements reported of each kind. The rest
+	// are only counted.
+	maxPerKind = 25
+)
+
+// Options configure a merge.
+type Options struct {
+	// Priority lists sources (e.g. baseview.SourceElo), most trusted first.
+	// Where both baseviews have an event, the more trusted one is kept.
+	// Unlisted sources are trusted least.
+	Priority []string
+	// Tolerance is how many seconds apart the same event may be in the two
+	// sources, once aligned.
+	Tolerance float64
+	// Align estimates the offset between the clocks of the sources from
+	// events that happen once, like level ups. Otherwise Offset is added to
+	// the times of the less trusted source.
+	Align  bool
+	Offset float64
+}
+
+// DefaultOptions trusts manual tagging over elo logs, and elo logs over ocr.
+func DefaultOptions() Options {
+	return Options{
+		Priority:  []string{baseview.SourceManual, baseview.SourceElo, baseview.SourceOCR},
+		Tolerance: 1,
+		Align:     true,
+	}
+}
+
+// Disagreement is something the two sources don't agree on.
+type Disagreement struct {
+	Kind          string  `json:"kind"`
+	Seconds       float64 `json:"seconds"`
+	EventType     string  `json:"event_type,omitempty"`
+	ParticipantID int64   `json:"participant_id,omitempty"`
+	Message       string  `json:"message"`
+}
+
+func (d Disagreement) String() string {
+	return fmt.Sprintf("%.1fs %s: %s", d.Seconds, d.Kind, d.Message)
+}
+
+// Report describes how two baseviews were merged.
+type Report struct {
+	// Primary is the source of the more trusted baseview, and Secondary the
+	// other.
+	Primary   string `json:"primary"`
+	Secondary string `json:"secondary"`
+	// Offset is the number of seconds added to the times of the secondary.
+	Offset float64 `json:"offset"`
+	// Participants maps the participant ids of the secondary to the primary.
+	Participants map[int64]int64 `json:"participants"`
+
+	// Matched counts the events (by type) that both sources have, and Added
+	// and Missing the events only the secondary and primary have.
+	Matched map[string]int `json:"matched"`
+	Added   map[string]int `json:"added"`
+	Missing map[string]int `json:"missing"`
+
+	Disagreements []Disagreement `json:"disagreements"`
+	// Counts are the number of disagreements of each kind, including those
+	// not reported.
+	Counts map[string]int `json:"counts"`
+}
+
+func (r *Report) disagree(d Disagreement) {
+	r.Counts[d.Kind]++
+	if r.Counts[d.Kind] <= maxPerKind {
+		r.Disagreements = append(r.Disagreements, d)
+	}
+}
+
+// Merge combines two baseviews of the same match, whose producers must say
+// what their sources are. Neither baseview is modified.
+func Merge(a, b *baseview.Baseview, opts Options) (*baseview.Baseview, *Report, error) {
+	for _, bv := range []*baseview.Baseview{a, b} {
+		if bv.Producer == nil || bv.Producer.Source == "" {
+			return nil, nil, fmt.Errorf("baseview has no source")
+		}
+	}
+	if rank(b.Producer.Source, opts.Priority) < rank(a.Producer.Source, opts.Priority) {
+		a, b = b, a
+	}
+	primary, err := a.Clone()
+	if err != nil {
+		return nil, nil, err
+	}
+	secondary, err := b.Clone()
+	if err != nil {
+		return nil, nil, err
+	}
+	tag(primary)
+	tag(secondary)
+
+	r := &Report{
+		Primary:   a.Producer.Source,
+		Secondary: b.Producer.Source,
+		Matched:   map[string]int{},
+		Added:     map[string]int{},
+		Missing:   map[string]int{},
+		Counts:    map[string]int{},
+	}
+	merged := &baseview.Baseview{
+		Version:      baseview.CurrentVersion,
+		Producer:     &baseview.Producer{Source: baseview.SourceMerged, GamePatch: a.Producer.GamePatch},
+		MapID:        primary.MapID,
+		LastUpdated:  primary.LastUpdated,
+		Participants: primary.Participants,
+	}
+	if merged.Producer.GamePatch == "" {
+		merged.Producer.GamePatch = b.Producer.GamePatch
+	}
+	if secondary.LastUpdated.After(merged.LastUpdated) {
+		merged.LastUpdated = secondary.LastUpdated
+	}
+	if merged.MapID != secondary.MapID {
+		r.disagree(Disagreement{Kind: DisagreeMap, Message: fmt.Sprintf("%s says map %d, %s says %d", r.Primary, primary.MapID, r.Secondary, secondary.MapID)})
+	}
+
+	if r.Participants, err = mapParticipants(primary, secondary, r); err != nil {
+		return nil, nil, err
+	}
+	for _, e := range secondary.Events {
+		remapParticipants(e, r.Participants)
+	}
+
+	r.Offset = opts.Offset
+	if opts.Align {
+		r.Offset = align(primary.Events, secondary.Events)
+	}
+	for _, e := range secondary.Events {
+		e.SetSeconds(e.Seconds() + r.Offset)
+	}
+
+	merged.Events = reconcile(primary.Events, secondary.Events, opts.Tolerance, r)
+	return merged, r, nil
+}
+
+// rank returns the position of the source in the priority list.
+func rank(source string, priority []string) int {
+	for i, s := range priority {
+		if s == source {
+			return i
+		}
+	}
+	return len(priority)
+}
+
+// tag sets the source of the events that don't already have one (i.e. that
+// weren't merged before).
+func tag(bv *baseview.Baseview) {
+	for _, e := range bv.Events {
+		if e.Source() == "" {
+			e.SetSource(bv.Producer.Source)
+		}
+	}
+}
+
+// mapParticipants maps each participant of the secondary to one of the
+// primary by their summoner, else their champion, else their id.
+func mapParticipants(primary, secondary *baseview.Baseview, r *Report) (map[int64]int64, error) {
+	mapping := map[int64]int64{}
+	mapped := map[int64]bool{}
+	byID := map[int64]*baseview.Participant{}
+	for i := range primary.Participants {
+		byID[primary.Participants[i].ParticipantID] = &primary.Participants[i]
+	}
+
+	for _, s := range secondary.Participants {
+		var match *baseview.Participant
+		for i := range primary.Participants {
+			p := &primary.Participants[i]
+			if s.SummonerID != 0 && p.SummonerID == s.SummonerID {
+				match = p
+				break
+			}
+			if match == nil && s.ChampionID != 0 && p.ChampionID == s.ChampionID {
+				match = p
+			}
+		}
+		if match == nil {
+			match = byID[s.ParticipantID]
+		}
+		if match == nil {
+			return nil, fmt.Errorf("participant %d of %s has no match in %s", s.ParticipantID, r.Secondary, r.Primary)
+		}
+		if mapped[match.ParticipantID] {
+			return nil, fmt.Errorf("participant %d of %s matches more than one in %s", match.ParticipantID, r.Primary, r.Secondary)
+		}
+		mapped[match.ParticipantID] = true
+		mapping[s.ParticipantID] = match.ParticipantID
+
+		if baseview.TeamID(match.ParticipantID) != baseview.TeamID(s.ParticipantID) {
+			r.disagree(Disagreement{
+				Kind:          DisagreeParticipants,
+				ParticipantID: match.ParticipantID,
+				Message:       fmt.Sprintf("%s has participant %d on the other team (as %d)", r.Secondary, match.ParticipantID, s.ParticipantID),
+			})
+		}
+		fillParticipant(match, s)
+	}
+
+	if len(secondary.Participants) != len(primary.Participants) {
+		r.disagree(Disagreement{
+			Kind:    DisagreeParticipants,
+			Message: fmt.Sprintf("%s has %d participants, %s has %d", r.Primary, len(primary.Participants), r.Secondary, len(secondary.Participants)),
+		})
+	}
+	return mapping, nil
+}
+
+// fillParticipant fills in what the primary doesn't know about a participant
+// from the secondary.
+func fillParticipant(p *baseview.Participant, s baseview.Participant) {
+	if p.SummonerID == 0 {
+		p.SummonerID = s.SummonerID
+	}
+	if p.ChampionID == 0 {
+		p.ChampionID = s.ChampionID
+	}
+	for _, f := range [][2]*string{
+		{&p.SummonerName, &s.SummonerName},
+		{&p.Tier, &s.Tier},
+		{&p.Division, &s.Division},
+		{&p.Spell1, &s.Spell1},
+		{&p.Spell2, &s.Spell2},
+	} {
+		if *f[0] == "" {
+			*f[0] = *f[1]
+		}
+	}
+}
+
+// remapParticipants replaces the participant ids of the event.
+func remapParticipants(e baseview.Event, mapping map[int64]int64) {
+	for _, id := range baseview.ParticipantRefs(e) {
+		if to, found := mapping[*id]; found {
+			*id = to
+		}
+	}
+}
+
+// key identifies an event across sources: two events of the same key at
+// (about) the same time are the same event. Ward deaths are keyed by ward
+// ids, so the secondary's must be mapped to the primary's first.
+func key(e baseview.Event) string {
+	switch t := e.(type) {
+	case *baseview.Attack:
+		return fmt.Sprintf("attack/%s/%d/%s/%s/%d", t.AttackerType, t.AttackerID, t.Slot, t.TargetType, t.TargetID)
+	case *baseview.BuildingKill:
+		return fmt.Sprintf("building_kill/%d", t.BuildingID)
+	case *baseview.Damage:
+		return fmt.Sprintf("damage/%s/%d/%s/%d", t.AttackerType, t.AttackerID, t.VictimType, t.VictimID)
+	case *baseview.Death:
+		return fmt.Sprintf("death/%d", t.VictimID)
+	case *baseview.EpicMonsterKill:
+		return fmt.Sprintf("epic_monster_kill/%d", t.VictimID)
+	case *baseview.GameEnd:
+		return "game_end"
+	case *baseview.ItemUsed:
+		return fmt.Sprintf("item_used/%d/%s", t.ParticipantID, t.Slot)
+	case *baseview.LevelUp:
+		return fmt.Sprintf("level_up/%d/%d", t.ParticipantID, t.Level)
+	case *baseview.SlotUpgrade:
+		return fmt.Sprintf("slot_upgrade/%d/%s/%d", t.ParticipantID, t.Slot, t.Level)
+	case *baseview.Spawn:
+		return fmt.Sprintf("spawn/%d", t.ParticipantID)
+	case *baseview.WardPlaced:
+		return fmt.Sprintf("ward_placed/%d/%s", t.ParticipantID, t.WardItemType)
+	case *baseview.WardDeath:
+		return fmt.Sprintf("ward_death/%d", t.WardID)
+	}
+	return e.Type()
+}
+
+// anchor returns true for events that happen at most once per key in a
+// match, which the clocks of two sources can be aligned by.
+func anchor(e baseview.Event) bool {
+	switch e.(type) {
+	case *baseview.BuildingKill, *baseview.LevelUp, *baseview.SlotUpgrade:
+		return true
+	}
+	return false
+}
+
+// align returns the median offset from the secondary's anchors to the
+// primary's, or 0 if they have none in common.
+func align(primary, secondary []baseview.Event) float64 {
+	times := map[string]float64{}
+	for _, e := range primary {
+		if anchor(e) {
+			times[key(e)] = e.Seconds()
+		}
+	}
+	offsets := []float64{}
+	for _, e := range secondary {
+		if !anchor(e) {
+			continue
+		}
+		if t, found := times[key(e)]; found && math.Abs(t-e.Seconds()) <= maxAlignOffset {
+			offsets = append(offsets, t-e.Seconds())
+		}
+	}
+	if len(offsets) == 0 {
+		return 0
+	}
+	sort.Float64s(offsets)
+	return offsets[len(offsets)/2]
+}
+
+// reconcile merges the events of the two sources, keeping the primary's where
+// both have an event, and returns them in order of time.
+func reconcile(primary, secondary []baseview.Event, tolerance float64, r *Report) []baseview.Event {
+	merged := []baseview.Event{}
+
+	// wards are matched first, so that the secondary's ward ids can be
+	// mapped to the primary's before their deaths (and attacks) are keyed.
+	wards := map[int64]int64{}
+	maxWardID := int64(0)
+	for _, e := range primary {
+		if t, ok := e.(*baseview.WardPlaced); ok && t.WardID > maxWardID {
+			maxWardID = t.WardID
+		}
+	}
+	isWard := func(e baseview.Event) bool { _, ok := e.(*baseview.WardPlaced); return ok }
+	match(filter(primary, isWard), filter(secondary, isWard), tolerance, r, func(p, s baseview.Event) {
+		switch {
+		case s == nil:
+			// only the primary has the ward, whose id is kept.
+		case p == nil:
+			maxWardID++
+			wards[s.(*baseview.WardPlaced).WardID] = maxWardID
+			s.(*baseview.WardPlaced).WardID = maxWardID
+		default:
+			wards[s.(*baseview.WardPlaced).WardID] = p.(*baseview.WardPlaced).WardID
+		}
+		merged = keep(merged, p, s)
+	})
+	// the secondary's events that refer to wards it never placed can't be
+	// mapped, and are dropped.
+	mapped := []baseview.Event{}
+	for _, e := range secondary {
+		if !mapWardRefs(e, wards) {
+			r.disagree(Disagreement{Kind: DisagreeWards, Seconds: e.Seconds(), EventType: e.Type(),
+				Message: fmt.Sprintf("%s refers to a ward %s never placed", e.Type(), r.Secondary)})
+			continue
+		}
+		mapped = append(mapped, e)
+	}
+	secondary = mapped
+
+	isState := func(e baseview.Event) bool { _, ok := e.(*baseview.StateUpdate); return ok }
+	isOther := func(e baseview.Event) bool { return !isWard(e) && !isState(e) }
+	match(filter(primary, isOther), filter(secondary, isOther), tolerance, r, func(p, s baseview.Event) {
+		merged = keep(merged, p, s)
+	})
+	merged = append(merged, mergeStates(filter(primary, isState), filter(secondary, isState), tolerance, r)...)
+
+	sort.Stable(byTime(merged))
+	return merged
+}
+
+// mapWardRefs maps the ward ids the event refers to, returning false if one
+// of them has no mapping. Placements are already mapped.
+func mapWardRefs(e baseview.Event, wards map[int64]int64) bool {
+	if _, placed := e.(*baseview.WardPlaced); placed {
+		return true
+	}
+	for _, id := range baseview.WardRefs(e) {
+		mapped, found := wards[*id]
+		if !found {
+			return false
+		}
+		*id = mapped
+	}
+	return true
+}
+
+// keep appends the primary's event, or the secondary's if the primary
+// doesn't have it.
+func keep(events []baseview.Event, p, s baseview.Event) []baseview.Event {
+	if p != nil {
+		return append(events, p)
+	}
+	return append(events, s)
+}
+
+func filter(events []baseview.Event, f func(baseview.Event) bool) []baseview.Event {
+	res := []baseview.Event{}
+	for _, e := range events {
+		if f(e) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// match pairs up the events of the same key that are within the tolerance of
+// each other, calling f with each pair, and with each event that has no pair
+// (and nil in place of the other).
+func match(primary, secondary []baseview.Event, tolerance float64, r *Report, f func(p, s baseview.Event)) {
+	byKey := map[string][2][]baseview.Event{}
+	keys := []string{}
+	for side, events := range [][]baseview.Event{primary, secondary} {
+		for _, e := range events {
+			k := key(e)
+			lists, found := byKey[k]
+			if !found {
+				keys = append(keys, k)
+			}
+			lists[side] = append(lists[side], e)
+			byKey[k] = lists
+		}
+	}
+
+	for _, k := range keys {
+		ps, ss := byKey[k][0], byKey[k][1]
+		i, j := 0, 0
+		for i < len(ps) || j < len(ss) {
+			switch {
+			case i < len(ps) && j < len(ss) && math.Abs(ps[i].Seconds()-ss[j].Seconds()) <= tolerance:
+				r.Matched[ps[i].Type()]++
+				comparePositions(ps[i], ss[j], r)
+				f(ps[i], ss[j])
+				i, j = i+1, j+1
+			case j == len(ss) || (i < len(ps) && ps[i].Seconds() < ss[j].Seconds()):
+				r.Missing[ps[i].Type()]++
+				f(ps[i], nil)
+				i++
+			default:
+				r.Added[ss[j].Type()]++
+				f(nil, ss[j])
+				j++
+			}
+		}
+	}
+}
+
+// comparePositions reports events that the sources place far apart.
+func comparePositions(p, s baseview.Event, r *Report) {
+	var pp, sp baseview.Position
+	var participantID int64
+	switch t := p.(type) {
+	case *baseview.Death:
+		pp, sp, participantID = t.Position, s.(*baseview.Death).Position, t.VictimID
+	case *baseview.Spawn:
+		pp, sp, participantID = t.Position, s.(*baseview.Spawn).Position, t.ParticipantID
+	case *baseview.WardPlaced:
+		pp, sp, participantID = t.Position, s.(*baseview.WardPlaced).Position, t.ParticipantID
+	default:
+		return
+	}
+	if d := pp.DistanceXY(sp); d > positionSlack {
+		r.disagree(Disagreement{
+			Kind:          DisagreePositions,
+			Seconds:       p.Seconds(),
+			EventType:     p.Type(),
+			ParticipantID: participantID,
+			Message:       fmt.Sprintf("%s is %.0f apart", p.Type(), d),
+		})
+	}
+}
+
+// mergeStates keeps the primary's state updates, and fills the gaps in them
+// with the secondary's.
+func mergeStates(primary, secondary []baseview.Event, tolerance float64, r *Report) []baseview.Event {
+	merged := append([]baseview.Event{}, primary...)
+	times := map[int64][]float64{}
+	states := map[int64][]*baseview.StateUpdate{}
+	for _, e := range primary {
+		t := e.(*baseview.StateUpdate)
+		times[t.ParticipantID] = append(times[t.ParticipantID], t.Seconds())
+		states[t.ParticipantID] = append(states[t.ParticipantID], t)
+	}
+
+	for _, e := range secondary {
+		s := e.(*baseview.StateUpdate)
+		ts := times[s.ParticipantID]
+		i := sort.SearchFloat64s(ts, s.Seconds())
+		nearest := -1
+		for _, n := range []int{i - 1, i} {
+			if n >= 0 && n < len(ts) && (nearest < 0 || math.Abs(ts[n]-s.Seconds()) < math.Abs(ts[nearest]-s.Seconds())) {
+				nearest = n
+			}
+		}
+
+		// the primary has a gap here (or nothing at all).
+		if nearest < 0 || math.Abs(ts[nearest]-s.Seconds()) > maxStateGap {
+			r.Added[s.Type()]++
+			merged = append(merged, s)
+			continue
+		}
+		r.Matched[s.Type()]++
+		p := states[s.ParticipantID][nearest]
+		if math.Abs(p.Seconds()-s.Seconds()) <= tolerance && p.Health > 0 && s.Health > 0 {
+			if d := p.Position.DistanceXY(s.Position); d > positionSlack {
+				r.disagree(Disagreement{
+					Kind:          DisagreeStates,
+					Seconds:       s.Seconds(),
+					EventType:     s.Type(),
+					ParticipantID: s.ParticipantID,
+					Message:       fmt.Sprintf("participant %d is %.0f apart", s.ParticipantID, d),
+				})
+			}
+		}
+	}
+	return merged
+}
+
+type byTime []baseview.Event
+
+func (a byTime) Len() int           { return len(a) }
+func (a byTime) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byTime) Less(i, j int) bool { return a[i].Seconds() < a[j].Seconds() }
@@ -0,0 +1,211 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/VantageSports/lolstats/baseview"
+
+	"gopkg.in/tylerb/is.v1"
+)
+
+func event(e baseview.Event, eventType string, seconds float64) baseview.Event {
+	e.SetType(eventType)
+	e.SetSeconds(seconds)
+	return e
+}
+
+// newMatch returns a one minute match seen by the source, with state updates
+// every second from the given range.
+func newMatch(source string, from, to int) *baseview.Baseview {
+	bv := &baseview.Baseview{
+		Version:  baseview.CurrentVersion,
+		Producer: &baseview.Producer{Source: source},
+		MapID:    baseview.MapSummonersRift,
+	}
+	for id := int64(1); id <= 10; id++ {
+		bv.Participants = append(bv.Participants, baseview.Participant{ParticipantID: id, SummonerID: 1000 + id, ChampionID: 100 + id})
+	}
+	for second := from; second <= to; second++ {
+		for id := int64(1); id <= 10; id++ {
+			bv.Events = append(bv.Events, event(&baseview.StateUpdate{
+				ParticipantID: id,
+				Health:        500,
+				HealthMax:     500,
+				Position:      baseview.Position{X: float64(100 * id), Y: 100},
+			}, "state_update", float64(second)))
+		}
+	}
+	return bv
+}
+
+func count(bv *baseview.Baseview, eventType, source string) int {
+	n := 0
+	for _, e := range bv.Events {
+		if e.Type() == eventType && e.Source() == source {
+			n++
+		}
+	}
+	return n
+}
+
+func TestMerge(t *testing.T) {
+	is := is.New(t)
+
+	// the ocr baseview starts 5 seconds late and has participants 1 and 2
+	// the other way around, but covers the end of the match, which the elo
+	// baseview doesn't.
+	elo := newMatch(baseview.SourceElo, 0, 40)
+	ocr := newMatch(baseview.SourceOCR, 25, 55)
+	ocr.Participants[0].ParticipantID, ocr.Participants[1].ParticipantID = 2, 1
+	ocr.Participants[0].SummonerName = "one"
+	for _, e := range ocr.Events {
+		e.SetSeconds(e.Seconds() - 5)
+		if t := e.(*baseview.StateUpdate); t.ParticipantID <= 2 {
+			t.ParticipantID = 3 - t.ParticipantID
+		}
+	}
+
+	elo.Events = append(elo.Events,
+		event(&baseview.LevelUp{ParticipantID: 1, Level: 2}, "level_up", 30),
+		event(&baseview.WardPlaced{ParticipantID: 3, WardID: 1, Position: baseview.Position{X: 1000, Y: 1000}}, "ward_placed", 31),
+		event(&baseview.Death{VictimID: 4, Position: baseview.Position{X: 5000, Y: 5000}}, "death", 32),
+	)
+	ocr.Events = append(ocr.Events,
+		event(&baseview.LevelUp{ParticipantID: 2, Level: 2}, "level_up", 25),
+		event(&baseview.LevelUp{ParticipantID: 3, Level: 2}, "level_up", 33),
+		event(&baseview.WardPlaced{ParticipantID: 3, WardID: 7, Position: baseview.Position{X: 1000, Y: 1100}}, "ward_placed", 26.5),
+		event(&baseview.WardDeath{WardID: 7}, "ward_death", 45),
+		// the ocr is a little off, but not by much.
+		event(&baseview.Death{VictimID: 4, Position: baseview.Position{X: 6000, Y: 5000}}, "death", 27.2),
+		event(&baseview.WardPlaced{ParticipantID: 5, WardID: 1}, "ward_placed", 48),
+		event(&baseview.WardDeath{WardID: 1}, "ward_death", 49),
+	)
+
+	// elo is preferred either way around.
+	merged, r, err := Merge(ocr, elo, DefaultOptions())
+	is.NotErr(err)
+	is.Equal(baseview.SourceElo, r.Primary)
+	is.Equal(5.0, r.Offset)
+	is.Equal(int64(2), r.Participants[1])
+	is.Equal(baseview.SourceMerged, merged.Producer.Source)
+	is.Equal("one", merged.Participants[0].SummonerName)
+
+	is.Equal(1, r.Matched["level_up"])
+	is.Equal(1, r.Added["level_up"])
+	is.Equal(1, r.Matched["ward_placed"])
+	is.Equal(1, r.Added["ward_placed"])
+	is.Equal(1, r.Matched["death"])
+	is.Equal(2, r.Added["ward_death"])
+	is.Equal(1, count(merged, "level_up", baseview.SourceOCR))
+	is.Equal(1, count(merged, "death", baseview.SourceElo))
+	is.Equal(0, count(merged, "death", baseview.SourceOCR))
+
+	// the ocr wards took the ids of elo's, or new ones.
+	wards := map[int64]int64{}
+	for _, e := range merged.Events {
+		switch t := e.(type) {
+		case *baseview.WardPlaced:
+			wards[t.WardID] = t.ParticipantID
+		case *baseview.WardDeath:
+			is.Equal(baseview.SourceOCR, t.Source())
+			is.True(wards[t.WardID] != 0)
+		}
+	}
+	is.Equal(int64(3), wards[1])
+	is.Equal(int64(5), wards[2])
+
+	// state updates after 40 seconds come from ocr, which also agrees with
+	// elo on the overlap.
+	is.Equal(410, count(merged, "state_update", baseview.SourceElo))
+	is.Equal(130, count(merged, "state_update", baseview.SourceOCR))
+	for i := 1; i < len(merged.Events); i++ {
+		is.True(merged.Events[i-1].Seconds() <= merged.Events[i].Seconds())
+	}
+
+	is.Equal(1, r.Counts[DisagreePositions])
+	is.Equal(int64(4), r.Disagreements[0].ParticipantID)
+	is.Equal(0, r.Counts[DisagreeStates])
+
+	// the inputs are untouched.
+	is.Equal("", elo.Events[0].Source())
+	is.Equal(int64(7), ocr.Events[len(ocr.Events)-4].(*baseview.WardDeath).WardID)
+}
+
+func TestMergeWards(t *testing.T) {
+	is := is.New(t)
+
+	// only elo saw the ward placed at 5s, and only ocr one placed before it
+	// started recording, which is attacked and dies.
+	elo := newMatch(baseview.SourceElo, 0, 10)
+	ocr := newMatch(baseview.SourceOCR, 0, 10)
+	elo.Events = append(elo.Events,
+		event(&baseview.WardPlaced{ParticipantID: 3, WardID: 4}, "ward_placed", 5),
+		event(&baseview.WardDeath{WardID: 4}, "ward_death", 8),
+	)
+	ocr.Events = append(ocr.Events,
+		event(&baseview.Attack{AttackerID: 6, TargetType: baseview.ActorWard, TargetID: 2}, "attack", 6),
+		event(&baseview.WardDeath{WardID: 2}, "ward_death", 7),
+	)
+
+	merged, r, err := Merge(elo, ocr, DefaultOptions())
+	is.NotErr(err)
+	is.Equal(1, r.Missing["ward_placed"])
+	is.Equal(2, r.Counts[DisagreeWards])
+	is.Equal(0, count(merged, "attack", baseview.SourceOCR))
+	is.Equal(0, count(merged, "ward_death", baseview.SourceOCR))
+	is.Equal(1, count(merged, "ward_placed", baseview.SourceElo))
+	for _, e := range merged.Events {
+		if t, ok := e.(*baseview.WardDeath); ok {
+			is.Equal(int64(4), t.WardID)
+		}
+	}
+}
+
+func TestMergeOffset(t *testing.T) {
+	is := is.New(t)
+
+	elo := newMatch(baseview.SourceElo, 0, 10)
+	ocr := newMatch(baseview.SourceOCR, 0, 10)
+	for _, e := range ocr.Events {
+		if t := e.(*baseview.StateUpdate); t.ParticipantID == 1 {
+			t.Position.X = 5000
+		}
+	}
+
+	opts := DefaultOptions()
+	opts.Align = false
+	opts.Offset = 0.5
+	merged, r, err := Merge(elo, ocr, opts)
+	is.NotErr(err)
+	is.Equal(0.5, r.Offset)
+	is.Equal(110, len(merged.Events))
+	is.Equal(11, r.Counts[DisagreeStates])
+	is.Equal(11, len(r.Disagreements))
+}
+
+func TestMergeErrors(t *testing.T) {
+	is := is.New(t)
+
+	elo := newMatch(baseview.SourceElo, 0, 1)
+	ocr := newMatch(baseview.SourceOCR, 0, 1)
+
+	elo.Producer = nil
+	_, _, err := Merge(elo, ocr, DefaultOptions())
+	is.Err(err)
+
+	elo = newMatch(baseview.SourceElo, 0, 1)
+	ocr.Participants[3].SummonerID = 0
+	ocr.Participants[3].ChampionID = 0
+	ocr.Participants[3].ParticipantID = 11
+	_, _, err = Merge(elo, ocr, DefaultOptions())
+	is.Err(err)
+
+	ocr = newMatch(baseview.SourceOCR, 0, 1)
+	ocr.MapID = baseview.MapHowlingAbyss
+	ocr.Participants[0].SummonerID = 1006
+	ocr.Participants[5].SummonerID = 1001
+	_, r, err := Merge(elo, ocr, DefaultOptions())
+	is.NotErr(err)
+	is.Equal(1, r.Counts[DisagreeMap])
+	is.Equal(2, r.Counts[DisagreeParticipants])
+}
@@ -0,0 +1,222 @@
+// Package ocr builds baseviews from the output of the lolocr pipeline, i.e.
+// the frame states it reads from a participant's video, after post
+// processing has aligned them to the match clock (the align step). It does
+// what lolocr's post_process/baseview_events.py does, so that OCR'd matches
+// can be converted, linted and tested in go like elo's.
+//
+// A video only shows its own participant, and OCR only sees their position,
+// health and mana, and when their abilities go on cooldown. Everything else
+// (kills, wards, other participants) has to be merged in from another source.
+
+package ocr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// abilityUsedPct is how much an ability's brightness has to drop (as a percent
+// of its brightest) for it to have just been used: the icon darkens while the
+// ability is on cooldown.
+const abilityUsedPct = -30.0
+
+// abilitySlots are the keys of the abilities in the frames' brightness, and
+// their baseview slots.
+var abilitySlots = map[string]string{"q": "Q", "w": "W", "e": "E", "r": "R"}
+
+// Output is lolocr's post processed output.
+type Output struct {
+	// Steps are the post processing steps that were run, e.g. "align".
+	Steps  []string `json:"steps"`
+	States []Frame  `json:"states"`
+}
+
+// Frame is what OCR read from a frame of the video.
+type Frame struct {
+	FrameNum int64   `json:"frame_num"`
+	VideoSec float64 `json:"video_sec"`
+	// MatchSec is the time of the frame in the match, or nil if the frame
+	// couldn't be aligned to the match (e.g. it is from before the match).
+	MatchSec   *float64 `json:"match_sec,omitempty"`
+	PovChamp   int64    `json:"pov_champ"`
+	SummonerID int64    `json:"summoner_id,omitempty"`
+
+	Location   *Location          `json:"location,omitempty"`
+	Bars       map[string]Bar     `json:"bars,omitempty"`
+	Brightness map[string]float64 `json:"brightness,omitempty"`
+	// Events are the changes in the brightness and bars since the previous
+	// frames.
+	Events []Change `json:"events,omitempty"`
+}
+
+type Location struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Bar is a health, mana or experience bar. Experience only has a percent.
+type Bar struct {
+	Current float64 `json:"current"`
+	Max     float64 `json:"max"`
+	Percent float64 `json:"percent"`
+}
+
+// Change is a significant change in a frame's brightness or bars, e.g. an
+// ability going on cooldown.
+type Change struct {
+	// Type is CHANGE, or MAX when the value reaches its maximum.
+	Type string `json:"type"`
+	Key  string `json:"key"`
+	// Pct is the change as a percent of the value's maximum.
+	Pct float64 `json:"pct"`
+}
+
+// Read decodes lolocr output: either the post processed object, or just the
+// frame states.
+func Read(r io.Reader) (*Output, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	out := &Output{}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		err = json.Unmarshal(data, &out.States)
+	} else {
+		err = json.Unmarshal(data, out)
+	}
+	return out, err
+}
+
+// ReadFile reads lolocr output from a json file.
+func ReadFile(path string) (*Output, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// ConversionStats describes how much of the OCR output made it into the
+// baseview.
+type ConversionStats struct {
+	// Frames is the number of frames read.
+	Frames int `json:"frames"`
+	// Unaligned is the number of frames dropped because they had no match time.
+	Unaligned int `json:"unaligned"`
+	// NoLocation is the number of aligned frames without a location, which
+	// have no state update.
+	NoLocation   int `json:"no_location"`
+	StateUpdates int `json:"state_updates"`
+	Attacks      int `json:"attacks"`
+	// Views are the (sorted) participants whose point of view the frames are
+	// from.
+	Views []int64 `json:"views"`
+}
+
+// ToBaseview converts the frames of the OCR output into a baseview of the
+// participants (e.g. from the match details) on the map. Every frame must be
+// from a participant's point of view.
+func ToBaseview(out *Output, participants []baseview.Participant, mapID int64) (*baseview.Baseview, *ConversionStats, error) {
+	isParticipant := map[int64]bool{}
+	for _, p := range participants {
+		isParticipant[p.ParticipantID] = true
+	}
+
+	bv := &baseview.Baseview{
+		Version:      baseview.CurrentVersion,
+		Producer:     &baseview.Producer{Source: baseview.SourceOCR},
+		MapID:        mapID,
+		Participants: participants,
+		Events:       []baseview.Event{},
+		LastUpdated:  time.Now(),
+	}
+	stats := &ConversionStats{Frames: len(out.States), Views: []int64{}}
+	views := map[int64]bool{}
+
+	for i := range out.States {
+		f := &out.States[i]
+		if f.MatchSec == nil || *f.MatchSec < 0 {
+			stats.Unaligned++
+			continue
+		}
+		if !isParticipant[f.PovChamp] {
+			return nil, nil, fmt.Errorf("frame %d is from the point of view of %d, who isn't a participant", f.FrameNum, f.PovChamp)
+		}
+		if !views[f.PovChamp] {
+			views[f.PovChamp] = true
+			stats.Views = append(stats.Views, f.PovChamp)
+		}
+
+		if su := stateUpdate(f); su != nil {
+			bv.Events = append(bv.Events, su)
+			stats.StateUpdates++
+		} else {
+			stats.NoLocation++
+		}
+		attacks := abilitiesUsed(f)
+		bv.Events = append(bv.Events, attacks...)
+		stats.Attacks += len(attacks)
+	}
+
+	if stats.Frames > 0 && stats.Unaligned == stats.Frames {
+		return nil, nil, fmt.Errorf("none of the %d frames are aligned to the match (steps: %v)", stats.Frames, out.Steps)
+	}
+	sort.Sort(int64s(stats.Views))
+	return bv, stats, nil
+}
+
+// stateUpdate returns the state of the frame's participant, or nil if the
+// frame has no location.
+func stateUpdate(f *Frame) *baseview.StateUpdate {
+	if f.Location == nil {
+		return nil
+	}
+	health, mana := f.Bars["health"], f.Bars["mana"]
+	su := &baseview.StateUpdate{
+		ParticipantID: f.PovChamp,
+		Position:      baseview.Position{X: f.Location.X, Y: f.Location.Y},
+		Health:        health.Current,
+		HealthMax:     health.Max,
+		Mana:          mana.Current,
+		ManaMax:       mana.Max,
+	}
+	su.SetType("state_update")
+	su.SetSeconds(*f.MatchSec)
+	return su
+}
+
+// abilitiesUsed returns an attack for each of the frame's abilities that went
+// on cooldown. OCR can't tell what (if anything) they hit.
+func abilitiesUsed(f *Frame) []baseview.Event {
+	attacks := []baseview.Event{}
+	for _, c := range f.Events {
+		slot, ok := abilitySlots[c.Key]
+		if !ok || c.Type != "CHANGE" || c.Pct >= abilityUsedPct {
+			continue
+		}
+		a := &baseview.Attack{
+			AttackerID:   f.PovChamp,
+			AttackerType: baseview.ActorHero,
+			Slot:         slot,
+		}
+		a.SetType("attack")
+		a.SetSeconds(*f.MatchSec)
+		attacks = append(attacks, a)
+	}
+	return attacks
+}
+
+type int64s []int64
+
+func (s int64s) Len() int           { return len(s) }
+func (s int64s) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s int64s) Less(i, j int) bool { return s[i] < s[j] }
@@ -0,0 +1,143 @@
+package ocr
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/tylerb/is.v1"
+
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/lolstats/baseview/lint"
+	"github.com/VantageSports/lolstats/testutil"
+)
+
+// The test output is 40 seconds of participant 7's video in match
+// 2190082947-NA1, after lolocr's post processing, and two frames from before
+// the match.
+const (
+	testOutputPath   = "../../testdata/2190082947-NA1-7.ocr.json"
+	testBaseviewPath = "../../testdata/2190082947-NA1-7.ocr.baseview.json"
+	testSummonerID   = 27049826
+)
+
+// change to true if you want to regenerate the golden file.
+var rewriteGolden = false
+
+func testParticipants() []baseview.Participant {
+	participants := []baseview.Participant{}
+	for id := int64(1); id <= 10; id++ {
+		participants = append(participants, baseview.Participant{ParticipantID: id, ChampionID: 100 + id})
+	}
+	participants[6].SummonerID = testSummonerID
+	return participants
+}
+
+func TestToBaseview(t *testing.T) {
+	is := is.New(t)
+
+	out, err := ReadFile(testOutputPath)
+	is.NotErr(err)
+	bv, stats, err := ToBaseview(out, testParticipants(), baseview.MapSummonersRift)
+	is.NotErr(err)
+
+	is.Equal(202, stats.Frames)
+	is.Equal(2, stats.Unaligned)
+	is.Equal(200, stats.StateUpdates)
+	is.Equal(3, stats.Attacks)
+	is.Equal([]int64{7}, stats.Views)
+	is.Equal(baseview.SourceOCR, bv.Producer.Source)
+
+	// the same checks as any other baseview, except for the participants
+	// without a video.
+	is.NotErr(lint.LintViews(bv, stats.Views).Err())
+
+	bv.LastUpdated = time.Date(2016, 1, 2, 3, 4, 5, 6, time.UTC)
+	ourData, err := json.MarshalIndent(bv, "", "  ")
+	is.NotErr(err)
+	if rewriteGolden {
+		is.NotErr(ioutil.WriteFile(testBaseviewPath, ourData, 0600))
+		t.Error("rewrote golden file. test is invalid")
+	}
+	goldenData, err := ioutil.ReadFile(testBaseviewPath)
+	is.NotErr(err)
+	if !bytes.Equal(goldenData, ourData) {
+		t.Errorf("found diff between golden file (-) and baseview (+)...\n")
+		t.Error(testutil.DiffLines(goldenData, ourData, t))
+	}
+}
+
+// TestPythonParity checks that the abilities used are the same as the
+// baseview_events that lolocr's post processing found.
+func TestPythonParity(t *testing.T) {
+	is := is.New(t)
+
+	data, err := ioutil.ReadFile(testOutputPath)
+	is.NotErr(err)
+	py := struct {
+		States []struct {
+			BaseviewEvents []struct {
+				Attacker string  `json:"attacker"`
+				Ability  string  `json:"ability"`
+				Seconds  float64 `json:"seconds"`
+			} `json:"baseview_events"`
+		} `json:"states"`
+	}{}
+	is.NotErr(json.Unmarshal(data, &py))
+
+	out, err := Read(bytes.NewReader(data))
+	is.NotErr(err)
+	bv, _, err := ToBaseview(out, testParticipants(), baseview.MapSummonersRift)
+	is.NotErr(err)
+
+	attacks := []*baseview.Attack{}
+	for _, e := range bv.Events {
+		if a, ok := e.(*baseview.Attack); ok {
+			attacks = append(attacks, a)
+		}
+	}
+	i := 0
+	for _, s := range py.States {
+		for _, e := range s.BaseviewEvents {
+			is.True(i < len(attacks))
+			is.Equal("27049826", e.Attacker)
+			is.Equal(int64(7), attacks[i].AttackerID)
+			is.Equal(e.Ability, attacks[i].Slot)
+			is.Equal(e.Seconds, attacks[i].Seconds())
+			i++
+		}
+	}
+	is.Equal(len(attacks), i)
+}
+
+func TestRead(t *testing.T) {
+	is := is.New(t)
+
+	// unprocessed output is just the states.
+	out, err := Read(strings.NewReader(`[{"frame_num": 5, "pov_champ": 7, "location": {"x": 1, "y": 2}}]`))
+	is.NotErr(err)
+	is.Equal(1, len(out.States))
+	is.Nil(out.States[0].MatchSec)
+
+	// which can't be converted until they are aligned.
+	_, _, err = ToBaseview(out, testParticipants(), baseview.MapSummonersRift)
+	is.Err(err)
+
+	out, err = Read(strings.NewReader(`{"steps": ["align"], "states": [
+		{"frame_num": 5, "match_sec": 1.5, "pov_champ": 7, "events": [{"type": "CHANGE", "key": "q", "pct": -50}]},
+		{"frame_num": 10, "match_sec": 1.7, "pov_champ": 11, "location": {"x": 1, "y": 2}}]}`))
+	is.NotErr(err)
+	is.Equal([]string{"align"}, out.Steps)
+	_, _, err = ToBaseview(out, testParticipants(), baseview.MapSummonersRift)
+	is.Err(err)
+
+	out.States = out.States[:1]
+	bv, stats, err := ToBaseview(out, testParticipants(), baseview.MapSummonersRift)
+	is.NotErr(err)
+	is.Equal(1, stats.NoLocation)
+	is.Equal(1, len(bv.Events))
+	is.Equal("Q", bv.Events[0].(*baseview.Attack).Slot)
+}
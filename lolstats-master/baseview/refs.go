@@ -0,0 +1,56 @@
+package baseview
+
+// ParticipantRefs returns pointers to the participant ids that the event
+// refers to, so they can be read or rewritten. Actor ids that aren't heroes
+// (e.g. the id of a minion that attacks) aren't participant ids.
+func ParticipantRefs(e Event) []*int64 {
+	refs := []*int64{}
+	hero := func(actor ActorType, id *int64) {
+		if actor == ActorHero {
+			refs = append(refs, id)
+		}
+	}
+	switch t := e.(type) {
+	case *Attack:
+		hero(t.AttackerType, &t.AttackerID)
+		hero(t.TargetType, &t.TargetID)
+	case *Damage:
+		hero(t.AttackerType, &t.AttackerID)
+		hero(t.VictimType, &t.VictimID)
+	case *Death:
+		refs = append(refs, &t.VictimID)
+	case *EpicMonsterKill:
+		// monsters can die without a champion killing them.
+		if t.KillerID != 0 {
+			refs = append(refs, &t.KillerID)
+		}
+	case *ItemUsed:
+		refs = append(refs, &t.ParticipantID)
+	case *LevelUp:
+		refs = append(refs, &t.ParticipantID)
+	case *SlotUpgrade:
+		refs = append(refs, &t.ParticipantID)
+	case *StateUpdate:
+		refs = append(refs, &t.ParticipantID)
+	case *Spawn:
+		refs = append(refs, &t.ParticipantID)
+	case *WardPlaced:
+		refs = append(refs, &t.ParticipantID)
+	}
+	return refs
+}
+
+// WardRefs returns pointers to the ward ids that the event refers to.
+func WardRefs(e Event) []*int64 {
+	switch t := e.(type) {
+	case *Attack:
+		if t.TargetType == ActorWard {
+			return []*int64{&t.TargetID}
+		}
+	case *WardPlaced:
+		return []*int64{&t.WardID}
+	case *WardDeath:
+		return []*int64{&t.WardID}
+	}
+	return nil
+}
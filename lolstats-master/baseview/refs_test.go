@@ -0,0 +1,34 @@
+package baseview
+
+import (
+	"testing"
+
+	"gopkg.in/tylerb/is.v1"
+)
+
+func TestRefs(t *testing.T) {
+	is := is.New(t)
+
+	values := func(refs []*int64) []int64 {
+		res := []int64{}
+		for _, r := range refs {
+			res = append(res, *r)
+		}
+		return res
+	}
+
+	is.Equal([]int64{1, 2}, values(ParticipantRefs(&Damage{AttackerID: 1, AttackerType: ActorHero, VictimID: 2, VictimType: ActorHero})))
+	is.Equal([]int64{2}, values(ParticipantRefs(&Damage{AttackerID: 1, AttackerType: ActorMinion, VictimID: 2, VictimType: ActorHero})))
+	is.Equal([]int64{1}, values(ParticipantRefs(&Attack{AttackerID: 1, AttackerType: ActorHero, TargetID: 7, TargetType: ActorWard})))
+	is.Equal([]int64{7}, values(WardRefs(&Attack{AttackerID: 1, AttackerType: ActorHero, TargetID: 7, TargetType: ActorWard})))
+	is.Equal(0, len(ParticipantRefs(&BuildingKill{BuildingID: TurretBlueMidOuter})))
+	is.Equal([]int64{6}, values(ParticipantRefs(&EpicMonsterKill{VictimID: MonsterBaron, KillerID: 6})))
+	is.Equal(0, len(ParticipantRefs(&EpicMonsterKill{VictimID: MonsterBaron})))
+
+	w := &WardPlaced{ParticipantID: 3, WardID: 4}
+	for _, r := range append(ParticipantRefs(w), WardRefs(w)...) {
+		*r *= 10
+	}
+	is.Equal(int64(30), w.ParticipantID)
+	is.Equal(int64(40), w.WardID)
+}
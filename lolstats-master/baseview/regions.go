@@ -0,0 +1,291 @@
+package baseview
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Layers of a map. Area regions cover the whole map between them, and every
+// position is in (at least) one. Features are smaller regions, like brush or
+// the dragon pit, that sit on top of the areas.
+const (
+	LayerArea    = "area"
+	LayerFeature = "feature"
+)
+
+// Kinds of region.
+const (
+	KindBase     = "base"
+	KindLane     = "lane"
+	KindJungle   = "jungle"
+	KindRiver    = "river"
+	KindPit      = "pit"
+	KindBrush    = "brush"
+	KindFountain = "fountain"
+)
+
+// indexCells is the number of rows (and columns) of the grid that a map
+// indexes its regions in.
+const indexCells = 16
+
+//go:embed maps/*.json
+var mapFiles embed.FS
+
+var (
+	maps     map[int64]*Map
+	mapsOnce sync.Once
+)
+
+func loadMaps() {
+	maps = map[int64]*Map{}
+	files, err := mapFiles.ReadDir("maps")
+	if err != nil {
+		panic("baseview: " + err.Error())
+	}
+	for _, file := range files {
+		f, err := mapFiles.Open("maps/" + file.Name())
+		if err != nil {
+			panic("baseview: " + err.Error())
+		}
+		m, err := LoadMap(f)
+		f.Close()
+		if err != nil {
+			panic(fmt.Sprintf("baseview: bad map %s: %v", file.Name(), err))
+		}
+		maps[m.ID] = m
+	}
+}
+
+// MapByID returns the geometry of the map, or nil if it isn't supported. An
+// id of 0 is taken to be summoner's rift, which every baseview of version 2
+// or earlier was recorded on.
+func MapByID(id int64) *Map {
+	mapsOnce.Do(loadMaps)
+	if id == 0 {
+		id = MapSummonersRift
+	}
+	return maps[id]
+}
+
+// SummonersRift returns the geometry of summoner's rift. The polygons are
+// traced (approximately) from the minimap.
+func SummonersRift() *Map {
+	return MapByID(MapSummonersRift)
+}
+
+// A Polygon is a list of [x, y] vertices. The last vertex joins the first.
+type Polygon [][2]float64
+
+// Contains returns true if the point (on the XY plane) is inside the polygon.
+// Points exactly on an edge may go either way.
+func (poly Polygon) Contains(p Position) bool {
+	in := false
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		a, b := poly[i], poly[j]
+		if (a[1] > p.Y) != (b[1] > p.Y) && p.X < a[0]+(p.Y-a[1])*(b[0]-a[0])/(b[1]-a[1]) {
+			in = !in
+		}
+	}
+	return in
+}
+
+type box struct {
+	XMin, XMax, YMin, YMax float64
+}
+
+func (b box) intersects(o box) bool {
+	return b.XMin <= o.XMax && o.XMin <= b.XMax && b.YMin <= o.YMax && o.YMin <= b.YMax
+}
+
+func (poly Polygon) bounds() box {
+	b := box{math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)}
+	for _, v := range poly {
+		b.XMin, b.XMax = math.Min(b.XMin, v[0]), math.Max(b.XMax, v[0])
+		b.YMin, b.YMax = math.Min(b.YMin, v[1]), math.Max(b.YMax, v[1])
+	}
+	return b
+}
+
+// A Region is a named part of a map.
+type Region struct {
+	Name  string `json:"name"`
+	Layer string `json:"layer"`
+	Kind  string `json:"kind"`
+	// Team is the side of the map the region is on, or 0 for neutral
+	// regions, like the river.
+	Team int64 `json:"team,omitempty"`
+	// Area is the id (AreaBlueBase etc.) of area regions.
+	Area int64 `json:"area,omitempty"`
+	// Where area regions overlap, the one with the highest priority wins.
+	Priority int     `json:"priority,omitempty"`
+	Polygon  Polygon `json:"polygon"`
+
+	bounds box
+}
+
+// Contains returns true if the position is inside the region.
+func (r *Region) Contains(p Position) bool {
+	return r.bounds.intersects(box{p.X, p.X, p.Y, p.Y}) && r.Polygon.Contains(p)
+}
+
+// Map is the geometry of a map: its turrets, fountains and regions, which are
+// indexed for point queries.
+type Map struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Bounds struct {
+		XMin float64 `json:"x_min"`
+		XMax float64 `json:"x_max"`
+		YMin float64 `json:"y_min"`
+		YMax float64 `json:"y_max"`
+	} `json:"bounds"`
+	// Fountains are keyed by team id, and turrets by building id (e.g.
+	// TurretBlueMidOuter).
+	Fountains map[int64]Position `json:"fountains"`
+	Turrets   map[int64]Position `json:"turrets"`
+	Regions   []*Region          `json:"regions"`
+
+	byName map[string]*Region
+	// the regions whose bounds intersect each cell of a grid over the map.
+	cells [indexCells * indexCells][]*Region
+}
+
+// LoadMap reads a map from its json definition.
+func LoadMap(r io.Reader) (*Map, error) {
+	m := &Map{byName: map[string]*Region{}}
+	if err := json.NewDecoder(r).Decode(m); err != nil {
+		return nil, err
+	}
+	if m.Bounds.XMax <= m.Bounds.XMin || m.Bounds.YMax <= m.Bounds.YMin {
+		return nil, fmt.Errorf("map %s has empty bounds", m.Name)
+	}
+
+	// regions are kept in priority order, so the first match wins.
+	sort.Stable(byPriority(m.Regions))
+	for _, r := range m.Regions {
+		if len(r.Polygon) < 3 {
+			return nil, fmt.Errorf("region %s has %d vertices", r.Name, len(r.Polygon))
+		}
+		if r.Layer != LayerArea && r.Layer != LayerFeature {
+			return nil, fmt.Errorf("region %s has unknown layer %q", r.Name, r.Layer)
+		}
+		if _, found := m.byName[r.Name]; found {
+			return nil, fmt.Errorf("region %s is defined twice", r.Name)
+		}
+		m.byName[r.Name] = r
+		r.bounds = r.Polygon.bounds()
+
+		for i := range m.cells {
+			if r.bounds.intersects(m.cellBounds(i)) {
+				m.cells[i] = append(m.cells[i], r)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *Map) cellBounds(i int) box {
+	w := (m.Bounds.XMax - m.Bounds.XMin) / indexCells
+	h := (m.Bounds.YMax - m.Bounds.YMin) / indexCells
+	x, y := float64(i%indexCells), float64(i/indexCells)
+	return box{
+		XMin: m.Bounds.XMin + x*w,
+		XMax: m.Bounds.XMin + (x+1)*w,
+		YMin: m.Bounds.YMin + y*h,
+		YMax: m.Bounds.YMin + (y+1)*h,
+	}
+}
+
+// cell returns the regions that might contain the position. Positions off the
+// map use the nearest cell.
+func (m *Map) cell(p Position) []*Region {
+	cell := func(v, min, max float64) int {
+		i := int((v - min) / (max - min) * indexCells)
+		if i < 0 {
+			return 0
+		} else if i >= indexCells {
+			return indexCells - 1
+		}
+		return i
+	}
+	x := cell(p.X, m.Bounds.XMin, m.Bounds.XMax)
+	y := cell(p.Y, m.Bounds.YMin, m.Bounds.YMax)
+	return m.cells[y*indexCells+x]
+}
+
+// Region returns the region with the name, or nil.
+func (m *Map) Region(name string) *Region {
+	return m.byName[name]
+}
+
+// RegionsAt returns every region (of either layer) that contains the
+// position, highest priority first.
+func (m *Map) RegionsAt(p Position) []*Region {
+	regions := []*Region{}
+	for _, r := range m.cell(p) {
+		if r.Contains(p) {
+			regions = append(regions, r)
+		}
+	}
+	return regions
+}
+
+// AreaID returns the id of the area that contains the position, which is
+// moved onto the map first if it is off the edge. It returns 0 if no area
+// contains the position. On summoner's rift it is the heuristic AreaID, which
+// existing stats depend on.
+func (m *Map) AreaID(p Position) int64 {
+	if m.ID == MapSummonersRift {
+		return AreaID(p)
+	}
+	p.X = math.Min(math.Max(p.X, m.Bounds.XMin+1), m.Bounds.XMax-1)
+	p.Y = math.Min(math.Max(p.Y, m.Bounds.YMin+1), m.Bounds.YMax-1)
+	if area := m.AreaAt(p); area != nil {
+		return area.Area
+	}
+	return 0
+}
+
+// AreaAt returns the area that contains the position, or nil if it is off
+// the map.
+func (m *Map) AreaAt(p Position) *Region {
+	for _, r := range m.cell(p) {
+		if r.Layer == LayerArea && r.Contains(p) {
+			return r
+		}
+	}
+	return nil
+}
+
+// FeaturesAt returns the features that contain the position.
+func (m *Map) FeaturesAt(p Position) []*Region {
+	features := []*Region{}
+	for _, r := range m.cell(p) {
+		if r.Layer == LayerFeature && r.Contains(p) {
+			features = append(features, r)
+		}
+	}
+	return features
+}
+
+// Areas returns the regions of the area layer.
+func (m *Map) Areas() []*Region {
+	areas := []*Region{}
+	for _, r := range m.Regions {
+		if r.Layer == LayerArea {
+			areas = append(areas, r)
+		}
+	}
+	return areas
+}
+
+type byPriority []*Region
+
+func (a byPriority) Len() int           { return len(a) }
+func (a byPriority) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byPriority) Less(i, j int) bool { return a[i].Priority > a[j].Priority }
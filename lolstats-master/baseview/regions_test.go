@@ -0,0 +1,159 @@
+package baseview
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/tylerb/is.v1"
+)
+
+func TestRegions(t *testing.T) {
+	is := is.New(t)
+
+	sr := SummonersRift()
+	cases := []struct {
+		area     string
+		features []string
+		pos      Position
+	}{
+		{"blue_base", []string{"blue_fountain"}, Position{X: 400, Y: 400}},
+		{"top_river", []string{"baron_pit"}, Position{X: 4950, Y: 10400}},
+		{"bottom_river", []string{"dragon_pit"}, Position{X: 9900, Y: 4500}},
+		{"blue_top_jungle", []string{"blue_tri_brush"}, Position{X: 2300, Y: 9800}},
+		{"red_bot_jungle", []string{"red_tri_brush"}, Position{X: 12570, Y: 5180}},
+		{"top_river", []string{"top_river_brush"}, Position{X: 6600, Y: 8400}},
+		{"red_mid_lane", nil, Position{X: 7500, Y: 8000}},
+		{"blue_bot_lane", nil, Position{X: 7000, Y: 1500}},
+	}
+	for i, c := range cases {
+		msg := fmt.Sprintf("case %d %v", i+1, c.pos)
+		area := sr.AreaAt(c.pos)
+		is.Msg(msg).NotNil(area)
+		is.Msg(msg).Equal(c.area, area.Name)
+
+		features := []string{}
+		for _, f := range sr.FeaturesAt(c.pos) {
+			features = append(features, f.Name)
+		}
+		is.Msg(msg).Equal(len(c.features), len(features))
+		is.Msg(msg).Equal(strings.Join(c.features, ","), strings.Join(features, ","))
+		is.Msg(msg).Equal(area, sr.RegionsAt(c.pos)[0])
+	}
+
+	is.Nil(sr.AreaAt(Position{X: -500, Y: 500}))
+	is.Equal(AreaBlueBase, AreaID(Position{X: -500, Y: 500}))
+	is.Equal("baron_pit", sr.Region("baron_pit").Name)
+	is.Nil(sr.Region("nope"))
+	is.Equal(14, len(sr.Areas()))
+}
+
+// Every position on the map is in an area, and the map is symmetric under a
+// half turn (which swaps teams, and top for bottom).
+func TestRegionsCoverMap(t *testing.T) {
+	is := is.New(t)
+
+	sr := SummonersRift()
+	swapped := map[int64]int64{
+		AreaBlueBase: AreaRedBase, AreaBlueTopLane: AreaRedBotLane, AreaBlueTopJungle: AreaRedBotJungle,
+		AreaBlueMidLane: AreaRedMidLane, AreaBlueBotJungle: AreaRedTopJungle, AreaBlueBotLane: AreaRedTopLane,
+		AreaTopRiver: AreaBottomRiver,
+	}
+	for id, other := range swapped {
+		swapped[other] = id
+	}
+
+	for x := SRXMin + 1; x < SRXMax; x += 97 {
+		for y := SRYMin + 1; y < SRYMax; y += 101 {
+			p := Position{X: x, Y: y}
+			area := sr.AreaAt(p)
+			is.Msg(fmt.Sprint(p)).NotNil(area)
+
+			rotated := sr.AreaAt(Position{X: SRXMax - x, Y: SRYMax - y})
+			if rotated != nil && rotated.Area == swapped[area.Area] {
+				continue
+			}
+			// the areas only disagree along their edges.
+			near := false
+			for _, d := range []Position{{X: 5}, {X: -5}, {Y: 5}, {Y: -5}} {
+				if sr.AreaAt(Position{X: SRXMax - x + d.X, Y: SRYMax - y + d.Y}).Area == swapped[area.Area] {
+					near = true
+				}
+			}
+			is.Msg(fmt.Sprint(p)).True(near)
+		}
+	}
+}
+
+func TestMaps(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(SummonersRift(), MapByID(0))
+	is.Equal(TurretPositions, SummonersRift().Turrets)
+	is.Equal(22, len(TurretPositions))
+	is.Nil(MapByID(10))
+
+	ha := MapByID(MapHowlingAbyss)
+	is.NotNil(ha)
+	is.Equal(10, len(ha.Turrets))
+	is.Equal(AreaBlueBase, ha.AreaID(ha.Fountains[BlueTeam]))
+	is.Equal(AreaRedBase, ha.AreaID(ha.Fountains[RedTeam]))
+	is.Equal(AreaBlueMidLane, ha.AreaID(Position{X: 5000, Y: 5500}))
+	is.Equal(AreaRedMidLane, ha.AreaID(Position{X: 7000, Y: 7500}))
+	// off the map.
+	is.Equal(AreaRedBase, ha.AreaID(Position{X: 20000, Y: 20000}))
+	for id, p := range ha.Turrets {
+		is.Equal(id/100*100, ha.AreaAt(p).Team)
+	}
+}
+
+func TestLoadMap(t *testing.T) {
+	is := is.New(t)
+
+	m, err := LoadMap(strings.NewReader(`{"name": "square", "bounds": {"x_min": 0, "x_max": 10, "y_min": 0, "y_max": 10},
+		"regions": [
+			{"name": "low", "layer": "area", "polygon": [[0, 0], [10, 0], [10, 10], [0, 10]]},
+			{"name": "high", "layer": "area", "priority": 1, "polygon": [[0, 0], [5, 0], [0, 5]]}
+		]}`))
+	is.NotErr(err)
+	is.Equal("high", m.AreaAt(Position{X: 1, Y: 1}).Name)
+	is.Equal("low", m.AreaAt(Position{X: 9, Y: 9}).Name)
+	is.Equal(2, len(m.RegionsAt(Position{X: 1, Y: 1})))
+	is.Nil(m.AreaAt(Position{X: 11, Y: 9}))
+
+	for _, doc := range []string{
+		`{"bounds": {"x_max": 10, "y_max": 10}, "regions": [{"name": "a", "layer": "area", "polygon": [[0, 0], [1, 1]]}]}`,
+		`{"bounds": {"x_max": 10, "y_max": 10}, "regions": [{"name": "a", "layer": "air", "polygon": [[0, 0], [1, 1], [0, 1]]}]}`,
+		`{"bounds": {"x_max": 10, "y_max": 10}, "regions": [{"name": "a", "layer": "area", "polygon": [[0, 0], [1, 1], [0, 1]]}, {"name": "a", "layer": "area", "polygon": [[0, 0], [1, 1], [0, 1]]}]}`,
+		`{"bounds": {}, "regions": []}`,
+		`{"regions": 1}`,
+	} {
+		_, err := LoadMap(strings.NewReader(doc))
+		is.Msg(doc).Err(err)
+	}
+}
+
+func TestAreaIDHeuristic(t *testing.T) {
+	is := is.New(t)
+
+	// AreaID (and the map's, on summoner's rift) keep the heuristic areas,
+	// which the polygons refine.
+	sr := SummonersRift()
+	refined := 0
+	for x := 0.0; x < SRXMax; x += 250 {
+		for y := 0.0; y < SRYMax; y += 250 {
+			p := Position{X: x, Y: y}
+			is.Equal(AreaID(p), sr.AreaID(p))
+			if area := sr.AreaAt(p); area != nil && area.Area != AreaID(p) {
+				refined++
+			}
+		}
+	}
+	is.True(refined > 0)
+}
+
+func BenchmarkAreaID(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		AreaID(Position{X: float64(i % 14000), Y: float64(i % 13000)})
+	}
+}
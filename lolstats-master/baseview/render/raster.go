@@ -0,0 +1,141 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"math"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// mask is an alpha mask of the pixels whose centers are inside a shape.
+type mask struct {
+	bounds image.Rectangle
+	inside func(x, y float64) bool
+}
+
+func (m *mask) ColorModel() color.Model { return color.AlphaModel }
+func (m *mask) Bounds() image.Rectangle { return m.bounds }
+func (m *mask) At(x, y int) color.Color {
+	if m.inside(float64(x)+0.5, float64(y)+0.5) {
+		return color.Opaque
+	}
+	return color.Transparent
+}
+
+// segmentDistance returns the distance from p to the segment from a to b.
+func segmentDistance(p, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	f := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		f = math.Max(0, math.Min(1, ((p[0]-a[0])*dx+(p[1]-a[1])*dy)/l))
+	}
+	return math.Hypot(p[0]-a[0]-f*dx, p[1]-a[1]-f*dy)
+}
+
+// bounds returns the pixels that the points, grown by margin, cover.
+func bounds(points [][2]float64, margin float64) image.Rectangle {
+	r := image.Rectangle{}
+	for _, p := range points {
+		r = r.Union(image.Rect(
+			int(math.Floor(p[0]-margin)), int(math.Floor(p[1]-margin)),
+			int(math.Ceil(p[0]+margin))+1, int(math.Ceil(p[1]+margin))+1))
+	}
+	return r
+}
+
+func fillColor(dst draw.Image, m *mask, c color.NRGBA) {
+	if c.A == 0 {
+		return
+	}
+	r := m.bounds.Intersect(dst.Bounds())
+	draw.DrawMask(dst, r, image.NewUniform(c), image.Point{}, m, r.Min, draw.Over)
+}
+
+// rasterize draws the shape onto the image.
+func rasterize(dst draw.Image, s shape) {
+	switch s.kind {
+	case circle:
+		c := s.points[0]
+		b := bounds(s.points, s.radius+s.width)
+		fillColor(dst, &mask{b, func(x, y float64) bool {
+			return math.Hypot(x-c[0], y-c[1]) <= s.radius
+		}}, s.fill)
+		fillColor(dst, &mask{b, func(x, y float64) bool {
+			return math.Abs(math.Hypot(x-c[0], y-c[1])-s.radius) <= s.width/2
+		}}, s.stroke)
+
+	case line:
+		fillColor(dst, &mask{bounds(s.points, s.width), func(x, y float64) bool {
+			return segmentDistance([2]float64{x, y}, s.points[0], s.points[1]) <= s.width/2
+		}}, s.stroke)
+
+	case polygon:
+		poly := baseview.Polygon(s.points)
+		b := bounds(s.points, s.width)
+		fillColor(dst, &mask{b, func(x, y float64) bool {
+			return poly.Contains(baseview.Position{X: x, Y: y})
+		}}, s.fill)
+		fillColor(dst, &mask{b, func(x, y float64) bool {
+			for i, j := 0, len(s.points)-1; i < len(s.points); j, i = i, i+1 {
+				if segmentDistance([2]float64{x, y}, s.points[j], s.points[i]) <= s.width/2 {
+					return true
+				}
+			}
+			return false
+		}}, s.stroke)
+	}
+}
+
+// backgroundImage returns the background of every frame: the Background of
+// the options scaled to the frame, or else the regions of the map.
+func (r *Renderer) backgroundImage() *image.RGBA {
+	size := r.opts.Size
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	if bg := r.opts.Background; bg != nil {
+		b := bg.Bounds()
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				img.Set(x, y, bg.At(b.Min.X+x*b.Dx()/size, b.Min.Y+y*b.Dy()/size))
+			}
+		}
+		return img
+	}
+	draw.Draw(img, img.Bounds(), image.Black, image.Point{}, draw.Src)
+	for _, s := range r.background() {
+		rasterize(img, s)
+	}
+	return img
+}
+
+// Frame draws the match at t.
+func (r *Renderer) Frame(t float64) *image.RGBA {
+	return r.frame(r.backgroundImage(), t)
+}
+
+func (r *Renderer) frame(background *image.RGBA, t float64) *image.RGBA {
+	img := image.NewRGBA(background.Bounds())
+	draw.Draw(img, img.Bounds(), background, image.Point{}, draw.Src)
+	for _, s := range r.scene(t) {
+		rasterize(img, s)
+	}
+	return img
+}
+
+// GIF writes the frames of the window as an animated GIF.
+func (r *Renderer) GIF(w io.Writer) error {
+	background := r.backgroundImage()
+	anim := &gif.GIF{}
+	for _, t := range r.Times() {
+		img := r.frame(background, t)
+		p := image.NewPaletted(img.Bounds(), palette.Plan9)
+		draw.Draw(p, p.Bounds(), img, image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, p)
+		anim.Delay = append(anim.Delay, r.opts.Delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
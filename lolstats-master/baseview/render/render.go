@@ -0,0 +1,339 @@
+// Package render draws baseviews on the minimap: participants, wards, turrets
+// and recent events, optionally with overlays of what the analyzers in ingest
+// found, like team fights and ward coverage. Frames are animated GIFs or SVG
+// documents, so a fight can be watched instead of read from json.
+
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// Options configure a Renderer.
+type Options struct {
+	// Size is the width and height of the frames, in pixels.
+	Size int
+	// From and To are the window of the match to draw, in seconds. To of 0
+	// is the end of the match.
+	From, To float64
+	// Step is the seconds of the match between frames.
+	Step float64
+	// Delay is the time between frames of GIFs, in 100ths of a second.
+	Delay int
+	// Background is drawn (scaled) under everything instead of the regions
+	// of the map, e.g. a screenshot of the minimap.
+	Background image.Image
+
+	Fights    []Fight
+	Coverages []Coverage
+}
+
+// DefaultOptions draws every second of a match at 512 pixels, 10 times as
+// fast as it was played.
+func DefaultOptions() Options {
+	return Options{Size: 512, Step: 1, Delay: 10}
+}
+
+// Fight is a span of the match to mark around the participants in it, like a
+// team fight from ingest.
+type Fight struct {
+	Begin, End     float64
+	ParticipantIDs []int64
+}
+
+// Coverage is an area with vision, like that of a ward from ingest.
+type Coverage struct {
+	Begin, End float64
+	Position   baseview.Position
+	Radius     float64
+	TeamID     int64
+}
+
+var (
+	blue    = color.NRGBA{66, 133, 244, 255}
+	red     = color.NRGBA{219, 68, 55, 255}
+	neutral = color.NRGBA{160, 160, 160, 255}
+	white   = color.NRGBA{255, 255, 255, 255}
+	yellow  = color.NRGBA{255, 210, 0, 255}
+
+	kindColors = map[string]color.NRGBA{
+		baseview.KindBase:     {62, 60, 72, 255},
+		baseview.KindLane:     {112, 102, 82, 255},
+		baseview.KindJungle:   {42, 72, 44, 255},
+		baseview.KindRiver:    {42, 72, 112, 255},
+		baseview.KindBrush:    {30, 96, 36, 255},
+		baseview.KindPit:      {72, 52, 92, 255},
+		baseview.KindFountain: {92, 92, 112, 255},
+	}
+)
+
+const (
+	// recent is how many seconds events (like attacks) stay on the map.
+	recent = 2.0
+
+	heroRadius    = 7.0
+	wardRadius    = 3.0
+	turretRadius  = 4.0
+	fightMargin   = 12.0
+	progressWidth = 4.0
+)
+
+func teamColor(teamID int64) color.NRGBA {
+	switch teamID {
+	case baseview.BlueTeam:
+		return blue
+	case baseview.RedTeam:
+		return red
+	}
+	return neutral
+}
+
+func translucent(c color.NRGBA, alpha uint8) color.NRGBA {
+	c.A = alpha
+	return c
+}
+
+type shapeKind int
+
+const (
+	circle shapeKind = iota
+	line
+	polygon
+)
+
+// shape is something to draw, in pixels. Circles have one point (their
+// center), lines two, and polygons any number. Colors with no alpha aren't
+// drawn.
+type shape struct {
+	kind   shapeKind
+	points [][2]float64
+	radius float64
+	fill   color.NRGBA
+	stroke color.NRGBA
+	width  float64
+	label  string
+}
+
+// Renderer draws frames of a baseview.
+type Renderer struct {
+	bv       *baseview.Baseview
+	index    *baseview.Index
+	geometry *baseview.Map
+	opts     Options
+
+	teams map[int64]int64
+	// ward ids by placement, and the time each died (or +Inf).
+	wards     []*baseview.WardPlaced
+	wardDeath map[int64]float64
+	// the time each building died, by id.
+	buildingDeath map[int64]float64
+}
+
+// New returns a renderer of the baseview, which must be on a map that baseview
+// has the geometry of.
+func New(bv *baseview.Baseview, opts Options) (*Renderer, error) {
+	geometry := baseview.MapByID(bv.MapID)
+	if geometry == nil {
+		return nil, fmt.Errorf("unsupported map: %d", bv.MapID)
+	}
+	if opts.Size <= 0 || opts.Step <= 0 {
+		return nil, fmt.Errorf("size and step must be positive")
+	}
+	if opts.To == 0 && len(bv.Events) > 0 {
+		opts.To = bv.Events[len(bv.Events)-1].Seconds()
+	}
+	if opts.To < opts.From {
+		return nil, fmt.Errorf("window ends (%.1fs) before it begins (%.1fs)", opts.To, opts.From)
+	}
+
+	r := &Renderer{
+		bv:            bv,
+		index:         baseview.NewIndex(bv),
+		geometry:      geometry,
+		opts:          opts,
+		teams:         map[int64]int64{},
+		wardDeath:     map[int64]float64{},
+		buildingDeath: map[int64]float64{},
+	}
+	for _, id := range r.index.Participants() {
+		r.teams[id] = baseview.TeamID(id)
+	}
+	for _, e := range bv.Events {
+		switch t := e.(type) {
+		case *baseview.WardPlaced:
+			r.wards = append(r.wards, t)
+			r.wardDeath[t.WardID] = math.Inf(1)
+		case *baseview.WardDeath:
+			r.wardDeath[t.WardID] = t.Seconds()
+		case *baseview.BuildingKill:
+			r.buildingDeath[t.BuildingID] = t.Seconds()
+		}
+	}
+	return r, nil
+}
+
+// Times returns the time of each frame.
+func (r *Renderer) Times() []float64 {
+	times := []float64{}
+	for t := r.opts.From; t <= r.opts.To; t += r.opts.Step {
+		times = append(times, t)
+	}
+	return times
+}
+
+// pixel returns the position on the frame of a position on the map. The y
+// axis of the map points up.
+func (r *Renderer) pixel(p baseview.Position) [2]float64 {
+	b := r.geometry.Bounds
+	size := float64(r.opts.Size)
+	return [2]float64{
+		(p.X - b.XMin) / (b.XMax - b.XMin) * size,
+		size - (p.Y-b.YMin)/(b.YMax-b.YMin)*size,
+	}
+}
+
+// scale returns the number of pixels a distance on the map covers.
+func (r *Renderer) scale(distance float64) float64 {
+	return distance / (r.geometry.Bounds.XMax - r.geometry.Bounds.XMin) * float64(r.opts.Size)
+}
+
+// background returns the regions of the map, areas first.
+func (r *Renderer) background() []shape {
+	shapes := []shape{}
+	for _, layer := range []string{baseview.LayerArea, baseview.LayerFeature} {
+		for _, region := range r.geometry.Regions {
+			if region.Layer != layer {
+				continue
+			}
+			s := shape{kind: polygon, fill: kindColors[region.Kind], label: region.Name}
+			if s.fill.A == 0 {
+				s.fill = color.NRGBA{30, 30, 30, 255}
+			}
+			for _, v := range region.Polygon {
+				s.points = append(s.points, r.pixel(baseview.Position{X: v[0], Y: v[1]}))
+			}
+			shapes = append(shapes, s)
+		}
+	}
+	return shapes
+}
+
+// scene returns what to draw over the background at t, from the bottom up.
+func (r *Renderer) scene(t float64) []shape {
+	shapes := []shape{}
+
+	for _, c := range r.opts.Coverages {
+		if c.Begin <= t && t <= c.End {
+			shapes = append(shapes, shape{kind: circle, points: [][2]float64{r.pixel(c.Position)}, radius: r.scale(c.Radius), fill: translucent(teamColor(c.TeamID), 60)})
+		}
+	}
+
+	for id, p := range r.geometry.Turrets {
+		if died, found := r.buildingDeath[id]; found && died <= t {
+			continue
+		}
+		shapes = append(shapes, shape{kind: circle, points: [][2]float64{r.pixel(p)}, radius: turretRadius, fill: teamColor(id / 100 * 100), stroke: white, width: 1})
+	}
+
+	for _, w := range r.wards {
+		if w.Seconds() > t || r.wardDeath[w.WardID] <= t {
+			continue
+		}
+		team := w.TeamID
+		if team == 0 {
+			team = r.teams[w.ParticipantID]
+		}
+		c := r.pixel(w.Position)
+		shapes = append(shapes, shape{kind: polygon, points: [][2]float64{
+			{c[0], c[1] - wardRadius}, {c[0] + wardRadius, c[1]}, {c[0], c[1] + wardRadius}, {c[0] - wardRadius, c[1]},
+		}, fill: teamColor(team)})
+	}
+
+	for _, e := range r.index.EventsBetween(t-recent, t, "attack", "death") {
+		switch e := e.(type) {
+		case *baseview.Attack:
+			if e.AttackerType != baseview.ActorHero || e.TargetType != baseview.ActorHero {
+				continue
+			}
+			from, ok1 := r.index.StateAt(e.AttackerID, t)
+			to, ok2 := r.index.StateAt(e.TargetID, t)
+			if ok1 && ok2 {
+				shapes = append(shapes, shape{kind: line, points: [][2]float64{r.pixel(from.Position), r.pixel(to.Position)}, stroke: translucent(teamColor(r.teams[e.AttackerID]), 160), width: 1.5})
+			}
+		case *baseview.Death:
+			c := r.pixel(e.Position)
+			d := heroRadius
+			for _, sign := range []float64{1, -1} {
+				shapes = append(shapes, shape{kind: line, points: [][2]float64{{c[0] - d, c[1] - sign*d}, {c[0] + d, c[1] + sign*d}}, stroke: white, width: 2})
+			}
+		}
+	}
+
+	for _, id := range r.index.Participants() {
+		state, ok := r.index.StateAt(id, t)
+		if !ok {
+			continue
+		}
+		s := shape{kind: circle, points: [][2]float64{r.pixel(state.Position)}, radius: heroRadius, label: r.label(id)}
+		if r.index.AliveAt(id, t) {
+			s.fill, s.stroke, s.width = teamColor(r.teams[id]), white, 1
+		} else {
+			s.stroke, s.width = translucent(teamColor(r.teams[id]), 160), 1.5
+		}
+		shapes = append(shapes, s)
+	}
+
+	for _, f := range r.opts.Fights {
+		if f.Begin <= t && t <= f.End {
+			if s, ok := r.fight(f, t); ok {
+				shapes = append(shapes, s)
+			}
+		}
+	}
+
+	// a bar along the bottom shows how far through the window the frame is.
+	if r.opts.To > r.opts.From {
+		size := float64(r.opts.Size)
+		done := (t - r.opts.From) / (r.opts.To - r.opts.From) * size
+		shapes = append(shapes, shape{kind: line, points: [][2]float64{{0, size - progressWidth/2}, {done, size - progressWidth/2}}, stroke: white, width: progressWidth})
+	}
+	return shapes
+}
+
+// fight returns a circle around the participants of the fight.
+func (r *Renderer) fight(f Fight, t float64) (shape, bool) {
+	points := [][2]float64{}
+	for _, id := range f.ParticipantIDs {
+		if state, ok := r.index.StateAt(id, t); ok {
+			points = append(points, r.pixel(state.Position))
+		}
+	}
+	if len(points) == 0 {
+		return shape{}, false
+	}
+	var center [2]float64
+	for _, p := range points {
+		center[0] += p[0] / float64(len(points))
+		center[1] += p[1] / float64(len(points))
+	}
+	radius := 0.0
+	for _, p := range points {
+		radius = math.Max(radius, math.Hypot(p[0]-center[0], p[1]-center[1]))
+	}
+	return shape{kind: circle, points: [][2]float64{center}, radius: radius + fightMargin, stroke: yellow, width: 2}, true
+}
+
+// label returns the champion id (or else participant id) of the participant.
+func (r *Renderer) label(participantID int64) string {
+	for _, p := range r.bv.Participants {
+		if p.ParticipantID == participantID && p.ChampionID != 0 {
+			return fmt.Sprint(p.ChampionID)
+		}
+	}
+	return fmt.Sprint(participantID)
+}
@@ -0,0 +1,152 @@
+package render
+
+import (
+	"bytes"
+	"image/gif"
+	"strings"
+	"testing"
+
+	"github.com/VantageSports/lolstats/baseview"
+
+	"gopkg.in/tylerb/is.v1"
+)
+
+func event(e baseview.Event, eventType string, seconds float64) baseview.Event {
+	e.SetType(eventType)
+	e.SetSeconds(seconds)
+	return e
+}
+
+// testBaseview returns ten seconds of participant 1 walking up the blue side
+// of mid lane, and participant 6 standing in the river until they die.
+func testBaseview() *baseview.Baseview {
+	bv := &baseview.Baseview{Version: baseview.CurrentVersion, MapID: baseview.MapSummonersRift}
+	for _, id := range []int64{1, 6} {
+		bv.Participants = append(bv.Participants, baseview.Participant{ParticipantID: id, ChampionID: 100 + id})
+	}
+	for second := 0.0; second <= 10; second += 0.5 {
+		bv.Events = append(bv.Events,
+			event(&baseview.StateUpdate{ParticipantID: 1, Health: 500, Position: baseview.Position{X: 5000 + 100*second, Y: 5000 + 100*second}}, "state_update", second))
+		if second < 6 {
+			bv.Events = append(bv.Events,
+				event(&baseview.StateUpdate{ParticipantID: 6, Health: 500, Position: baseview.Position{X: 9000, Y: 5000}}, "state_update", second))
+		}
+		switch second {
+		case 2:
+			bv.Events = append(bv.Events, event(&baseview.WardPlaced{ParticipantID: 1, WardID: 1, TeamID: baseview.BlueTeam, Position: baseview.Position{X: 6000, Y: 7000}}, "ward_placed", second))
+		case 5:
+			bv.Events = append(bv.Events,
+				event(&baseview.Attack{AttackerID: 1, AttackerType: baseview.ActorHero, TargetID: 6, TargetType: baseview.ActorHero}, "attack", second),
+				event(&baseview.BuildingKill{BuildingID: baseview.TurretRedMidOuter, BuildingType: baseview.ActorTurret}, "building_kill", second))
+		case 6:
+			bv.Events = append(bv.Events, event(&baseview.Death{VictimID: 6, Position: baseview.Position{X: 9000, Y: 5000}}, "death", second))
+		case 8:
+			bv.Events = append(bv.Events, event(&baseview.WardDeath{WardID: 1}, "ward_death", second))
+		}
+	}
+	return bv
+}
+
+func count(shapes []shape, kind shapeKind) int {
+	n := 0
+	for _, s := range shapes {
+		if s.kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func TestScene(t *testing.T) {
+	is := is.New(t)
+
+	opts := DefaultOptions()
+	opts.Fights = []Fight{{Begin: 4, End: 7, ParticipantIDs: []int64{1, 6}}}
+	opts.Coverages = []Coverage{{Begin: 2, End: 8, Position: baseview.Position{X: 6000, Y: 7000}, Radius: 1100, TeamID: baseview.BlueTeam}}
+	r, err := New(testBaseview(), opts)
+	is.NotErr(err)
+	is.Equal(11, len(r.Times()))
+	turrets := len(r.geometry.Turrets)
+
+	// the turrets, both heroes and the progress bar.
+	shapes := r.scene(1)
+	is.Equal(turrets+3, count(shapes, circle)+count(shapes, line))
+	is.Equal(0, count(shapes, polygon))
+
+	// the coverage, the ward, the attack and the fight.
+	shapes = r.scene(5)
+	is.Equal(turrets-1+1+2+1, count(shapes, circle))
+	is.Equal(2, count(shapes, line))
+	is.Equal(1, count(shapes, polygon))
+	for _, s := range shapes {
+		if s.label == "101" {
+			is.Equal(blue, s.fill)
+			is.Equal(r.pixel(baseview.Position{X: 5500, Y: 5500}), s.points[0])
+		}
+	}
+
+	// participant 6 died, so isn't filled in, and there is a cross where they
+	// died.
+	shapes = r.scene(7)
+	for _, s := range shapes {
+		if s.label == "106" {
+			is.Equal(uint8(0), s.fill.A)
+		}
+	}
+	is.Equal(3+1, count(shapes, line))
+
+	// the ward is gone.
+	shapes = r.scene(9)
+	is.Equal(0, count(shapes, polygon))
+}
+
+func TestGIF(t *testing.T) {
+	is := is.New(t)
+
+	opts := DefaultOptions()
+	opts.Size = 128
+	opts.From, opts.To, opts.Step = 2, 6, 2
+	r, err := New(testBaseview(), opts)
+	is.NotErr(err)
+
+	buf := &bytes.Buffer{}
+	is.NotErr(r.GIF(buf))
+	anim, err := gif.DecodeAll(buf)
+	is.NotErr(err)
+	is.Equal(3, len(anim.Image))
+	is.Equal(128, anim.Image[0].Bounds().Dx())
+
+	// participant 1 is drawn blue.
+	p := r.pixel(baseview.Position{X: 5200, Y: 5200})
+	red, green, blue, _ := anim.Image[0].At(int(p[0]), int(p[1])).RGBA()
+	is.True(blue > red && blue > green)
+}
+
+func TestSVG(t *testing.T) {
+	is := is.New(t)
+
+	r, err := New(testBaseview(), DefaultOptions())
+	is.NotErr(err)
+	buf := &bytes.Buffer{}
+	is.NotErr(r.SVG(buf, 65))
+	svg := buf.String()
+	is.True(strings.HasPrefix(svg, "<svg"))
+	is.True(strings.Contains(svg, "<title>baron_pit</title>"))
+	is.True(strings.Contains(svg, ">101</text>"))
+	is.True(strings.Contains(svg, ">1:05</text>"))
+	is.True(strings.HasSuffix(svg, "</svg>\n"))
+}
+
+func TestNewErrors(t *testing.T) {
+	is := is.New(t)
+
+	bv := testBaseview()
+	bv.MapID = 10
+	_, err := New(bv, DefaultOptions())
+	is.Err(err)
+
+	opts := DefaultOptions()
+	opts.From = 20
+	_, err = New(testBaseview(), opts)
+	is.Err(err)
+}
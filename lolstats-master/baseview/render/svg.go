@@ -0,0 +1,74 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+func svgColor(c color.NRGBA) string {
+	if c.A == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("rgba(%d,%d,%d,%.3f)", c.R, c.G, c.B, float64(c.A)/255)
+}
+
+func svgShape(w io.Writer, s shape) {
+	paint := fmt.Sprintf(`fill="%s" stroke="%s" stroke-width="%g"`, svgColor(s.fill), svgColor(s.stroke), s.width)
+	switch s.kind {
+	case circle:
+		fmt.Fprintf(w, `<circle cx="%.1f" cy="%.1f" r="%.1f" %s>`, s.points[0][0], s.points[0][1], s.radius, paint)
+	case line:
+		fmt.Fprintf(w, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" %s>`, s.points[0][0], s.points[0][1], s.points[1][0], s.points[1][1], paint)
+	case polygon:
+		points := []string{}
+		for _, p := range s.points {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", p[0], p[1]))
+		}
+		fmt.Fprintf(w, `<polygon points="%s" %s>`, strings.Join(points, " "), paint)
+	}
+	// labels are tooltips, and for heroes also drawn on top.
+	if s.label != "" {
+		fmt.Fprintf(w, `<title>%s</title>`, html.EscapeString(s.label))
+	}
+	fmt.Fprintf(w, "</%s>\n", [...]string{"circle", "line", "polygon"}[s.kind])
+	if s.label != "" && s.kind == circle {
+		fmt.Fprintf(w, `<text x="%.1f" y="%.1f" font-size="%.0f" text-anchor="middle" dominant-baseline="central" fill="white">%s</text>`+"\n",
+			s.points[0][0], s.points[0][1], s.radius, html.EscapeString(s.label))
+	}
+}
+
+// SVG writes the match at t as an SVG document.
+func (r *Renderer) SVG(w io.Writer, t float64) error {
+	bw := bufio.NewWriter(w)
+	size := r.opts.Size
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", size, size, size, size)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="black"/>`+"\n", size, size)
+
+	if r.opts.Background != nil {
+		buf := &bytes.Buffer{}
+		if err := png.Encode(buf, r.opts.Background); err != nil {
+			return err
+		}
+		fmt.Fprintf(bw, `<image width="%d" height="%d" preserveAspectRatio="none" href="data:image/png;base64,%s"/>`+"\n",
+			size, size, base64.StdEncoding.EncodeToString(buf.Bytes()))
+	} else {
+		for _, s := range r.background() {
+			svgShape(bw, s)
+		}
+	}
+	for _, s := range r.scene(t) {
+		svgShape(bw, s)
+	}
+
+	minutes, seconds := int(t)/60, int(t)%60
+	fmt.Fprintf(bw, `<text x="6" y="18" font-size="14" fill="white">%d:%02d</text>`+"\n", minutes, seconds)
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
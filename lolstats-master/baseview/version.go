@@ -0,0 +1,180 @@
+package baseview
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CurrentVersion is the version of the baseview schema defined by this
+// package. Documents written before baseviews were versioned (i.e. without a
+// version, or with version 0) are version 1.
+//
+// Whenever a change to the schema would make older documents decode
+// incorrectly, increment CurrentVersion and register a migration from the
+// previous version.
+const CurrentVersion = 3
+
+const legacyVersion = 1
+
+// sources of baseviews.
+const (
+	SourceElo    = "elo"
+	SourceOCR    = "ocr"
+	SourceManual = "manual"
+	// SourceMerged baseviews combine others, and tag each event with the
+	// source it came from.
+	SourceMerged = "merged"
+)
+
+// Producer describes what produced a baseview.
+type Producer struct {
+	// Source is how the data was collected, e.g. SourceElo.
+	Source string `json:"source"`
+	// ConverterVersion is the version of the code that produced the baseview.
+	ConverterVersion string `json:"converter_version,omitempty"`
+	// GamePatch is the patch the match was played on, e.g. "7.1.165.3566".
+	GamePatch string `json:"game_patch,omitempty"`
+}
+
+// A Migration upgrades a json document from one version of the schema to the
+// next. The top level fields of the document are left encoded, so that a
+// migration only decodes (and re-encodes) the fields it changes.
+type Migration func(doc map[string]json.RawMessage) error
+
+// migrations are keyed by the version they upgrade from.
+var migrations = map[int]Migration{}
+
+func registerMigration(from int, m Migration) {
+	if _, ok := migrations[from]; ok {
+		panic(fmt.Sprintf("migration from baseview version %d registered twice", from))
+	}
+	migrations[from] = m
+}
+
+func init() {
+	// version 2 only added the version and producer, which are left unset.
+	registerMigration(1, func(doc map[string]json.RawMessage) error { return nil })
+
+	// version 3 added the map id. Every earlier baseview was recorded on
+	// summoner's rift.
+	registerMigration(2, func(doc map[string]json.RawMessage) error {
+		mapID := int64(0)
+		if raw, ok := doc["map_id"]; ok {
+			if err := json.Unmarshal(raw, &mapID); err != nil {
+				return err
+			}
+		}
+		if mapID == 0 {
+			doc["map_id"] = json.RawMessage(fmt.Sprint(MapSummonersRift))
+		}
+		return nil
+	})
+}
+
+// migrate upgrades the document from one version to another.
+func migrate(doc map[string]json.RawMessage, from, to int) error {
+	for v := from; v < to; v++ {
+		m, ok := migrations[v]
+		if !ok {
+			return fmt.Errorf("no migration from baseview version %d", v)
+		}
+		if err := m(doc); err != nil {
+			return fmt.Errorf("error migrating baseview from version %d: %v", v, err)
+		}
+	}
+	return nil
+}
+
+// unmarshalDocument decodes a json document of any version into b. If strict,
+// documents newer than CurrentVersion are rejected, otherwise their unknown
+// fields and events are dropped. Either way, b conforms to CurrentVersion.
+func unmarshalDocument(data []byte, b *Baseview, strict bool) error {
+	doc := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	version := 0
+	if raw, ok := doc["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return fmt.Errorf("invalid baseview version: %v", err)
+		}
+	}
+	if version < legacyVersion {
+		version = legacyVersion
+	}
+	if err := checkVersion(version, strict); err != nil {
+		return err
+	}
+	if err := migrate(doc, version, CurrentVersion); err != nil {
+		return err
+	}
+
+	in := struct {
+		Producer     *Producer         `json:"producer"`
+		MapID        int64             `json:"map_id"`
+		Updated      time.Time         `json:"last_updated"`
+		Participants []Participant     `json:"participants"`
+		Events       []json.RawMessage `json:"events"`
+	}{}
+	fields := map[string]interface{}{
+		"producer":     &in.Producer,
+		"map_id":       &in.MapID,
+		"last_updated": &in.Updated,
+		"participants": &in.Participants,
+		"events":       &in.Events,
+	}
+	for name, v := range fields {
+		if raw, ok := doc[name]; ok {
+			if err := json.Unmarshal(raw, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	events := []Event{}
+	for _, msg := range in.Events {
+		event, err := parseEvent(msg)
+		if _, unknown := err.(*unknownEventError); unknown && version > CurrentVersion {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+	b.Version = CurrentVersion
+	b.Producer = in.Producer
+	b.MapID = in.MapID
+	b.LastUpdated = in.Updated
+	b.Participants = in.Participants
+	b.Events = events
+	return nil
+}
+
+// upgrade brings a decoded baseview up to CurrentVersion. Older baseviews are
+// migrated through their json encoding.
+func (b *Baseview) upgrade(strict bool) error {
+	if b.Version < legacyVersion {
+		b.Version = legacyVersion
+	}
+	if err := checkVersion(b.Version, strict); err != nil {
+		return err
+	}
+	if b.Version >= CurrentVersion {
+		b.Version = CurrentVersion
+		return nil
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	return unmarshalDocument(data, b, strict)
+}
+
+func checkVersion(version int, strict bool) error {
+	if strict && version > CurrentVersion {
+		return fmt.Errorf("baseview version %d is newer than supported version %d", version, CurrentVersion)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package baseview
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/tylerb/is.v1"
+)
+
+func TestReadVersions(t *testing.T) {
+	is := is.New(t)
+
+	// documents written before baseviews were versioned are upgraded.
+	legacy := `{"last_updated":"2017-01-01T00:00:00Z","participants":[{"participant_id":1}],
+		"events":[{"event":"death","seconds":10,"victim_id":1}]}`
+	bv, err := Read(bytes.NewReader([]byte(legacy)), Strict())
+	is.NotErr(err)
+	is.Equal(CurrentVersion, bv.Version)
+	is.Nil(bv.Producer)
+	is.Equal(MapSummonersRift, bv.MapID)
+	is.Equal(1, len(bv.Events))
+
+	current := `{"version":2,"producer":{"source":"elo","converter_version":"v2","game_patch":"7.1"},
+		"events":[{"event":"death","seconds":10,"victim_id":1}]}`
+	bv, err = Read(bytes.NewReader([]byte(current)), Strict())
+	is.NotErr(err)
+	is.Equal(&Producer{Source: SourceElo, ConverterVersion: "v2", GamePatch: "7.1"}, bv.Producer)
+	is.Equal(MapSummonersRift, bv.MapID)
+
+	aram := `{"version":3,"map_id":12,"events":[]}`
+	bv, err = Read(bytes.NewReader([]byte(aram)), Strict())
+	is.NotErr(err)
+	is.Equal(MapHowlingAbyss, bv.MapID)
+
+	// future documents are read as far as possible, unless strict.
+	future := `{"version":99,"shiny":true,"events":[{"event":"death","seconds":10,"victim_id":1},
+		{"event":"teleport","seconds":11}]}`
+	_, err = Read(bytes.NewReader([]byte(future)), Strict())
+	is.Err(err)
+	bv, err = Read(bytes.NewReader([]byte(future)))
+	is.NotErr(err)
+	is.Equal(CurrentVersion, bv.Version)
+	is.Equal(1, len(bv.Events))
+
+	// unknown events in documents that aren't from the future are errors.
+	unknown := `{"version":2,"events":[{"event":"teleport","seconds":11}]}`
+	_, err = Read(bytes.NewReader([]byte(unknown)))
+	is.Err(err)
+
+	// the future binary encoding of a version is rejected when strict.
+	data, err := (&Baseview{Version: CurrentVersion + 1}).MarshalBinary()
+	is.NotErr(err)
+	_, err = Read(bytes.NewReader(data), Strict())
+	is.Err(err)
+	bv, err = Read(bytes.NewReader(data))
+	is.NotErr(err)
+	is.Equal(CurrentVersion, bv.Version)
+}
+
+func TestMigrate(t *testing.T) {
+	is := is.New(t)
+
+	registerMigration(CurrentVersion, func(doc map[string]json.RawMessage) error {
+		doc["updated"] = doc["last_updated"]
+		delete(doc, "last_updated")
+		return nil
+	})
+	defer delete(migrations, CurrentVersion)
+
+	doc := map[string]json.RawMessage{"last_updated": json.RawMessage(`"2017-01-01T00:00:00Z"`)}
+	is.NotErr(migrate(doc, legacyVersion, CurrentVersion+1))
+	is.Equal(`"2017-01-01T00:00:00Z"`, string(doc["updated"]))
+	is.Nil(doc["last_updated"])
+
+	// there is no migration past the next version.
+	is.Err(migrate(doc, legacyVersion, CurrentVersion+2))
+}
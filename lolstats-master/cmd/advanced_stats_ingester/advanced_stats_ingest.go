@@ -6,7 +6,6 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"cloud.google.com/go/pubsub"
@@ -112,19 +111,21 @@ func (i *Ingester) Handle(ctx context.Context, m *pubsub.Message) error {
 	if err := i.fc.Copy(msg.BaseviewPath, baseviewLocal); err != nil {
 		return err
 	}
-	bv := baseview.Baseview{}
-	if err := vsjson.DecodeFile(baseviewLocal, &bv); err != nil {
+	bv, err := baseview.ReadFile(baseviewLocal)
+	if err != nil {
 		return err
 	}
 
-	stats, err := ingest.ComputeAdvanced(bv, msg.SummonerId, msg.MatchId, platform)
+	stats, err := ingest.ComputeAdvanced(*bv, msg.SummonerId, msg.MatchId, platform)
 	if err != nil {
 		// Errors in role position calculation represent outliers where players don't stick to the meta.
-		// Since we want to have stats to help people play in the meta, these games don't make sense to include
-		if strings.HasPrefix(err.Error(), "error in role position calculation") {
+		// Since we want to have stats to help people play in the meta, these games don't make sense to include.
+		// Nor do games on unsupported maps, which would fail again on redelivery.
+		switch err.(type) {
+		case *ingest.RolePositionError, *ingest.UnsupportedMapError:
 			log.Error(err.Error())
 			return nil
-		} else {
+		default:
 			return err
 		}
 	}
@@ -132,6 +133,9 @@ func (i *Ingester) Handle(ctx context.Context, m *pubsub.Message) error {
 	if err := i.zipAndUpload(stats, remotePath, workDir, statsFilename); err != nil {
 		return err
 	}
+	if err := i.uploadHeatmaps(bv, stats, workDir); err != nil {
+		return err
+	}
 
 	bqRemotePath := fmt.Sprintf("%s/%s", i.bqOutputDir, statsFilename)
 
@@ -148,7 +152,26 @@ func (i *Ingester) Handle(ctx context.Context, m *pubsub.Message) error {
 	return ctx.Err()
 }
 
-func (i *Ingester) zipAndUpload(stats *ingest.AdvancedStats, remotePath string, workDir string, statsFilename string) error {
+// uploadHeatmaps uploads the summoner's heatmaps of the match, which the stats
+// server merges across matches. Matches on maps without geometry have none.
+func (i *Ingester) uploadHeatmaps(bv *baseview.Baseview, stats *ingest.AdvancedStats, workDir string) error {
+	if baseview.MapByID(bv.MapID) == nil {
+		log.Info(fmt.Sprintf("no heatmaps for map %d", bv.MapID))
+		return nil
+	}
+	heatmaps, err := ingest.NewHeatmaps(stats.SummonerID, bv.MapID, ingest.HeatmapGridSize)
+	if err != nil {
+		return err
+	}
+	if err := heatmaps.Add(bv, stats.RolePosition); err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("%d-%v-%d.heatmap.json", stats.MatchID, stats.PlatformID, stats.SummonerID)
+	return i.zipAndUpload(heatmaps, fmt.Sprintf("%s/%s", i.outputDir, filename), workDir, filename)
+}
+
+func (i *Ingester) zipAndUpload(stats interface{}, remotePath string, workDir string, statsFilename string) error {
 
 	localPath := filepath.Join(workDir, statsFilename)
 	if err := vsjson.Write(localPath, stats, 0664); err != nil {
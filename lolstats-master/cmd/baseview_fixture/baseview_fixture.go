@@ -0,0 +1,155 @@
+// The baseview_fixture command cuts a baseview down to a small, anonymous
+// fixture for ingest test data. With -minimize, it removes as much of the
+// baseview as it can while the advanced stats of the summoner (or just the
+// -field of them) stay the same, and writes them next to the fixture.
+//
+//   baseview_fixture -bv match.baseview.json -out fixture.baseview.json \
+//     -s 123 -from 600 -to 900 -minimize -field team_fights
+
+package main
+
+import (
+	"bufio"
+	encjson "encoding/json"
+	"flag"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/VantageSports/common/json"
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/lolstats/baseview/fixture"
+	"github.com/VantageSports/lolstats/ingest"
+	"github.com/VantageSports/riot"
+)
+
+var (
+	baseviewFile = flag.String("bv", "", "path to the baseview to cut down")
+	outFile      = flag.String("out", "", "path to write the fixture to")
+	binary       = flag.Bool("binary", false, "if true, write the binary encoding instead of json")
+	from         = flag.Float64("from", 0, "seconds into the match to start the fixture at")
+	to           = flag.Float64("to", 0, "seconds into the match to end the fixture at (0 for the end)")
+	participants = flag.String("participants", "", "comma separated participant ids to keep (default all)")
+	anonymize    = flag.Bool("anonymize", true, "if true, replace summoner names and ids")
+	salt         = flag.String("salt", "", "if set, hash summoner names and ids with it instead of removing them")
+	downsample   = flag.Float64("downsample", 0, "if set, seconds between the state updates of each participant")
+	minimize     = flag.Bool("minimize", false, "if true, minimize the fixture while the advanced stats stay the same")
+	summonerID   = flag.Int64("s", 0, "summoner id to compute advanced stats for (before anonymizing)")
+	field        = flag.String("field", "", "the advanced stats field that has to stay the same (default all of them)")
+	maxTests     = flag.Int("max-tests", 2000, "the most times to compute advanced stats while minimizing")
+	statsFile    = flag.String("stats", "", "path to write the advanced stats of the fixture to (default next to it)")
+)
+
+func main() {
+	flag.Parse()
+	if *baseviewFile == "" || *outFile == "" {
+		log.Fatalln("usage: baseview_fixture -bv file -out file [flags]")
+	}
+
+	original, err := baseview.ReadFile(*baseviewFile)
+	exitIf(err)
+	bv, err := original.Clone()
+	exitIf(err)
+
+	end := *to
+	if end == 0 {
+		end = math.Inf(1)
+	}
+	bv = fixture.Slice(bv, *from, end)
+	if *participants != "" {
+		ids := []int64{}
+		for _, s := range strings.Split(*participants, ",") {
+			id, err := strconv.ParseInt(s, 10, 64)
+			exitIf(err)
+			ids = append(ids, id)
+		}
+		bv = fixture.KeepParticipants(bv, ids)
+	}
+	fixture.Renumber(bv)
+	summoner := *summonerID
+	if *anonymize {
+		summoner = fixture.Anonymize(bv, *salt)[summoner]
+	}
+	if *downsample > 0 {
+		bv = fixture.Downsample(bv, *downsample)
+	}
+
+	if *minimize {
+		if *summonerID == 0 {
+			log.Fatalln("-minimize needs the summoner id (-s)")
+		}
+		want := output(bv, summoner)
+		if output(original, *summonerID) != want {
+			log.Println("warning: the fixture doesn't reproduce the advanced stats of the baseview, minimizing what it does produce")
+		}
+		before := len(bv.Events)
+		bv, err = fixture.Minimize(bv, func(c *baseview.Baseview) bool {
+			return output(c, summoner) == want
+		}, *maxTests)
+		exitIf(err)
+		log.Printf("minimized %d events to %d", before, len(bv.Events))
+	}
+	exitIf(write(bv))
+
+	if *summonerID != 0 {
+		path := *statsFile
+		if path == "" {
+			path = strings.TrimSuffix(strings.TrimSuffix(*outFile, ".json"), ".baseview") + ".advanced.json"
+		}
+		stats, err := ingest.ComputeAdvanced(*bv, summoner, 0, riot.PlatformFromString("NA1"))
+		if err != nil {
+			log.Printf("not writing advanced stats: %v", err)
+			return
+		}
+		exitIf(json.WriteIndent(path, stats, 0660))
+	}
+}
+
+// output returns the advanced stats of the summoner (or the -field of them)
+// as json, or the error computing them.
+func output(bv *baseview.Baseview, summonerID int64) string {
+	stats, err := ingest.ComputeAdvanced(*bv, summonerID, 0, riot.PlatformFromString("NA1"))
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	data, err := encjson.Marshal(stats)
+	exitIf(err)
+	if *field == "" {
+		return string(data)
+	}
+
+	fields := map[string]encjson.RawMessage{}
+	exitIf(encjson.Unmarshal(data, &fields))
+	return string(fields[*field])
+}
+
+func write(bv *baseview.Baseview) error {
+	f, err := os.Create(*outFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if *binary {
+		if err = baseview.WriteBinary(f, bv); err != nil {
+			return err
+		}
+	} else {
+		w := bufio.NewWriter(f)
+		if err = encjson.NewEncoder(w).Encode(bv); err != nil {
+			return err
+		}
+		if err = w.Flush(); err != nil {
+			return err
+		}
+	}
+	return f.Close()
+}
+
+func exitIf(err error) {
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
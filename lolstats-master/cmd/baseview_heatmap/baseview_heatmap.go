@@ -0,0 +1,79 @@
+// The baseview_heatmap command accumulates a summoner's heatmaps over many
+// baseviews, like the stats server does over their last matches, and writes
+// them as json and as a png for each kind, role and game stage.
+//
+//   baseview_heatmap -s 123 -out heatmaps/ matches/*.baseview.json
+
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/VantageSports/common/json"
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/lolstats/ingest"
+	"github.com/VantageSports/riot"
+)
+
+var (
+	summonerID = flag.Int64("s", 0, "summoner id")
+	platformID = flag.String("p", "NA1", "platform id")
+	out        = flag.String("out", "", "directory to write heatmaps.json and the pngs to")
+	gridSize   = flag.Int("grid", ingest.HeatmapGridSize, "number of rows (and columns) of the grids")
+	size       = flag.Int("size", 512, "width and height of the pngs, in pixels")
+	role       = flag.String("role", "", "role the summoner played (default computed from each match)")
+)
+
+func main() {
+	flag.Parse()
+	if *summonerID == 0 || *out == "" || flag.NArg() == 0 {
+		log.Fatalln("usage: baseview_heatmap -s id -out dir [flags] file...")
+	}
+
+	var heatmaps *ingest.Heatmaps
+	for _, path := range flag.Args() {
+		bv, err := baseview.ReadFile(path)
+		exitIf(err)
+		if heatmaps == nil {
+			heatmaps, err = ingest.NewHeatmaps(*summonerID, bv.MapID, *gridSize)
+			exitIf(err)
+		}
+		if err = heatmaps.Add(bv, rolePosition(bv)); err != nil {
+			log.Printf("skipping %s: %v", path, err)
+		}
+	}
+
+	exitIf(os.MkdirAll(*out, 0755))
+	exitIf(json.WriteIndent(filepath.Join(*out, "heatmaps.json"), heatmaps, 0660))
+	for key := range heatmaps.Grids {
+		f, err := os.Create(filepath.Join(*out, strings.Replace(key, "/", "-", -1)+".png"))
+		exitIf(err)
+		exitIf(heatmaps.WritePNG(f, key, *size))
+		exitIf(f.Close())
+	}
+	log.Printf("wrote %d heatmaps of %d matches", len(heatmaps.Grids), heatmaps.Matches)
+}
+
+// rolePosition returns the -role, or else the role in the summoner's advanced
+// stats of the match ("" if they can't be computed).
+func rolePosition(bv *baseview.Baseview) baseview.RolePosition {
+	if *role != "" {
+		return baseview.RolePosition(*role)
+	}
+	stats, err := ingest.ComputeAdvanced(*bv, *summonerID, 0, riot.PlatformFromString(*platformID))
+	if err != nil {
+		log.Printf("no role: %v", err)
+		return ""
+	}
+	return stats.RolePosition
+}
+
+func exitIf(err error) {
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
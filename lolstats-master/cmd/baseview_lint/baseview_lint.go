@@ -0,0 +1,62 @@
+// The baseview_lint command checks baseview files for the invariants that
+// advanced stats assume, printing each violation. It exits with a non-zero
+// status if any file has errors (or warnings, with -strict).
+//
+//   baseview_lint [-json] [-strict] file.baseview.json ...
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/lolstats/baseview/lint"
+)
+
+var (
+	asJSON = flag.Bool("json", false, "if true, print the reports as json")
+	strict = flag.Bool("strict", false, "if true, warnings fail too")
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatalln("usage: baseview_lint [-json] [-strict] file ...")
+	}
+
+	failed := false
+	reports := map[string]*lint.Report{}
+	for _, filename := range flag.Args() {
+		bv, err := baseview.ReadFile(filename, baseview.Strict())
+		if err != nil {
+			log.Printf("%s: %v", filename, err)
+			failed = true
+			continue
+		}
+		r := lint.Lint(bv)
+		reports[filename] = r
+		failed = failed || r.Errors > 0 || (*strict && r.Warnings > 0)
+
+		if !*asJSON {
+			fmt.Printf("%s: %d errors, %d warnings\n", filename, r.Errors, r.Warnings)
+			for _, v := range r.Violations {
+				fmt.Printf("  %v\n", v)
+			}
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
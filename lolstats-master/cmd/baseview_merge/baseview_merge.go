@@ -0,0 +1,120 @@
+// The baseview_merge command merges two baseviews of the same match from
+// different sources (e.g. elo logs and ocr) into one, tagging each event with
+// its source, and prints what the sources disagree on.
+//
+//   baseview_merge -out merged.baseview.json [-priority manual,elo,ocr] a b
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/lolstats/baseview/merge"
+)
+
+var (
+	out       = flag.String("out", "", "file to write the merged baseview to")
+	binary    = flag.Bool("binary", false, "if true, write the binary encoding instead of json")
+	priority  = flag.String("priority", "", "comma separated sources, most trusted first (default manual,elo,ocr)")
+	tolerance = flag.Float64("tolerance", 1, "seconds apart the same event may be in each source")
+	align     = flag.Bool("align", true, "if true, estimate the offset between the clocks of the sources")
+	offset    = flag.Float64("offset", 0, "seconds to add to the less trusted source, if not -align")
+	sources   = flag.String("sources", "", "comma separated sources of the two baseviews, for those without a producer")
+	report    = flag.String("report", "", "if set, file to write the merge report to as json")
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 2 || *out == "" {
+		log.Fatalln("usage: baseview_merge -out file [flags] a b")
+	}
+
+	opts := merge.DefaultOptions()
+	if *priority != "" {
+		opts.Priority = strings.Split(*priority, ",")
+	}
+	opts.Tolerance = *tolerance
+	opts.Align = *align
+	opts.Offset = *offset
+
+	var overrides []string
+	if *sources != "" {
+		if overrides = strings.Split(*sources, ","); len(overrides) != 2 {
+			log.Fatalln("-sources needs one source for each baseview")
+		}
+	}
+	bvs := []*baseview.Baseview{}
+	for i, filename := range flag.Args() {
+		bv, err := baseview.ReadFile(filename)
+		if err != nil {
+			log.Fatalf("%s: %v", filename, err)
+		}
+		if overrides != nil {
+			if bv.Producer == nil {
+				bv.Producer = &baseview.Producer{}
+			}
+			bv.Producer.Source = overrides[i]
+		}
+		bvs = append(bvs, bv)
+	}
+
+	merged, r, err := merge.Merge(bvs[0], bvs[1], opts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err = write(merged); err != nil {
+		log.Fatalln(err)
+	}
+
+	fmt.Printf("merged %s into %s (offset %.2fs)\n", r.Secondary, r.Primary, r.Offset)
+	for _, counts := range []struct {
+		name   string
+		counts map[string]int
+	}{{"matched", r.Matched}, {"only in " + r.Secondary, r.Added}, {"only in " + r.Primary, r.Missing}} {
+		fmt.Printf("  %s: %v\n", counts.name, counts.counts)
+	}
+	fmt.Printf("%d disagreements %v\n", len(r.Disagreements), r.Counts)
+	for _, d := range r.Disagreements {
+		fmt.Printf("  %v\n", d)
+	}
+
+	if *report != "" {
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err = os.WriteFile(*report, data, 0644); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
+
+func write(bv *baseview.Baseview) error {
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if *binary {
+		if err = baseview.WriteBinary(f, bv); err != nil {
+			return err
+		}
+	} else {
+		w := bufio.NewWriter(f)
+		if err = json.NewEncoder(w).Encode(bv); err != nil {
+			return err
+		}
+		if err = w.Flush(); err != nil {
+			return err
+		}
+	}
+	return f.Close()
+}
@@ -0,0 +1,134 @@
+// The baseview_render command draws a window of a baseview on the minimap, as
+// an animated GIF or a directory of SVG frames. With a summoner id, it also
+// computes their advanced stats and overlays the team fights and ward coverage
+// they found.
+//
+//   baseview_render -bv match.baseview.json -out fight.gif -from 600 -to 660
+//   baseview_render -bv match.baseview.json -out frames/ -svg -s 123
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/lolstats/baseview/render"
+	"github.com/VantageSports/lolstats/ingest"
+	"github.com/VantageSports/riot"
+)
+
+var (
+	baseviewFile = flag.String("bv", "", "path to the baseview to draw")
+	out          = flag.String("out", "", "path of the GIF, or directory of the SVG frames, to write")
+	svg          = flag.Bool("svg", false, "if true, write SVG frames instead of a GIF")
+	from         = flag.Float64("from", 0, "seconds into the match to start at")
+	to           = flag.Float64("to", 0, "seconds into the match to end at (0 for the end)")
+	step         = flag.Float64("step", 1, "seconds of the match between frames")
+	size         = flag.Int("size", 512, "width and height of the frames, in pixels")
+	delay        = flag.Int("delay", 10, "time between GIF frames, in 100ths of a second")
+	background   = flag.String("background", "", "path to a png or jpeg of the minimap to draw on")
+	summonerID   = flag.Int64("s", 0, "if set, overlay the team fights and ward coverage of the summoner")
+	platformID   = flag.String("p", "NA1", "platform id")
+)
+
+func main() {
+	flag.Parse()
+	if *baseviewFile == "" || *out == "" {
+		log.Fatalln("usage: baseview_render -bv file -out file [flags]")
+	}
+
+	bv, err := baseview.ReadFile(*baseviewFile)
+	exitIf(err)
+
+	opts := render.DefaultOptions()
+	opts.From, opts.To, opts.Step = *from, *to, *step
+	opts.Size, opts.Delay = *size, *delay
+	if *background != "" {
+		opts.Background = readImage(*background)
+	}
+	if *summonerID != 0 {
+		opts.Fights, opts.Coverages = overlays(bv)
+	}
+
+	r, err := render.New(bv, opts)
+	exitIf(err)
+
+	if !*svg {
+		f, err := os.Create(*out)
+		exitIf(err)
+		exitIf(r.GIF(f))
+		exitIf(f.Close())
+		return
+	}
+
+	exitIf(os.MkdirAll(*out, 0755))
+	for i, t := range r.Times() {
+		f, err := os.Create(filepath.Join(*out, fmt.Sprintf("frame%05d.svg", i)))
+		exitIf(err)
+		exitIf(r.SVG(f, t))
+		exitIf(f.Close())
+	}
+}
+
+// overlays returns the team fights and ward coverage in the advanced stats of
+// the summoner.
+func overlays(bv *baseview.Baseview) ([]render.Fight, []render.Coverage) {
+	stats, err := ingest.ComputeAdvanced(*bv, *summonerID, 0, riot.PlatformFromString(*platformID))
+	exitIf(err)
+
+	fights := []render.Fight{}
+	for _, tf := range stats.TeamFights {
+		f := render.Fight{Begin: tf.Begin, End: tf.End}
+		for id, in := range tf.ParticipantIDs {
+			if in {
+				f.ParticipantIDs = append(f.ParticipantIDs, id)
+			}
+		}
+		fights = append(fights, f)
+	}
+
+	sightRanges := map[string]float64{}
+	for _, w := range []baseview.Ward{baseview.BlueWard, baseview.PinkWard, baseview.YellowWard} {
+		sightRanges[w.Type()] = w.SightRange()
+	}
+	coverages := []render.Coverage{}
+	for creator, lives := range stats.WardLives {
+		for _, life := range lives {
+			if life.Position == nil {
+				continue
+			}
+			coverages = append(coverages, render.Coverage{
+				Begin:    life.Begin,
+				End:      life.End,
+				Position: *life.Position,
+				Radius:   sightRanges[life.Type],
+				TeamID:   baseview.TeamID(creator),
+			})
+		}
+	}
+	return fights, coverages
+}
+
+func readImage(path string) image.Image {
+	f, err := os.Open(path)
+	exitIf(err)
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		log.Fatalf("%s: %v", path, err)
+	}
+	return img
+}
+
+func exitIf(err error) {
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/VantageSports/common/json"
 	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/lolstats/baseview/lint"
 	"github.com/VantageSports/lolstats/generate"
 	"github.com/VantageSports/lolstats/ingest"
 	"github.com/VantageSports/riot"
@@ -18,10 +19,11 @@ import (
 var advanced = flag.Bool("advanced", true, "if true, do advanced, else do basic")
 var matchDetailsFile = flag.String("md", "", "path to match details file (for basic)")
 var baseviewFile = flag.String("bv", "", "path to baseview file (for advanced)")
-var summonerID = flag.Int64("s", 0, "summoner id")
+var summonerID = flag.Int64("s", 0, "summoner id (for advanced, 0 for every participant of the match)")
 var matchID = flag.Int64("m", 0, "match id")
 var platformID = flag.String("p", "NA1", "platform id")
 var outFile = flag.String("o", "/tmp/stats.json", "path to baseview file")
+var lintBaseview = flag.Bool("lint", true, "if true, refuse baseviews with lint errors (for advanced)")
 
 func main() {
 	flag.Parse()
@@ -47,13 +49,26 @@ func computeBasic(detailsFile string, summonerID int64) *generate.BasicStats {
 	return stats
 }
 
-func computeAdvanced(baseviewFile string, summonerID, matchID int64, platformID string) *ingest.AdvancedStats {
-	bv := baseview.Baseview{}
-	err := json.DecodeFile(baseviewFile, &bv)
+// computeAdvanced returns the advanced stats of the summoner, or of the whole
+// match if summonerID is 0.
+func computeAdvanced(baseviewFile string, summonerID, matchID int64, platformID string) interface{} {
+	bv, err := baseview.ReadFile(baseviewFile)
 	exitIf(err)
+	if *lintBaseview {
+		r := lint.Lint(bv)
+		for _, v := range r.Violations {
+			log.Println(v)
+		}
+		exitIf(r.Err())
+	}
 
 	platform := riot.PlatformFromString(platformID)
-	stats, err := ingest.ComputeAdvanced(bv, summonerID, matchID, platform)
+	if summonerID == 0 {
+		stats, err := ingest.ComputeAdvancedMatch(*bv, matchID, platform)
+		exitIf(err)
+		return stats
+	}
+	stats, err := ingest.ComputeAdvanced(*bv, summonerID, matchID, platform)
 	exitIf(err)
 
 	return stats
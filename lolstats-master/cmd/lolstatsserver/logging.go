@@ -70,6 +70,13 @@ func (s *StatsLogger) TeamAdvanced(ctx context.Context, in *lolstats.TeamRequest
 	return out, err
 }
 
+func (s *StatsLogger) Heatmap(ctx context.Context, in *lolstats.HeatmapRequest) (*lolstats.HeatmapResponse, error) {
+	start := time.Now()
+	out, err := s.next.Heatmap(ctx, in)
+	logRequest("Heatmap", start, err)
+	return out, err
+}
+
 func logRequest(method string, start time.Time, err error, v ...interface{}) {
 	millis := int64(time.Since(start).Seconds() * 1000)
 	v = append(v, "method", method, "ms", millis, "err", err)
@@ -0,0 +1,97 @@
+// The ocr_baseview command converts lolocr's post processed output into a
+// baseview, with the participants and map of the match details, and lints it
+// like any other baseview (except that only the participants whose videos
+// were OCR'd need state updates).
+//
+//   ocr_baseview -ocr 123-na-p7-events.json -md 123-na.json -out 123-NA1-7.ocr.baseview.json
+
+package main
+
+import (
+	"flag"
+	"log"
+	"sort"
+
+	"github.com/VantageSports/common/json"
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/lolstats/baseview/lint"
+	"github.com/VantageSports/lolstats/baseview/ocr"
+	"github.com/VantageSports/riot"
+	"github.com/VantageSports/riot/api"
+)
+
+var (
+	ocrFile          = flag.String("ocr", "", "path to lolocr's post processed output (after the align step)")
+	matchDetailsFile = flag.String("md", "", "path to the match details")
+	outFile          = flag.String("out", "", "path to write the baseview to")
+	lintBaseview     = flag.Bool("lint", true, "if true, refuse baseviews with lint errors")
+	statsFile        = flag.String("stats", "", "if set, path to write the conversion stats to")
+)
+
+func main() {
+	flag.Parse()
+	if *ocrFile == "" || *matchDetailsFile == "" || *outFile == "" {
+		log.Fatalln("usage: ocr_baseview -ocr file -md file -out file [flags]")
+	}
+
+	out, err := ocr.ReadFile(*ocrFile)
+	exitIf(err)
+	md := api.MatchDetail{}
+	exitIf(json.DecodeFile(*matchDetailsFile, &md))
+
+	bv, stats, err := ocr.ToBaseview(out, participants(&md), int64(md.MapID))
+	exitIf(err)
+	log.Printf("converted %d of %d frames: %d state updates, %d attacks",
+		stats.Frames-stats.Unaligned, stats.Frames, stats.StateUpdates, stats.Attacks)
+	if *statsFile != "" {
+		exitIf(json.WriteIndent(*statsFile, stats, 0660))
+	}
+
+	if *lintBaseview {
+		r := lint.LintViews(bv, stats.Views)
+		for _, v := range r.Violations {
+			log.Println(v)
+		}
+		exitIf(r.Err())
+	}
+	exitIf(json.WriteIndent(*outFile, bv, 0660))
+}
+
+type byParticipantID []baseview.Participant
+
+func (p byParticipantID) Len() int           { return len(p) }
+func (p byParticipantID) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p byParticipantID) Less(i, j int) bool { return p[i].ParticipantID < p[j].ParticipantID }
+
+// participants returns the participants of the match, sorted by participant
+// id.
+func participants(md *api.MatchDetail) []baseview.Participant {
+	byPID := map[int]*baseview.Participant{}
+	for _, p := range md.Participants {
+		byPID[p.ParticipantID] = &baseview.Participant{
+			ParticipantID: int64(p.ParticipantID),
+			ChampionID:    int64(p.ChampionID),
+			Spell1:        riot.SpellNamesByID[int64(p.Spell1ID)],
+			Spell2:        riot.SpellNamesByID[int64(p.Spell2ID)],
+		}
+	}
+	for _, pi := range md.ParticipantIdentities {
+		if p, ok := byPID[pi.ParticipantID]; ok {
+			p.SummonerID = pi.Player.SummonerID
+			p.SummonerName = pi.Player.SummonerName
+		}
+	}
+
+	res := []baseview.Participant{}
+	for _, p := range byPID {
+		res = append(res, *p)
+	}
+	sort.Sort(byParticipantID(res))
+	return res
+}
+
+func exitIf(err error) {
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
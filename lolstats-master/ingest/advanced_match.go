@@ -0,0 +1,105 @@
+package ingest
+
+import (
+	"fmt"
+
+	"github.com/VantageSports/lolstats/baseview"
+	"github.com/VantageSports/riot"
+)
+
+// MatchAdvancedStats are the advanced stats of every participant of a match,
+// and of each team.
+type MatchAdvancedStats struct {
+	MatchID    int64         `json:"match_id,omitempty"`
+	PlatformID riot.Platform `json:"platform_id,omitempty"`
+	MapID      int64         `json:"map_id,omitempty"`
+
+	// Participants are in the order of the baseview's participants.
+	Participants []*AdvancedStats     `json:"participants"`
+	Teams        []*TeamAdvancedStats `json:"teams"`
+}
+
+// Participant returns the advanced stats of the summoner, or nil if they
+// didn't play the match.
+func (m *MatchAdvancedStats) Participant(summonerID int64) *AdvancedStats {
+	for _, s := range m.Participants {
+		if s.SummonerID == summonerID {
+			return s
+		}
+	}
+	return nil
+}
+
+// TeamAdvancedStats sums the advanced stats of a team's participants.
+type TeamAdvancedStats struct {
+	TeamID  int64        `json:"team_id"`
+	Members []TeamMember `json:"members"`
+
+	// DamageDealt and DamageTaken are the team's champion damage, by enemy
+	// champion and game stage, like a participant's.
+	DamageDealt DamageMap `json:"damage_dealt,omitempty"`
+	DamageTaken DamageMap `json:"damage_taken,omitempty"`
+	// LiveWardsAverage is the average number of the team's live wards of each
+	// type.
+	LiveWardsAverage map[string]float64 `json:"live_wards_average,omitempty"`
+}
+
+type TeamMember struct {
+	ParticipantID int64                 `json:"participant_id"`
+	SummonerID    int64                 `json:"summoner_id,omitempty"`
+	RolePosition  baseview.RolePosition `json:"role_position,omitempty"`
+}
+
+// ComputeAdvancedMatch computes the advanced stats of every participant of the
+// match, and of each team. Each participant's stats are the same as
+// ComputeAdvanced's, but the events are scanned (and the team fight state,
+// wards, roles and index computed) once instead of once per participant,
+// which is several times faster (see BenchmarkComputeAdvancedMatch).
+func ComputeAdvancedMatch(bv baseview.Baseview, matchID int64, platformID riot.Platform) (*MatchAdvancedStats, error) {
+	if len(bv.Participants) == 0 {
+		return nil, fmt.Errorf("match %d has no participants", matchID)
+	}
+	participants := make([]*baseview.Participant, len(bv.Participants))
+	for i := range bv.Participants {
+		p := bv.Participants[i]
+		participants[i] = &p
+	}
+
+	stats, rolePositions, err := computeAdvanced(&bv, participants, matchID, platformID)
+	if err != nil {
+		return nil, err
+	}
+	res := &MatchAdvancedStats{
+		MatchID:      matchID,
+		PlatformID:   platformID,
+		MapID:        bv.MapID,
+		Participants: stats,
+		Teams:        []*TeamAdvancedStats{},
+	}
+
+	byTeam := map[int64]*TeamAdvancedStats{}
+	for _, s := range stats {
+		team := byTeam[s.TeamID]
+		if team == nil {
+			team = &TeamAdvancedStats{TeamID: s.TeamID, DamageDealt: DamageMap{}, DamageTaken: DamageMap{}}
+			byTeam[s.TeamID] = team
+			res.Teams = append(res.Teams, team)
+		}
+		team.Members = append(team.Members, TeamMember{
+			ParticipantID: s.ParticipantID,
+			SummonerID:    s.SummonerID,
+			RolePosition:  rolePositions[s.ParticipantID],
+		})
+		team.DamageDealt.Merge(s.DamageDealt)
+		team.DamageTaken.Merge(s.DamageTaken)
+		if s.LiveWardsAverage != nil {
+			if team.LiveWardsAverage == nil {
+				team.LiveWardsAverage = map[string]float64{}
+			}
+			for wardType, average := range s.LiveWardsAverage {
+				team.LiveWardsAverage[wardType] += average
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,162 @@
+package ingest
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	"gopkg.in/tylerb/is.v1"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// laneMinions are where each participant of advancedMatch farms, so that
+// roles can be computed: 1 and 6 mid, 3 and 8 top, 4 and 9 bot (with their
+// supports 5 and 10, who farm less), and 2 and 7 in the jungle.
+var laneMinions = map[int64]baseview.Position{
+	1: {X: 5000, Y: 5000}, 6: {X: 6000, Y: 6000},
+	3: {X: 2000, Y: 12500}, 8: {X: 1500, Y: 13000},
+	4: {X: 12500, Y: 2000}, 9: {X: 13000, Y: 3000},
+	5: {X: 12500, Y: 2000}, 10: {X: 13000, Y: 3000},
+}
+
+func matchEvent(e baseview.Event, eventType string, seconds float64) baseview.Event {
+	e.SetType(eventType)
+	e.SetSeconds(seconds)
+	return e
+}
+
+// advancedMatch returns a summoner's rift version of randomMatch, in which
+// the participants also farm, level up, ward, and fight each other.
+func advancedMatch(seed int64) *baseview.Baseview {
+	bv := randomMatch(seed)
+	bv.Version = baseview.CurrentVersion
+	bv.MapID = baseview.MapSummonersRift
+	for i := range bv.Participants {
+		bv.Participants[i].SummonerID = 100 + bv.Participants[i].ParticipantID
+		bv.Participants[i].ChampionID = bv.Participants[i].ParticipantID
+		bv.Participants[i].Spell1 = "Flash"
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	slots := []string{"Q", "W", "E", "R", "basic", "Summoner1"}
+	events := []baseview.Event{}
+	wardID := int64(0)
+	for _, e := range bv.Events {
+		events = append(events, e)
+		s, ok := e.(*baseview.StateUpdate)
+		if !ok || s.Health <= 0 {
+			continue
+		}
+		id, seconds := s.ParticipantID, s.Seconds()
+
+		if minion, found := laneMinions[id]; found && r.Intn(4) == 0 && (id != 5 && id != 10 || r.Intn(3) == 0) {
+			events = append(events, matchEvent(&baseview.Attack{AttackerID: id, AttackerType: baseview.ActorHero, Slot: "basic",
+				TargetType: baseview.ActorMinion, TargetPosition: &minion}, "attack", seconds))
+		}
+		if (id == 2 || id == 7) && r.Intn(4) == 0 {
+			events = append(events, matchEvent(&baseview.Attack{AttackerID: id, AttackerType: baseview.ActorHero, Slot: "basic",
+				TargetID: baseview.MonsterGromp}, "attack", seconds))
+		}
+		if r.Intn(10) == 0 {
+			victim := baseview.EnemyTeamID(id)/100*5 - 4 + int64(r.Intn(5))
+			events = append(events, matchEvent(&baseview.Attack{AttackerID: id, AttackerType: baseview.ActorHero,
+				Slot: slots[r.Intn(len(slots))], TargetID: victim, TargetType: baseview.ActorHero}, "attack", seconds))
+			events = append(events, matchEvent(&baseview.Damage{AttackerID: id, AttackerType: baseview.ActorHero,
+				VictimID: victim, VictimType: baseview.ActorHero, Total: float64(r.Intn(200)), Percent: float64(r.Intn(20))}, "damage", seconds))
+		}
+		if r.Intn(120) == 0 {
+			events = append(events, matchEvent(&baseview.LevelUp{ParticipantID: id}, "level_up", seconds))
+		}
+		if r.Intn(400) == 0 {
+			wardID++
+			events = append(events, matchEvent(&baseview.WardPlaced{ParticipantID: id, WardID: wardID, WardType: "yellow",
+				WardItemType: baseview.YellowTrinket, Position: s.Position, TeamID: baseview.TeamID(id)}, "ward_placed", seconds))
+		}
+	}
+	bv.Events = events
+	return bv
+}
+
+// normalized returns the stats as json, without the fields that change every
+// time they are computed.
+func normalized(s *AdvancedStats) string {
+	s.LastUpdated = time.Time{}
+	for _, r := range s.Analyses {
+		r.Seconds = 0
+	}
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+func TestComputeAdvancedMatch(t *testing.T) {
+	is := is.New(t)
+
+	for _, mapID := range []int64{baseview.MapSummonersRift, baseview.MapHowlingAbyss} {
+		bv := advancedMatch(1)
+		bv.MapID = mapID
+		m, err := ComputeAdvancedMatch(*bv, 123, "NA1")
+		is.NotErr(err)
+		is.Equal(10, len(m.Participants))
+
+		// the same stats as computing each participant's alone.
+		for i, p := range bv.Participants {
+			alone, err := ComputeAdvanced(*bv, p.SummonerID, 123, "NA1")
+			is.NotErr(err)
+			is.Equal(p.ParticipantID, m.Participants[i].ParticipantID)
+			is.Equal(normalized(alone), normalized(m.Participants[i]))
+			is.Equal(m.Participants[i], m.Participant(p.SummonerID))
+		}
+		is.Nil(m.Participant(1))
+
+		is.Equal(2, len(m.Teams))
+		blue := m.Teams[0]
+		is.Equal(baseview.BlueTeam, blue.TeamID)
+		is.Equal(5, len(blue.Members))
+		is.Equal(int64(105), blue.Members[4].SummonerID)
+
+		dealt := 0.0
+		for _, s := range m.Participants[:5] {
+			dealt += s.DamageDealt["6"]["early"].DamageTotal
+		}
+		is.Equal(dealt, blue.DamageDealt["6"]["early"].DamageTotal)
+
+		if mapID == baseview.MapSummonersRift {
+			is.Equal(baseview.RoleMid, blue.Members[0].RolePosition)
+			is.Equal(baseview.RoleJungle, blue.Members[1].RolePosition)
+			is.True(blue.LiveWardsAverage["yellow"] > 0)
+		} else {
+			is.Equal(baseview.RolePosition(""), blue.Members[0].RolePosition)
+			is.Nil(blue.LiveWardsAverage)
+		}
+	}
+
+	_, err := ComputeAdvancedMatch(baseview.Baseview{MapID: baseview.MapSummonersRift}, 123, "NA1")
+	is.Err(err)
+}
+
+// BenchmarkComputeAdvancedEach computes the stats of every participant with
+// ten calls to ComputeAdvanced, for comparison with
+// BenchmarkComputeAdvancedMatch.
+func BenchmarkComputeAdvancedEach(b *testing.B) {
+	bv := advancedMatch(1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, p := range bv.Participants {
+			if _, err := ComputeAdvanced(*bv, p.SummonerID, 123, "NA1"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkComputeAdvancedMatch(b *testing.B) {
+	bv := advancedMatch(1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ComputeAdvancedMatch(*bv, 123, "NA1"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -10,17 +10,19 @@ import (
 	"github.com/VantageSports/riot"
 )
 
+// killCreditSeconds is how long after a champ last damaged another that they
+// get credit for its death.
+const killCreditSeconds = 13
+
 // advancedStatsState is the "bucket o' data" that we don't plan on exporting,
-// but is useful state for calculating advanced stats.
+// but is useful state for calculating advanced stats. It is shared by every
+// participant whose stats are computed.
 type advancedStatsState struct {
-	AttacksTotal            int64
-	DamageTakenPercentTotal float64
 	// LastChampDamage is a map from victim participantID to the last champ
 	// attack upon that champion. It is used to attribute deaths, which are
-	// given to the last champ to cause damage (within 13 seconds).
+	// given to the last champ to cause damage (within killCreditSeconds).
 	LastChampDamage   map[int64]baseview.Damage
 	MatchDuration     float64
-	Deaths            int64
 	MinionStatTracker *MinionStatTracker
 	BuddyFinder       *BuddyFinder
 }
@@ -31,6 +33,7 @@ type AdvancedStats struct {
 	TeamID        int64         `json:"team_id,omitempty"`
 	MatchID       int64         `json:"match_id,omitempty"`
 	PlatformID    riot.Platform `json:"platform_id,omitempty"`
+	MapID         int64         `json:"map_id,omitempty"`
 	LastUpdated   time.Time     `json:"last_updated,omitempty"`
 
 	Positions    []PositionTime        `json:"champ_positions,omitempty"`
@@ -46,6 +49,7 @@ type AdvancedStats struct {
 	AbilityCounts      map[string]int64   `json:"ability_counts,omitempty"`
 	AbilityCounts0to10 map[string]int64   `json:"ability_counts_zero_to_ten,omitempty"`
 	MapCoverages       map[string]float64 `json:"map_coverages,omitempty"`
+	AreaCoverages      map[string]float64 `json:"area_coverages,omitempty"`
 
 	TeamComp *TeamComp `json:"team_comp,omitempty"`
 
@@ -67,6 +71,9 @@ type AdvancedStats struct {
 
 	Combos               []*ComboSummary `json:"combos,omitempty"`
 	ComboDamagePerMinute float64         `json:"combo_damage_per_minute"`
+
+	// Analyses are the results of the registered analyzers.
+	Analyses Analyses `json:"analyses,omitempty"`
 }
 
 func (s *AdvancedStats) TrimNonStats() {
@@ -78,6 +85,7 @@ func (s *AdvancedStats) TrimNonStats() {
 	s.TeamFights = nil
 	s.WardLives = nil
 	s.Combos = nil
+	s.Analyses = nil
 }
 
 type PositionTime struct {
@@ -110,11 +118,73 @@ func stageDurationSecs(gameStage string, matchDurationSecs float64) float64 {
 	return matchDurationSecs
 }
 
+// UnsupportedMapError is returned when advanced stats can't be computed for
+// the map a match was played on.
+type UnsupportedMapError struct {
+	MapID int64
+}
+
+func (e *UnsupportedMapError) Error() string {
+	return fmt.Sprintf("unsupported map: %d", e.MapID)
+}
+
+// RolePositionError is returned when the players of a match can't be assigned
+// a role each, typically because they didn't stick to the meta.
+type RolePositionError struct {
+	Roles map[int64]baseview.RolePosition
+}
+
+func (e *RolePositionError) Error() string {
+	return fmt.Sprintf("error in role position calculation. expected 8 roles, but only got %d %v", len(e.Roles), e.Roles)
+}
+
+// ComputeAdvanced computes the advanced stats of the summoner in the match,
+// and then runs the analyzers of DefaultRegistry on it. Analyses that don't
+// support the map the match was played on (see analysisMaps, and the
+// analyzers' maps) are skipped, leaving their stats empty. To compute the
+// stats of every participant, ComputeAdvancedMatch is much faster.
 func ComputeAdvanced(bv baseview.Baseview, summonerID int64, matchID int64, platformID riot.Platform) (*AdvancedStats, error) {
-	byPID, p := buildPIDMap(bv.Participants, summonerID)
+	_, p := buildPIDMap(bv.Participants, summonerID)
 	if p == nil {
 		return nil, fmt.Errorf("no participant found with summoner id %d", summonerID)
 	}
+	stats, _, err := computeAdvanced(&bv, []*baseview.Participant{p}, matchID, platformID)
+	if err != nil {
+		return nil, err
+	}
+	return stats[0], nil
+}
+
+// participantStats accumulates the advanced stats of one participant while
+// computeAdvanced scans the events.
+type participantStats struct {
+	p   *baseview.Participant
+	res *AdvancedStats
+
+	attacksTotal            int64
+	damageTakenPercentTotal float64
+	deaths                  int64
+
+	coverageMap    *CoverageMap
+	teamFights     *teamFightAnalysis
+	timeManagement *timeManagementState
+}
+
+// computeAdvanced computes the advanced stats of the participants in one scan
+// of the events. The work that doesn't depend on the participant (the state
+// team fights are found in, wards, roles, kill credit, the index, and the
+// analyzers' match-wide work) is only done once, so each participant's stats
+// are the same as if they had been computed alone. It also returns the role
+// positions, if the map supports them.
+func computeAdvanced(bv *baseview.Baseview, participants []*baseview.Participant, matchID int64, platformID riot.Platform) ([]*AdvancedStats, map[int64]baseview.RolePosition, error) {
+	byPID, _ := buildPIDMap(bv.Participants, 0)
+	geometry := baseview.MapByID(bv.MapID)
+	if geometry == nil {
+		return nil, nil, &UnsupportedMapError{MapID: bv.MapID}
+	}
+	roles := supports(analysisRoles, geometry)
+	wards := supports(analysisWards, geometry)
+	timed := supports(analysisTimeManagement, geometry)
 
 	state := advancedStatsState{
 		LastChampDamage:   map[int64]baseview.Damage{},
@@ -122,55 +192,70 @@ func ComputeAdvanced(bv baseview.Baseview, summonerID int64, matchID int64, plat
 		MinionStatTracker: NewMinionStatTracker(),
 		BuddyFinder:       NewBuddyFinder(),
 	}
-
-	res := AdvancedStats{
-		ParticipantID: p.ParticipantID,
-		SummonerID:    summonerID,
-		TeamID:        baseview.TeamID(p.ParticipantID),
-		MatchID:       matchID,
-		PlatformID:    platformID,
-		LastUpdated:   time.Now(),
-
-		AbilityCounts:      map[string]int64{"Q": 0, "W": 0, "E": 0, "R": 0},
-		AbilityCounts0to10: map[string]int64{"Q": 0, "W": 0, "E": 0, "R": 0},
-		DamageDealt:        DamageMap{},
-		DamageTaken:        DamageMap{},
-		TeamComp:           &TeamComp{},
-	}
-
-	for _, eachP := range byPID {
-		res.TeamComp.AddParticipant(*p, *eachP)
-	}
-
-	coverageMap := NewCoverageMap(12)
 	wardAnalysis := NewWardAnalysis()
-	teamFights := NewTeamFightAnalysis(p.ParticipantID, state.MatchDuration, wardAnalysis)
-	timeManagement := NewTimeManagement(p.ParticipantID)
-	comboAnalysis := NewComboAnalysis(p)
+	fights := newFightState(geometry)
+
+	all := make([]*participantStats, len(participants))
+	for i, p := range participants {
+		ps := &participantStats{
+			p: p,
+			res: &AdvancedStats{
+				ParticipantID: p.ParticipantID,
+				SummonerID:    p.SummonerID,
+				TeamID:        baseview.TeamID(p.ParticipantID),
+				MatchID:       matchID,
+				PlatformID:    platformID,
+				MapID:         geometry.ID,
+				LastUpdated:   time.Now(),
+
+				AbilityCounts:      map[string]int64{"Q": 0, "W": 0, "E": 0, "R": 0},
+				AbilityCounts0to10: map[string]int64{"Q": 0, "W": 0, "E": 0, "R": 0},
+				DamageDealt:        DamageMap{},
+				DamageTaken:        DamageMap{},
+				TeamComp:           &TeamComp{},
+			},
+			coverageMap:    NewCoverageMap(geometry, 12),
+			teamFights:     newTeamFightAnalysis(p.ParticipantID, state.MatchDuration, wardAnalysis, fights),
+			timeManagement: NewTimeManagement(p.ParticipantID),
+		}
+		for _, eachP := range byPID {
+			ps.res.TeamComp.AddParticipant(*p, *eachP)
+		}
+		all[i] = ps
+	}
 
 	for i := range bv.Events {
 		e := bv.Events[i]
 		switch t := e.(type) {
 
 		case *baseview.Attack:
-			wardAnalysis.AddAttack(t)
-			state.MinionStatTracker.AddAttack(t)
-			teamFights.AddAttack(t)
-			if t.AttackerID != p.ParticipantID {
-				continue
+			if wards {
+				wardAnalysis.AddAttack(t)
 			}
-			state.AttacksTotal++
-			if t.Slot == "Q" || t.Slot == "W" || t.Slot == "E" || t.Slot == "R" {
-				res.AbilityCounts[t.Slot]++
-				if t.Seconds() <= 600 {
-					res.AbilityCounts0to10[t.Slot]++
+			if roles {
+				state.MinionStatTracker.AddAttack(t)
+			}
+			fights.AddAttack(t)
+			for _, ps := range all {
+				if t.AttackerID != ps.p.ParticipantID {
+					continue
+				}
+				ps.attacksTotal++
+				if t.Slot == "Q" || t.Slot == "W" || t.Slot == "E" || t.Slot == "R" {
+					ps.res.AbilityCounts[t.Slot]++
+					if t.Seconds() <= 600 {
+						ps.res.AbilityCounts0to10[t.Slot]++
+					}
+				}
+				if timed {
+					ps.timeManagement.AddAttack(t.Seconds(), t.TargetID, t.TargetType)
 				}
 			}
-			comboAnalysis.AddAttack(t)
-			timeManagement.AddAttack(t.Seconds(), t.TargetID, t.TargetType)
 
 		case *baseview.Damage:
-			state.MinionStatTracker.AddDamage(t)
+			if roles {
+				state.MinionStatTracker.AddDamage(t)
+			}
 			attacker := byPID[t.AttackerID]
 			victim := byPID[t.VictimID]
 
@@ -182,127 +267,199 @@ func ComputeAdvanced(bv baseview.Baseview, summonerID int64, matchID int64, plat
 			if attacker != nil {
 				state.LastChampDamage[victim.ParticipantID] = *t
 			}
-			if attacker != nil && attacker != victim {
-				if t.AttackerID == p.ParticipantID {
-					res.DamageDealt.AddDamage(victim.ChampionID, t.Seconds(), t.Total, t.Percent)
-					comboAnalysis.AddDamage(t)
+			// Significant sources of damage to players from heros, turrets, and
+			// monsters should be considered for team fights.
+			fightDamage := (attacker != nil && baseview.TeamID(victim.ParticipantID) != baseview.TeamID(attacker.ParticipantID)) ||
+				t.AttackerType == baseview.ActorTurret || baseview.IsEpicMonster(t.AttackerID)
+			if fightDamage {
+				fights.addDamage(t)
+			}
+
+			for _, ps := range all {
+				if attacker != nil && attacker != victim {
+					if t.AttackerID == ps.p.ParticipantID {
+						ps.res.DamageDealt.AddDamage(victim.ChampionID, t.Seconds(), t.Total, t.Percent)
+					}
+					if t.VictimID == ps.p.ParticipantID {
+						ps.res.DamageTaken.AddDamage(attacker.ChampionID, t.Seconds(), t.Total, t.Percent)
+						ps.damageTakenPercentTotal += t.Percent
+					}
+				}
+				if t.VictimID == ps.p.ParticipantID && timed {
+					ps.timeManagement.AddDamage(t.Seconds(), t.AttackerID, t.AttackerType)
 				}
-				if t.VictimID == p.ParticipantID {
-					res.DamageTaken.AddDamage(attacker.ChampionID, t.Seconds(), t.Total, t.Percent)
-					state.DamageTakenPercentTotal += t.Percent
+				if fightDamage {
+					ps.teamFights.analyzeDamage(t)
 				}
 			}
 
-			if t.VictimID == p.ParticipantID {
-				timeManagement.AddDamage(t.Seconds(), t.AttackerID, t.AttackerType)
+		case *baseview.StateUpdate:
+			// team fights see the wards before this update.
+			fights.addStateUpdate(t)
+			for _, ps := range all {
+				ps.teamFights.analyzeStateUpdate(t)
 			}
-
-			// Significant sources of damage to players from heros, turrets, and
-			// monsters should be considered for team fights.
-			if (attacker != nil && baseview.TeamID(victim.ParticipantID) != baseview.TeamID(attacker.ParticipantID)) ||
-				t.AttackerType == baseview.ActorTurret || baseview.IsEpicMonster(t.AttackerID) {
-				teamFights.AddDamage(t)
+			if wards {
+				wardAnalysis.AddStateUpdate(t)
 			}
-
-		case *baseview.StateUpdate:
-			teamFights.AddStateUpdate(t)
-			wardAnalysis.AddStateUpdate(t)
-			state.BuddyFinder.AddStateUpdate(t)
-			if t.ParticipantID != p.ParticipantID {
-				continue
+			if roles {
+				state.BuddyFinder.AddStateUpdate(t)
 			}
-			if (len(res.Positions) == 0 || t.Seconds()-res.Positions[len(res.Positions)-1].Seconds > 3) &&
-				t.Health > 0 {
-				res.Positions = append(res.Positions, PositionTime{
-					Position: t.Position,
-					Seconds:  t.Seconds(),
-				})
+			for _, ps := range all {
+				if t.ParticipantID != ps.p.ParticipantID {
+					continue
+				}
+				res := ps.res
+				if (len(res.Positions) == 0 || t.Seconds()-res.Positions[len(res.Positions)-1].Seconds > 3) &&
+					t.Health > 0 {
+					res.Positions = append(res.Positions, PositionTime{
+						Position: t.Position,
+						Seconds:  t.Seconds(),
+					})
+				}
+				ps.coverageMap.Add(t.Seconds(), t.Position)
+				if timed {
+					ps.timeManagement.AddStateUpdate(t.Seconds(), t.Position, t.Health)
+				}
 			}
-			coverageMap.Add(t.Seconds(), t.Position)
-			timeManagement.AddStateUpdate(t.Seconds(), t.Position, t.Health)
 
 		case *baseview.WardPlaced:
-			wardAnalysis.AddWardPlaced(t, state.MatchDuration)
-			if t.ParticipantID != p.ParticipantID {
+			if !wards {
 				continue
 			}
-			timeManagement.AddWard(t.Seconds())
+			wardAnalysis.AddWardPlaced(t, state.MatchDuration)
+			for _, ps := range all {
+				if t.ParticipantID == ps.p.ParticipantID && timed {
+					ps.timeManagement.AddWard(t.Seconds())
+				}
+			}
 
 		case *baseview.WardDeath:
+			if !wards {
+				continue
+			}
 			if err := wardAnalysis.AddWardDeath(t); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		case *baseview.Death:
-			teamFights.AddDeath(t)
+			// the fights see the state before the death.
+			for _, ps := range all {
+				ps.teamFights.analyzeDeath(t)
+			}
+			fights.updateDeath(t)
 			victim := byPID[t.VictimID]
 
 			var killer *baseview.Participant
 			if lastDamage, found := state.LastChampDamage[t.VictimID]; found {
 				// If a champ took damaged from another champ within the last 13
 				// seconds, kill credit goes to that champ.
-				if t.Seconds()-lastDamage.Seconds() < 13 {
+				if t.Seconds()-lastDamage.Seconds() < killCreditSeconds {
 					killer = byPID[lastDamage.AttackerID]
 				}
 			}
 
-			if killer != nil && killer.ParticipantID == p.ParticipantID {
-				res.DamageDealt.AddDeath(victim.ChampionID, t.Seconds())
-			}
+			for _, ps := range all {
+				if killer != nil && killer.ParticipantID == ps.p.ParticipantID {
+					ps.res.DamageDealt.AddDeath(victim.ChampionID, t.Seconds())
+				}
 
-			if t.VictimID != p.ParticipantID {
-				continue
-			}
-			if killer != nil {
-				res.DamageTaken.AddDeath(killer.ChampionID, t.Seconds())
+				if t.VictimID != ps.p.ParticipantID {
+					continue
+				}
+				if killer != nil {
+					ps.res.DamageTaken.AddDeath(killer.ChampionID, t.Seconds())
+				}
+				ps.deaths++
+				if timed {
+					ps.timeManagement.AddDeath(t.Seconds())
+				}
 			}
-			state.Deaths++
-			timeManagement.AddDeath(t.Seconds())
 		case *baseview.LevelUp:
-			state.MinionStatTracker.AddLevelUp(t)
-			teamFights.AddLevelUp(t)
+			if roles {
+				state.MinionStatTracker.AddLevelUp(t)
+			}
+			fights.AddLevelUp(t)
 		case *baseview.BuildingKill:
 			if t.BuildingType == "turret" {
-				teamFights.AddTurretKill(t)
+				fights.AddTurretKill(t)
 			}
 		}
 
 	}
 
-	res.AttacksPerMinute = float64(state.AttacksTotal) / (state.MatchDuration / 60.0)
-	res.FavorableTeamFights = teamFights.Aggregate(FavorableFights)
-	res.BalancedTeamFights = teamFights.Aggregate(BalancedFights)
-	res.UnfavorableTeamFights = teamFights.Aggregate(UnfavorableFights)
-	res.GoodKills, res.BadDeaths = teamFights.SignificantKillsAndDeaths()
-	res.TeamFights = teamFights.TeamFights
-	res.FavorableFightPercent = 100.0 * float64(res.FavorableTeamFights.Count) / math.Max(float64(len(res.TeamFights)), 1)
-
-	res.WardLives = wardAnalysis.ExportPlayerWards()
-	res.RevealsPerWardAverage = wardAnalysis.RevealsPerWardAverage(p.ParticipantID)
-	res.LiveWardsAverage = wardAnalysis.LiveWardsAverage(p.ParticipantID, state.MatchDuration)
-
-	rolePositions, err := CalculateRolePosition(state.MinionStatTracker, state.BuddyFinder)
-	if err != nil {
-		return nil, err
+	var rolePositions map[int64]baseview.RolePosition
+	if roles {
+		var err error
+		rolePositions, err = CalculateRolePosition(state.MinionStatTracker, state.BuddyFinder)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	res.CarryFocusEfficiency = CalculateCarryFocusEfficiency(res.TeamFights, rolePositions, state.MatchDuration)
-
-	res.DamageTakenPercentPerDeath = state.DamageTakenPercentTotal / math.Max(float64(state.Deaths), 1)
+	index := baseview.NewIndex(bv)
+	cache := map[string]interface{}{}
+	stats := make([]*AdvancedStats, len(all))
+	for i, ps := range all {
+		res := ps.res
+		res.AttacksPerMinute = float64(ps.attacksTotal) / (state.MatchDuration / 60.0)
+		res.FavorableTeamFights = ps.teamFights.Aggregate(FavorableFights)
+		res.BalancedTeamFights = ps.teamFights.Aggregate(BalancedFights)
+		res.UnfavorableTeamFights = ps.teamFights.Aggregate(UnfavorableFights)
+		res.GoodKills, res.BadDeaths = ps.teamFights.SignificantKillsAndDeaths()
+		res.TeamFights = ps.teamFights.TeamFights
+		res.FavorableFightPercent = 100.0 * float64(res.FavorableTeamFights.Count) / math.Max(float64(len(res.TeamFights)), 1)
+
+		if wards {
+			res.WardLives = wardAnalysis.ExportPlayerWards()
+			res.RevealsPerWardAverage = wardAnalysis.RevealsPerWardAverage(ps.p.ParticipantID)
+			res.LiveWardsAverage = wardAnalysis.LiveWardsAverage(ps.p.ParticipantID, state.MatchDuration)
+		}
 
-	res.MapCoverages = coverageMap.Percents()
+		if roles {
+			res.RolePosition = rolePositions[ps.p.ParticipantID]
+			res.CarryFocusEfficiency = CalculateCarryFocusEfficiency(res.TeamFights, rolePositions, state.MatchDuration)
+		}
 
-	res.TeamComp.ComputeKeyAttributes()
+		res.DamageTakenPercentPerDeath = ps.damageTakenPercentTotal / math.Max(float64(ps.deaths), 1)
 
-	timeManagement.AddSupplemental(res.TeamFights)
-	res.TimeDetail = timeManagement.AllFrames()
-	res.UsefulPercent = UsefulPercent(res.TimeDetail, state.MatchDuration)
+		res.MapCoverages = ps.coverageMap.Percents()
+		res.AreaCoverages = ps.coverageMap.AreaPercents()
 
-	res.Combos = comboAnalysis.ComboSummary()
-	res.ComboDamagePerMinute = comboAnalysis.ComboDamagePerMinute(state.MatchDuration)
+		res.TeamComp.ComputeKeyAttributes()
 
-	res.RolePosition = rolePositions[p.ParticipantID]
+		if timed {
+			ps.timeManagement.AddSupplemental(res.TeamFights)
+			res.TimeDetail = ps.timeManagement.AllFrames()
+			res.UsefulPercent = UsefulPercent(res.TimeDetail, state.MatchDuration)
+		}
 
-	return &res, nil
+		analyses, err := DefaultRegistry.Run(&Match{
+			Baseview:      bv,
+			Index:         index,
+			Geometry:      geometry,
+			Participant:   ps.p,
+			ByPID:         byPID,
+			MatchDuration: state.MatchDuration,
+			Stats:         res,
+			cache:         cache,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		res.Analyses = analyses
+
+		// combos were computed here before they had an analyzer, and are still
+		// stats columns, which are their only copy in the stats.
+		for _, r := range analyses {
+			if combos, ok := r.Result.(*ComboResult); ok {
+				res.Combos = combos.Combos
+				res.ComboDamagePerMinute = combos.DamagePerMinute
+				r.Result = nil
+			}
+		}
+		stats[i] = res
+	}
+	return stats, rolePositions, nil
 }
 
 func buildPIDMap(participants []baseview.Participant, summonerID int64) (map[int64]*baseview.Participant, *baseview.Participant) {
@@ -383,7 +540,7 @@ func CalculateRolePosition(minionStatTracker *MinionStatTracker, buddyFinder *Bu
 	// We should have 8 unique assignments (no overlaps). If there are overlaps, then it's an error
 	if _, found := roles[-1]; found || len(roles) != 8 {
 		// These games are really unstructured, and are typically low level games where people don't know about roles
-		return roles, fmt.Errorf("error in role position calculation. expected 8 roles, but only got %d %v", len(roles), roles)
+		return roles, &RolePositionError{Roles: roles}
 	}
 	// Fill in the last 2 roles as "top".
 	for i := int64(1); i <= 10; i++ {
@@ -498,6 +655,12 @@ func AverageAdvancedStats(advancedStats []AdvancedStats) AdvancedStats {
 		for stage, coverage := range adv.MapCoverages {
 			average.MapCoverages[stage] += coverage
 		}
+		if average.AreaCoverages == nil {
+			average.AreaCoverages = map[string]float64{}
+		}
+		for stage, coverage := range adv.AreaCoverages {
+			average.AreaCoverages[stage] += coverage
+		}
 
 		// Useful Percent
 		if average.UsefulPercent == nil {
@@ -578,7 +741,7 @@ func AverageAdvancedStats(advancedStats []AdvancedStats) AdvancedStats {
 		}
 	}
 
-	for _, floatMap := range []map[string]float64{average.MapCoverages, average.UsefulPercent, average.LiveWardsAverage} {
+	for _, floatMap := range []map[string]float64{average.MapCoverages, average.AreaCoverages, average.UsefulPercent, average.LiveWardsAverage} {
 		for k, _ := range floatMap {
 			floatMap[k] /= float64(len(advancedStats))
 		}
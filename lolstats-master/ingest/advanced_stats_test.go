@@ -29,6 +29,8 @@ func TestComputeAdvancedStats(t *testing.T) {
 
 	advancedStats, err := ComputeAdvanced(bv, summonerID, matchID, "NA1")
 	is.Nil(err)
+	// the golden predates the analyzers, whose results have their own tests.
+	advancedStats.Analyses = nil
 
 	if rewriteGolden {
 		is.Nil(json.WriteIndent(fmt.Sprintf("../testdata/%d-NA1-%d.advanced.json", matchID, summonerID), advancedStats, 0600))
@@ -122,3 +124,33 @@ func TestComputeAverageAdvancedStats(t *testing.T) {
 		t.Errorf(testutil.DiffLines(goldenData, ourData, t))
 	}
 }
+
+func TestComputeAdvancedMaps(t *testing.T) {
+	is := is.New(t)
+
+	bv := randomMatch(3)
+	for i := range bv.Participants {
+		bv.Participants[i].SummonerID = 1000 + bv.Participants[i].ParticipantID
+		bv.Participants[i].ChampionID = 1
+	}
+
+	bv.MapID = baseview.MapHowlingAbyss
+	aram, err := ComputeAdvanced(*bv, 1001, 1, "NA1")
+	is.NotErr(err)
+	is.Equal(baseview.MapHowlingAbyss, aram.MapID)
+	is.Equal("", aram.RolePosition)
+	is.Nil(aram.WardLives)
+	is.Nil(aram.TimeDetail)
+	is.Nil(aram.UsefulPercent)
+	is.NotNil(aram.TeamFights)
+	is.True(aram.AreaCoverages["full"] > 0)
+
+	// roles are computed on summoner's rift, and fail without minions.
+	bv.MapID = 0
+	_, err = ComputeAdvanced(*bv, 1001, 1, "NA1")
+	is.Err(err)
+
+	bv.MapID = 10
+	_, err = ComputeAdvanced(*bv, 1001, 1, "NA1")
+	is.Err(err)
+}
@@ -0,0 +1,262 @@
+package ingest
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/VantageSports/common/log"
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// An Analyzer computes one analysis of a summoner's match. Analyzers are
+// registered (see Register) instead of being called by ComputeAdvanced, so
+// that adding an analysis doesn't mean editing ComputeAdvanced or
+// AdvancedStats: their results are in AdvancedStats.Analyses.
+type Analyzer interface {
+	// Name identifies the analysis, e.g. "combos".
+	Name() string
+	// Version is incremented whenever the analysis changes its results, so
+	// that results of different versions aren't compared.
+	Version() int
+	// Maps are the ids of the maps the analysis supports, or nil for every
+	// map with geometry.
+	Maps() []int64
+	// Roles are the roles the analysis supports, or nil for every role
+	// (including an unknown one).
+	Roles() []baseview.RolePosition
+	// Dependencies are the names of the analyzers whose results Analyze
+	// uses. They always run first.
+	Dependencies() []string
+	// Analyze returns the result of the analysis, which is marshalled to
+	// json. deps are the results of the dependencies, by name.
+	Analyze(m *Match, deps map[string]interface{}) (interface{}, error)
+}
+
+// Match is what analyzers analyze: a summoner's match and the advanced stats
+// computed before the analyzers run.
+type Match struct {
+	Baseview    *baseview.Baseview
+	Index       *baseview.Index
+	Geometry    *baseview.Map
+	Participant *baseview.Participant
+	// ByPID are the participants, by participant id.
+	ByPID         map[int64]*baseview.Participant
+	MatchDuration float64
+	Stats         *AdvancedStats
+
+	// cache holds the results of match-wide work (see shared), and is the
+	// same for every participant whose stats are computed together.
+	cache map[string]interface{}
+}
+
+// shared returns the result of the match-wide work of the key, calling f only
+// if no analyzer has done it yet for another participant of the match. The
+// result must not depend on the participant, nor be modified.
+func (m *Match) shared(key string, f func() interface{}) interface{} {
+	if m.cache == nil {
+		m.cache = map[string]interface{}{}
+	}
+	if v, found := m.cache[key]; found {
+		return v
+	}
+	v := f()
+	m.cache[key] = v
+	return v
+}
+
+// AnalysisResult is the result of an analyzer, or why it has none.
+type AnalysisResult struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	// Result is omitted from the stats of analyses that have their own
+	// columns (combos), so they aren't stored twice.
+	Result interface{} `json:"result,omitempty"`
+	// Error is set if the analyzer failed (or panicked).
+	Error string `json:"error,omitempty"`
+	// Skipped is set if the analyzer didn't run, e.g. on an unsupported map.
+	Skipped string  `json:"skipped,omitempty"`
+	Seconds float64 `json:"seconds"`
+}
+
+// Analyses are the results of the analyzers, by AnalysisKey.
+type Analyses map[string]*AnalysisResult
+
+// AnalysisKey returns the key of the results of a version of an analysis,
+// e.g. "combos/v1".
+func AnalysisKey(name string, version int) string {
+	return fmt.Sprintf("%s/v%d", name, version)
+}
+
+// Get returns the result of the named analysis (of any version), or nil if it
+// has none.
+func (a Analyses) Get(name string) interface{} {
+	for _, r := range a {
+		if r.Name == name {
+			return r.Result
+		}
+	}
+	return nil
+}
+
+// Registry is a set of analyzers, which it runs in order of their
+// dependencies.
+type Registry struct {
+	analyzers map[string]Analyzer
+}
+
+func NewRegistry() *Registry {
+	return &Registry{analyzers: map[string]Analyzer{}}
+}
+
+// DefaultRegistry holds the analyzers that ComputeAdvanced runs.
+var DefaultRegistry = NewRegistry()
+
+// Register adds an analyzer to the default registry. It panics if the name is
+// already taken, so it is meant to be called from init.
+func Register(a Analyzer) {
+	if err := DefaultRegistry.Register(a); err != nil {
+		panic(err)
+	}
+}
+
+// Register adds an analyzer, unless one of the same name already exists.
+func (r *Registry) Register(a Analyzer) error {
+	if _, found := r.analyzers[a.Name()]; found {
+		return fmt.Errorf("analyzer %s registered twice", a.Name())
+	}
+	r.analyzers[a.Name()] = a
+	return nil
+}
+
+// Order returns the analyzers so that each comes after its dependencies (and
+// otherwise by name). It returns an error if a dependency isn't registered,
+// or the dependencies have a cycle.
+func (r *Registry) Order() ([]Analyzer, error) {
+	names := []string{}
+	for name, a := range r.analyzers {
+		for _, dep := range a.Dependencies() {
+			if _, found := r.analyzers[dep]; !found {
+				return nil, fmt.Errorf("analyzer %s depends on %s, which isn't registered", name, dep)
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ordered := []Analyzer{}
+	done := map[string]bool{}
+	for len(ordered) < len(names) {
+		progress := false
+		for _, name := range names {
+			if done[name] || !r.ready(name, done) {
+				continue
+			}
+			ordered = append(ordered, r.analyzers[name])
+			done[name] = true
+			progress = true
+		}
+		if !progress {
+			cycle := []string{}
+			for _, name := range names {
+				if !done[name] {
+					cycle = append(cycle, name)
+				}
+			}
+			return nil, fmt.Errorf("analyzers have a dependency cycle: %v", cycle)
+		}
+	}
+	return ordered, nil
+}
+
+// ready returns true if every dependency of the named analyzer is done.
+func (r *Registry) ready(name string, done map[string]bool) bool {
+	for _, dep := range r.analyzers[name].Dependencies() {
+		if !done[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// Run runs the analyzers on the match. An analyzer that fails doesn't stop the
+// others, except those that depend on it, which are skipped. Run only returns
+// an error if the analyzers can't be ordered.
+func (r *Registry) Run(m *Match) (Analyses, error) {
+	ordered, err := r.Order()
+	if err != nil {
+		return nil, err
+	}
+
+	res := Analyses{}
+	results := map[string]interface{}{}
+	for _, a := range ordered {
+		ar := &AnalysisResult{Name: a.Name(), Version: a.Version()}
+		res[AnalysisKey(a.Name(), a.Version())] = ar
+
+		ar.Skipped = skipReason(a, m, results)
+		if ar.Skipped != "" {
+			continue
+		}
+		deps := map[string]interface{}{}
+		for _, dep := range a.Dependencies() {
+			deps[dep] = results[dep]
+		}
+
+		start := time.Now()
+		result, err := analyze(a, m, deps)
+		ar.Seconds = time.Since(start).Seconds()
+		if err != nil {
+			ar.Error = err.Error()
+			log.Warning(fmt.Sprintf("analyzer %s of summoner %d failed: %v", a.Name(), m.Stats.SummonerID, err))
+			continue
+		}
+		ar.Result = result
+		results[a.Name()] = result
+	}
+	return res, nil
+}
+
+// skipReason returns why the analyzer can't run on the match, or "" if it can.
+func skipReason(a Analyzer, m *Match, results map[string]interface{}) string {
+	if maps := a.Maps(); maps != nil && !containsInt64(maps, m.Geometry.ID) {
+		return fmt.Sprintf("unsupported map %d", m.Geometry.ID)
+	}
+	if roles := a.Roles(); roles != nil && !containsRole(roles, m.Stats.RolePosition) {
+		return fmt.Sprintf("unsupported role %q", m.Stats.RolePosition)
+	}
+	for _, dep := range a.Dependencies() {
+		if _, found := results[dep]; !found {
+			return fmt.Sprintf("no result from %s", dep)
+		}
+	}
+	return ""
+}
+
+// analyze runs the analyzer, turning a panic into an error.
+func analyze(a Analyzer, m *Match, deps map[string]interface{}) (result interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			result, err = nil, fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return a.Analyze(m, deps)
+}
+
+func containsInt64(values []int64, v int64) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsRole(roles []baseview.RolePosition, role baseview.RolePosition) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
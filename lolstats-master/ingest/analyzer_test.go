@@ -0,0 +1,159 @@
+package ingest
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/tylerb/is.v1"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// testAnalyzer returns the result of its analyze func, or the names of its
+// dependencies' results if it has none.
+type testAnalyzer struct {
+	name    string
+	maps    []int64
+	roles   []baseview.RolePosition
+	deps    []string
+	analyze func() (interface{}, error)
+}
+
+func (a *testAnalyzer) Name() string                   { return a.name }
+func (a *testAnalyzer) Version() int                   { return 2 }
+func (a *testAnalyzer) Maps() []int64                  { return a.maps }
+func (a *testAnalyzer) Roles() []baseview.RolePosition { return a.roles }
+func (a *testAnalyzer) Dependencies() []string         { return a.deps }
+
+func (a *testAnalyzer) Analyze(m *Match, deps map[string]interface{}) (interface{}, error) {
+	if a.analyze != nil {
+		return a.analyze()
+	}
+	res := a.name + ":"
+	for _, dep := range a.deps {
+		res += " " + deps[dep].(string)
+	}
+	return res, nil
+}
+
+func testMatch(mapID int64, role baseview.RolePosition) *Match {
+	return &Match{
+		Geometry: baseview.MapByID(mapID),
+		Stats:    &AdvancedStats{SummonerID: 11, RolePosition: role},
+	}
+}
+
+func analyzerNames(analyzers []Analyzer) []string {
+	names := []string{}
+	for _, a := range analyzers {
+		names = append(names, a.Name())
+	}
+	return names
+}
+
+func TestRegistryOrder(t *testing.T) {
+	is := is.New(t)
+
+	r := NewRegistry()
+	is.NotErr(r.Register(&testAnalyzer{name: "c", deps: []string{"d", "a"}}))
+	is.NotErr(r.Register(&testAnalyzer{name: "d", deps: []string{"b"}}))
+	is.NotErr(r.Register(&testAnalyzer{name: "b"}))
+	is.NotErr(r.Register(&testAnalyzer{name: "a"}))
+	is.Err(r.Register(&testAnalyzer{name: "a"}))
+
+	ordered, err := r.Order()
+	is.NotErr(err)
+	is.Equal([]string{"a", "b", "d", "c"}, analyzerNames(ordered))
+
+	res, err := r.Run(testMatch(baseview.MapSummonersRift, ""))
+	is.NotErr(err)
+	is.Equal(4, len(res))
+	is.Equal("c: d: b: a:", res.Get("c"))
+	is.Equal("c", res[AnalysisKey("c", 2)].Name)
+	is.Equal(2, res["c/v2"].Version)
+
+	// dependencies must be registered, and can't be circular.
+	is.NotErr(r.Register(&testAnalyzer{name: "e", deps: []string{"f"}}))
+	_, err = r.Order()
+	is.Err(err)
+	is.NotErr(r.Register(&testAnalyzer{name: "f", deps: []string{"g"}}))
+	is.NotErr(r.Register(&testAnalyzer{name: "g", deps: []string{"e"}}))
+	_, err = r.Order()
+	is.Err(err)
+	_, err = r.Run(testMatch(baseview.MapSummonersRift, ""))
+	is.Err(err)
+}
+
+func TestRegistryRun(t *testing.T) {
+	is := is.New(t)
+
+	r := NewRegistry()
+	is.NotErr(r.Register(&testAnalyzer{name: "ok"}))
+	is.NotErr(r.Register(&testAnalyzer{name: "panics", analyze: func() (interface{}, error) {
+		var m map[string]int
+		m["boom"]++
+		return nil, nil
+	}}))
+	is.NotErr(r.Register(&testAnalyzer{name: "fails", analyze: func() (interface{}, error) {
+		return nil, errors.New("no good")
+	}}))
+	is.NotErr(r.Register(&testAnalyzer{name: "needs_panics", deps: []string{"panics", "ok"}}))
+	is.NotErr(r.Register(&testAnalyzer{name: "rift", maps: []int64{baseview.MapSummonersRift}}))
+	is.NotErr(r.Register(&testAnalyzer{name: "mid", roles: []baseview.RolePosition{baseview.RoleMid}}))
+
+	res, err := r.Run(testMatch(baseview.MapHowlingAbyss, baseview.RoleTop))
+	is.NotErr(err)
+	is.Equal(6, len(res))
+
+	// a failing analyzer only loses its own result, and its dependents'.
+	is.Equal("ok:", res.Get("ok"))
+	is.Nil(res.Get("panics"))
+	is.Equal("panic: assignment to entry in nil map", res["panics/v2"].Error)
+	is.Equal("no good", res["fails/v2"].Error)
+	is.Equal("no result from panics", res["needs_panics/v2"].Skipped)
+
+	is.Equal("unsupported map 12", res["rift/v2"].Skipped)
+	is.Equal(`unsupported role "top"`, res["mid/v2"].Skipped)
+
+	res, err = r.Run(testMatch(baseview.MapSummonersRift, baseview.RoleMid))
+	is.NotErr(err)
+	is.Equal("rift:", res.Get("rift"))
+	is.Equal("mid:", res.Get("mid"))
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	is := is.New(t)
+
+	ordered, err := DefaultRegistry.Order()
+	is.NotErr(err)
+	is.True(len(ordered) > 0)
+	is.ShouldPanic(func() { Register(comboAnalyzer{}) })
+}
+
+func TestCombosStoredOnce(t *testing.T) {
+	is := is.New(t)
+
+	s, err := ComputeAdvanced(*advancedMatch(1), 101, 1, "NA1")
+	is.NotErr(err)
+	r := s.Analyses[AnalysisKey("combos", 1)]
+	is.NotNil(r)
+	is.Equal("", r.Error)
+	is.Nil(r.Result)
+	is.NotNil(s.Combos)
+}
+
+func TestMatchShared(t *testing.T) {
+	is := is.New(t)
+
+	calls := 0
+	f := func() interface{} { calls++; return calls }
+	cache := map[string]interface{}{}
+	for i := 0; i < 3; i++ {
+		m := &Match{cache: cache}
+		is.Equal(1, m.shared("work", f))
+	}
+	is.Equal(1, calls)
+
+	// a match on its own has a cache of its own.
+	is.Equal(2, (&Match{}).shared("work", f))
+}
@@ -139,6 +139,49 @@ type comboAnalysis struct {
 	Participant *baseview.Participant
 }
 
+func init() {
+	Register(comboAnalyzer{})
+}
+
+// ComboResult is the result of the combos analyzer.
+type ComboResult struct {
+	// Combos are sorted by damage dealt, most first.
+	Combos          []*ComboSummary `json:"combos"`
+	DamagePerMinute float64         `json:"damage_per_minute"`
+}
+
+// comboAnalyzer finds the summoner's combos: abilities, summoner spells and
+// basic attacks used in quick succession that damaged enemy champions.
+type comboAnalyzer struct{}
+
+func (comboAnalyzer) Name() string                   { return "combos" }
+func (comboAnalyzer) Version() int                   { return 1 }
+func (comboAnalyzer) Maps() []int64                  { return nil }
+func (comboAnalyzer) Roles() []baseview.RolePosition { return nil }
+func (comboAnalyzer) Dependencies() []string         { return nil }
+
+func (comboAnalyzer) Analyze(m *Match, deps map[string]interface{}) (interface{}, error) {
+	pid := m.Participant.ParticipantID
+	ca := NewComboAnalysis(m.Participant)
+	for _, e := range m.Baseview.Events {
+		switch t := e.(type) {
+		case *baseview.Attack:
+			if t.AttackerID == pid {
+				ca.AddAttack(t)
+			}
+		case *baseview.Damage:
+			// only damage to other champions counts.
+			if t.AttackerID == pid && t.VictimID != pid && m.ByPID[t.VictimID] != nil {
+				ca.AddDamage(t)
+			}
+		}
+	}
+	return &ComboResult{
+		Combos:          ca.ComboSummary(),
+		DamagePerMinute: ca.ComboDamagePerMinute(m.MatchDuration),
+	}, nil
+}
+
 func NewComboAnalysis(p *baseview.Participant) *comboAnalysis {
 	return &comboAnalysis{
 		Combos:       []*combo{},
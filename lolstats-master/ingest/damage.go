@@ -50,3 +50,22 @@ type DamageSummary struct {
 	// Deaths is total deaths
 	Deaths int64 `json:"deaths,omitempty"`
 }
+
+// Merge adds the damage and deaths of other to the map.
+func (dm DamageMap) Merge(other DamageMap) {
+	for champKey, stageSummary := range other {
+		for stage, summary := range stageSummary {
+			if dm[champKey] == nil {
+				dm[champKey] = map[string]*DamageSummary{}
+			}
+			sum := dm[champKey][stage]
+			if sum == nil {
+				sum = &DamageSummary{}
+				dm[champKey][stage] = sum
+			}
+			sum.DamageTotal += summary.DamageTotal
+			sum.DamagePercent += summary.DamagePercent
+			sum.Deaths += summary.Deaths
+		}
+	}
+}
@@ -33,3 +33,25 @@ func TestDamageMap(t *testing.T) {
 	is.Equal(1000, d["95"]["late"].DamageTotal)
 	is.Equal(0, d["95"]["late"].Deaths)
 }
+
+func TestDamageMapMerge(t *testing.T) {
+	is := is.New(t)
+
+	d := DamageMap{}
+	d.AddDamage(123, 50.0, 50, 5)
+	other := DamageMap{}
+	other.AddDamage(123, 60.0, 100, 3)
+	other.AddDeath(123, 60.0)
+	other.AddDamage(95, 700.0, 500, 10)
+
+	d.Merge(other)
+	is.Equal(150, d["123"]["early"].DamageTotal)
+	is.Equal(8, d["123"]["early"].DamagePercent)
+	is.Equal(1, d["123"]["early"].Deaths)
+	is.Equal(500, d["95"]["mid"].DamageTotal)
+
+	// the merged summaries are copies.
+	d.Merge(other)
+	is.Equal(1000, d["95"]["mid"].DamageTotal)
+	is.Equal(500, other["95"]["mid"].DamageTotal)
+}
@@ -0,0 +1,236 @@
+package ingest
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// Kinds of heatmap.
+const (
+	HeatmapPositions = "positions"
+	HeatmapDeaths    = "deaths"
+	HeatmapKills     = "kills"
+	HeatmapWards     = "wards"
+)
+
+// HeatmapGridSize is the number of rows (and columns) of the grids that the
+// advanced stats ingester builds heatmaps on.
+const HeatmapGridSize = 64
+
+// heatmapAllRoles is the role of the grids that count every role. Like
+// coverage maps, the grids of every game stage are "full".
+const heatmapAllRoles = "all"
+
+// HeatmapKey returns the key of the grid of the kind (e.g. HeatmapDeaths), for
+// the role and game stage. An empty role or stage is every role or stage.
+func HeatmapKey(kind string, role baseview.RolePosition, stage string) string {
+	if role == "" {
+		role = heatmapAllRoles
+	}
+	if stage == "" {
+		stage = "full"
+	}
+	return fmt.Sprintf("%s/%s/%s", kind, role, stage)
+}
+
+// A Grid counts things (like positions) in each square of a grid over a map.
+// Counts are by row, from the bottom left of the map.
+type Grid struct {
+	Counts []float64 `json:"counts"`
+	Total  float64   `json:"total"`
+}
+
+// Normalized returns the fraction of the total in each square.
+func (g *Grid) Normalized() []float64 {
+	res := make([]float64, len(g.Counts))
+	for i, c := range g.Counts {
+		if g.Total > 0 {
+			res[i] = c / g.Total
+		}
+	}
+	return res
+}
+
+// Heatmaps accumulate where a summoner was, died, killed and placed wards
+// over many matches (on the same map), by role and game stage. Unlike a
+// CoverageMap, which only tracks whether a square was visited in one match,
+// heatmaps count how often.
+type Heatmaps struct {
+	SummonerID int64            `json:"summoner_id"`
+	MapID      int64            `json:"map_id"`
+	GridSize   int              `json:"grid_size"`
+	Matches    int              `json:"matches"`
+	Grids      map[string]*Grid `json:"grids"`
+
+	geometry *baseview.Map
+}
+
+// NewHeatmaps returns empty heatmaps of a gridSize x gridSize grid over the
+// map.
+func NewHeatmaps(summonerID, mapID int64, gridSize int) (*Heatmaps, error) {
+	geometry := baseview.MapByID(mapID)
+	if geometry == nil {
+		return nil, fmt.Errorf("unsupported map: %d", mapID)
+	}
+	if gridSize <= 0 {
+		return nil, fmt.Errorf("invalid grid size: %d", gridSize)
+	}
+	return &Heatmaps{
+		SummonerID: summonerID,
+		MapID:      geometry.ID,
+		GridSize:   gridSize,
+		Grids:      map[string]*Grid{},
+		geometry:   geometry,
+	}, nil
+}
+
+// Grid returns the grid of the kind, role and stage (see HeatmapKey), or nil
+// if nothing was counted in it.
+func (h *Heatmaps) Grid(kind string, role baseview.RolePosition, stage string) *Grid {
+	return h.Grids[HeatmapKey(kind, role, stage)]
+}
+
+// square returns the index of the square the position is in. Positions off
+// the map are counted in the nearest square.
+func (h *Heatmaps) square(p baseview.Position) int {
+	b := h.geometry.Bounds
+	square := func(v, min, max float64) int {
+		i := int((v - min) / (max - min) * float64(h.GridSize))
+		return int(math.Min(math.Max(float64(i), 0), float64(h.GridSize-1)))
+	}
+	return square(p.Y, b.YMin, b.YMax)*h.GridSize + square(p.X, b.XMin, b.XMax)
+}
+
+// add counts the position in the grids of the kind for the role (and every
+// role), and the game stage at the time (and every stage).
+func (h *Heatmaps) add(kind string, role baseview.RolePosition, seconds float64, p baseview.Position) {
+	square := h.square(p)
+	roles := []baseview.RolePosition{""}
+	if role != "" {
+		roles = append(roles, role)
+	}
+	for _, r := range roles {
+		for _, stage := range []string{gameStage(seconds), "full"} {
+			key := HeatmapKey(kind, r, stage)
+			g, found := h.Grids[key]
+			if !found {
+				g = &Grid{Counts: make([]float64, h.GridSize*h.GridSize)}
+				h.Grids[key] = g
+			}
+			g.Counts[square]++
+			g.Total++
+		}
+	}
+}
+
+// Add accumulates a match, in which the summoner played the role (or "" if it
+// isn't known). Positions are counted from the summoner's state updates while
+// alive, and kills where the victim died, with kill credit given like in
+// ComputeAdvanced.
+func (h *Heatmaps) Add(bv *baseview.Baseview, role baseview.RolePosition) error {
+	if m := baseview.MapByID(bv.MapID); m == nil || m.ID != h.MapID {
+		return fmt.Errorf("match is on map %d, not %d", bv.MapID, h.MapID)
+	}
+	_, p := buildPIDMap(bv.Participants, h.SummonerID)
+	if p == nil {
+		return fmt.Errorf("no participant found with summoner id %d", h.SummonerID)
+	}
+
+	lastChampDamage := map[int64]*baseview.Damage{}
+	for _, e := range bv.Events {
+		switch t := e.(type) {
+		case *baseview.StateUpdate:
+			if t.ParticipantID == p.ParticipantID && t.Health > 0 {
+				h.add(HeatmapPositions, role, t.Seconds(), t.Position)
+			}
+		case *baseview.WardPlaced:
+			if t.ParticipantID == p.ParticipantID {
+				h.add(HeatmapWards, role, t.Seconds(), t.Position)
+			}
+		case *baseview.Damage:
+			if t.AttackerType == baseview.ActorHero && t.VictimType == baseview.ActorHero {
+				lastChampDamage[t.VictimID] = t
+			}
+		case *baseview.Death:
+			if t.VictimID == p.ParticipantID {
+				h.add(HeatmapDeaths, role, t.Seconds(), t.Position)
+				continue
+			}
+			last := lastChampDamage[t.VictimID]
+			if last != nil && last.AttackerID == p.ParticipantID && t.Seconds()-last.Seconds() < killCreditSeconds {
+				h.add(HeatmapKills, role, t.Seconds(), t.Position)
+			}
+		}
+	}
+	h.Matches++
+	return nil
+}
+
+// Merge adds the counts of other heatmaps of the same map and grid size (e.g.
+// those of single matches).
+func (h *Heatmaps) Merge(other *Heatmaps) error {
+	if other.MapID != h.MapID || other.GridSize != h.GridSize {
+		return fmt.Errorf("can't merge heatmaps of map %d (%d squares) into map %d (%d squares)",
+			other.MapID, other.GridSize, h.MapID, h.GridSize)
+	}
+	for key, og := range other.Grids {
+		if len(og.Counts) != h.GridSize*h.GridSize {
+			return fmt.Errorf("grid %s has %d squares", key, len(og.Counts))
+		}
+		g, found := h.Grids[key]
+		if !found {
+			g = &Grid{Counts: make([]float64, len(og.Counts))}
+			h.Grids[key] = g
+		}
+		for i, c := range og.Counts {
+			g.Counts[i] += c
+		}
+		g.Total += og.Total
+	}
+	h.Matches += other.Matches
+	return nil
+}
+
+// heatColor returns the color of a square with the value (from 0 to 1): from
+// transparent through red and yellow to white.
+func heatColor(v float64) color.NRGBA {
+	if v <= 0 {
+		return color.NRGBA{}
+	}
+	v = math.Sqrt(math.Min(v, 1))
+	channel := func(from, to float64) uint8 {
+		return uint8(255 * math.Min(math.Max((v-from)/(to-from), 0), 1))
+	}
+	return color.NRGBA{R: channel(0, 0.4), G: channel(0.4, 0.8), B: channel(0.8, 1), A: channel(-0.25, 0.25)}
+}
+
+// WritePNG draws the grid with the key as a PNG of size x size pixels, with
+// each square colored by its count relative to the largest.
+func (h *Heatmaps) WritePNG(w io.Writer, key string, size int) error {
+	g := h.Grids[key]
+	if g == nil {
+		return fmt.Errorf("no heatmap %s", key)
+	}
+	max := 0.0
+	for _, c := range g.Counts {
+		max = math.Max(max, c)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		row := (size - 1 - y) * h.GridSize / size
+		for x := 0; x < size; x++ {
+			c := g.Counts[row*h.GridSize+x*h.GridSize/size]
+			if max > 0 {
+				img.SetNRGBA(x, y, heatColor(c/max))
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
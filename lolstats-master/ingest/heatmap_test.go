@@ -0,0 +1,125 @@
+package ingest
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"gopkg.in/tylerb/is.v1"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+func heatmapEvent(e baseview.Event, eventType string, seconds float64) baseview.Event {
+	e.SetType(eventType)
+	e.SetSeconds(seconds)
+	return e
+}
+
+// heatmapBaseview returns a match in which summoner 11 (participant 1) walks
+// around the bottom left corner, places a ward, kills participant 6 and is
+// killed by participant 7 in the mid game.
+func heatmapBaseview() *baseview.Baseview {
+	bv := &baseview.Baseview{Version: baseview.CurrentVersion, MapID: baseview.MapSummonersRift}
+	for _, id := range []int64{1, 6, 7} {
+		bv.Participants = append(bv.Participants, baseview.Participant{ParticipantID: id, SummonerID: 10 + id})
+	}
+	corner := baseview.Position{X: 100, Y: 100}
+	river := baseview.Position{X: 8000, Y: 8000}
+	bv.Events = []baseview.Event{
+		heatmapEvent(&baseview.StateUpdate{ParticipantID: 1, Health: 500, Position: corner}, "state_update", 10),
+		heatmapEvent(&baseview.StateUpdate{ParticipantID: 1, Health: 500, Position: corner}, "state_update", 20),
+		heatmapEvent(&baseview.StateUpdate{ParticipantID: 6, Health: 500, Position: corner}, "state_update", 20),
+		heatmapEvent(&baseview.WardPlaced{ParticipantID: 1, WardID: 1, Position: river}, "ward_placed", 30),
+		heatmapEvent(&baseview.Damage{AttackerID: 1, AttackerType: baseview.ActorHero, VictimID: 6, VictimType: baseview.ActorHero}, "damage", 40),
+		heatmapEvent(&baseview.Death{VictimID: 6, Position: river}, "death", 45),
+		heatmapEvent(&baseview.Damage{AttackerID: 1, AttackerType: baseview.ActorHero, VictimID: 7, VictimType: baseview.ActorHero}, "damage", 50),
+		// too long after the damage for participant 1 to get the kill.
+		heatmapEvent(&baseview.Death{VictimID: 7, Position: river}, "death", 70),
+		heatmapEvent(&baseview.StateUpdate{ParticipantID: 1, Health: 500, Position: river}, "state_update", 700),
+		heatmapEvent(&baseview.Death{VictimID: 1, Position: river}, "death", 710),
+		heatmapEvent(&baseview.StateUpdate{ParticipantID: 1, Health: 0, Position: river}, "state_update", 720),
+	}
+	return bv
+}
+
+func TestHeatmaps(t *testing.T) {
+	is := is.New(t)
+
+	h, err := NewHeatmaps(11, baseview.MapSummonersRift, 4)
+	is.NotErr(err)
+	is.NotErr(h.Add(heatmapBaseview(), baseview.RoleMid))
+	is.Equal(1, h.Matches)
+
+	// positions while alive, in both the role's and every role's grids.
+	for _, role := range []baseview.RolePosition{"", baseview.RoleMid} {
+		g := h.Grid(HeatmapPositions, role, "full")
+		is.NotNil(g)
+		is.Equal(3.0, g.Total)
+		is.Equal(2.0, g.Counts[0])
+		is.Equal(1.0, g.Counts[2*4+2])
+	}
+	is.Nil(h.Grid(HeatmapPositions, baseview.RoleTop, "full"))
+	is.Equal(2.0, h.Grid(HeatmapPositions, "", "early").Total)
+	is.Equal(1.0, h.Grid(HeatmapPositions, "", "mid").Total)
+	is.Nil(h.Grid(HeatmapPositions, "", "late"))
+
+	is.Equal(1.0, h.Grid(HeatmapWards, "", "").Counts[2*4+2])
+	is.Equal(1.0, h.Grid(HeatmapKills, "", "").Total)
+	is.Equal(1.0, h.Grid(HeatmapDeaths, baseview.RoleMid, "mid").Total)
+	is.Nil(h.Grid(HeatmapDeaths, "", "early"))
+
+	norm := h.Grid(HeatmapPositions, "", "").Normalized()
+	is.Equal(2.0/3, norm[0])
+	is.Equal(0.0, norm[1])
+
+	// an unknown role only counts in every role's grids.
+	other, err := NewHeatmaps(11, baseview.MapSummonersRift, 4)
+	is.NotErr(err)
+	is.NotErr(other.Add(heatmapBaseview(), ""))
+	is.Nil(other.Grid(HeatmapPositions, baseview.RoleMid, ""))
+
+	is.NotErr(h.Merge(other))
+	is.Equal(2, h.Matches)
+	is.Equal(6.0, h.Grid(HeatmapPositions, "", "").Total)
+	is.Equal(4.0, h.Grid(HeatmapPositions, "", "").Counts[0])
+	is.Equal(3.0, h.Grid(HeatmapPositions, baseview.RoleMid, "").Total)
+}
+
+func TestHeatmapsErrors(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewHeatmaps(11, 10, 4)
+	is.Err(err)
+	_, err = NewHeatmaps(11, baseview.MapSummonersRift, 0)
+	is.Err(err)
+
+	h, err := NewHeatmaps(12, baseview.MapSummonersRift, 4)
+	is.NotErr(err)
+	is.Err(h.Add(heatmapBaseview(), ""))
+
+	other, err := NewHeatmaps(11, baseview.MapSummonersRift, 8)
+	is.NotErr(err)
+	is.Err(h.Merge(other))
+}
+
+func TestHeatmapPNG(t *testing.T) {
+	is := is.New(t)
+
+	h, err := NewHeatmaps(11, baseview.MapSummonersRift, 4)
+	is.NotErr(err)
+	is.NotErr(h.Add(heatmapBaseview(), ""))
+
+	buf := &bytes.Buffer{}
+	is.Err(h.WritePNG(buf, HeatmapKey(HeatmapPositions, baseview.RoleTop, ""), 16))
+	is.NotErr(h.WritePNG(buf, HeatmapKey(HeatmapPositions, "", ""), 16))
+	img, err := png.Decode(buf)
+	is.NotErr(err)
+	is.Equal(16, img.Bounds().Dx())
+
+	// the bottom left square is the hottest, and the top right is empty.
+	_, _, _, hot := img.At(0, 15).RGBA()
+	_, _, _, cold := img.At(15, 0).RGBA()
+	is.True(hot > 0)
+	is.Equal(uint32(0), cold)
+}
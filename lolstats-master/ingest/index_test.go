@@ -0,0 +1,138 @@
+package ingest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/VantageSports/lolstats/baseview"
+
+	"gopkg.in/tylerb/is.v1"
+)
+
+// randomMatch returns a baseview of ten participants wandering around (and
+// occasionally dying) for fifteen minutes. Participants update at slightly
+// different times, as they do in elo logs.
+func randomMatch(seed int64) *baseview.Baseview {
+	r := rand.New(rand.NewSource(seed))
+	bv := &baseview.Baseview{}
+	positions := map[int64]baseview.Position{}
+	gold := map[int64]int64{}
+	deadUntil := map[int64]float64{}
+	for id := int64(1); id <= 10; id++ {
+		bv.Participants = append(bv.Participants, baseview.Participant{ParticipantID: id})
+		positions[id] = baseview.Position{X: 7000, Y: 7000}
+	}
+
+	for tick := 0; tick < 15*60*2; tick++ {
+		for id := int64(1); id <= 10; id++ {
+			seconds := float64(tick)/2 + float64(id)/100
+			if seconds >= deadUntil[id] && r.Intn(500) == 0 {
+				d := &baseview.Death{VictimID: id, Position: positions[id]}
+				d.SetType("death")
+				d.SetSeconds(seconds)
+				bv.Events = append(bv.Events, d)
+				deadUntil[id] = seconds + 10 + float64(r.Intn(30))
+				continue
+			}
+
+			health := 500.0
+			if seconds < deadUntil[id] {
+				health = 0
+			}
+			p := positions[id]
+			p.X += float64(r.Intn(800) - 400)
+			p.Y += float64(r.Intn(800) - 400)
+			positions[id] = p
+			gold[id] += int64(r.Intn(30) - 10)
+
+			s := &baseview.StateUpdate{ParticipantID: id, Position: p, Health: health, HealthMax: 500, Gold: gold[id]}
+			s.SetType("state_update")
+			s.SetSeconds(seconds)
+			bv.Events = append(bv.Events, s)
+		}
+	}
+	return bv
+}
+
+// The index agrees with the state that team fight analysis keeps as it scans
+// the events.
+func TestIndexMatchesTeamFightState(t *testing.T) {
+	is := is.New(t)
+
+	bv := randomMatch(1)
+	x := baseview.NewIndex(bv)
+	tfa := NewTeamFightAnalysis(1, baseview.MatchDuration(bv.Events), NewWardAnalysis(), baseview.SummonersRift())
+
+	deaths := 0
+	for _, e := range bv.Events {
+		switch t := e.(type) {
+		case *baseview.StateUpdate:
+			tfa.updateRecentHistory(t.Seconds())
+			tfa.updateState(t)
+		case *baseview.Death:
+			tfa.updateDeath(t)
+			deaths++
+		}
+
+		st := tfa.RecentStates.Back().Value.(*augmentedState)
+		for id := int64(1); id <= 10; id++ {
+			is.Equal(st.IsAlive[id], x.AliveAt(id, e.Seconds()))
+
+			last := x.LastState(id, e.Seconds())
+			if last == nil {
+				is.Nil(st.ChampPositions[id])
+				continue
+			}
+			is.Equal(*st.ChampPositions[id], last.Position)
+			is.Equal(st.GoldAmount[id], last.Gold)
+		}
+	}
+	is.True(deaths > 10)
+}
+
+// The index agrees with the last state updates that the buddy finder keeps.
+func TestIndexMatchesBuddyFinder(t *testing.T) {
+	is := is.New(t)
+
+	bv := randomMatch(2)
+	x := baseview.NewIndex(bv)
+	bf := NewBuddyFinder()
+	scores := map[int64]map[int64]int64{}
+
+	for _, e := range bv.Events {
+		t, ok := e.(*baseview.StateUpdate)
+		if !ok {
+			continue
+		}
+		bf.AddStateUpdate(t)
+		if t.Seconds() < 100 || t.Seconds() > 600 {
+			continue
+		}
+
+		// every participant must have had an update in the early game.
+		seen, others := 0, []*baseview.StateUpdate{}
+		for _, id := range x.Participants() {
+			last := x.LastState(id, t.Seconds())
+			if last == nil || last.Seconds() < 100 {
+				continue
+			}
+			seen++
+			if id != t.ParticipantID && baseview.TeamID(id) == baseview.TeamID(t.ParticipantID) {
+				others = append(others, last)
+			}
+		}
+		if seen != 10 {
+			continue
+		}
+		for _, o := range others {
+			if t.Position.DistanceXY(o.Position) < 2000 {
+				if scores[t.ParticipantID] == nil {
+					scores[t.ParticipantID] = map[int64]int64{}
+				}
+				scores[t.ParticipantID][o.ParticipantID]++
+			}
+		}
+	}
+	is.True(len(scores) > 0)
+	is.Equal(bf.EarlyGameBuddyScore, scores)
+}
@@ -7,16 +7,6 @@ import (
 	"github.com/VantageSports/lolstats/baseview"
 )
 
-const (
-	// These are the max width and max height of the summoner's rift map.
-	// Technically, you can have slightly negative x and y positions, but we're
-	// ignoring that for simplicity, as it's unlikely to make a significant
-	// difference in the computation of map coverage percent.
-	// https://developer.riotgames.com/docs/game-constants
-	width  = 14870.0
-	height = 14980.0
-)
-
 // A CoverageMap is a way to track what percentage of the map has been visted
 // at least once. For each position added to the CoverageMap, we compute the
 // grid "square" referenced by the position and mark it as visited. The total
@@ -26,16 +16,30 @@ const (
 // The gridSize represents the number of grid squares in a grid. Therefore, a
 // gridSize of 12 would represent a 12x12 grid, whereas a gridSize of 1 would
 // mean 1x1.
+//
+// The grid spans from 0 to the max x and y of the map. Technically, you can
+// have slightly negative x and y positions, but we're ignoring that for
+// simplicity, as it's unlikely to make a significant difference in the
+// computation of map coverage percent.
+//
+// It also tracks which areas of the map (see baseview.Map) have been visited,
+// since a grid square can straddle a lane and the jungle.
 type CoverageMap struct {
 	// keyed by game-stage and then grid-key
 	stageToSeen map[string]map[string]int64
 	gridSize    float64
+	geometry    *baseview.Map
+
+	// keyed by game-stage and then area name
+	stageToAreas map[string]map[string]int64
 }
 
-func NewCoverageMap(gridSize int64) *CoverageMap {
+func NewCoverageMap(m *baseview.Map, gridSize int64) *CoverageMap {
 	return &CoverageMap{
-		stageToSeen: map[string]map[string]int64{},
-		gridSize:    float64(gridSize),
+		stageToSeen:  map[string]map[string]int64{},
+		gridSize:     float64(gridSize),
+		geometry:     m,
+		stageToAreas: map[string]map[string]int64{},
 	}
 }
 
@@ -43,6 +47,7 @@ func NewCoverageMap(gridSize int64) *CoverageMap {
 func (c *CoverageMap) Add(seconds float64, p baseview.Position) {
 	stage := gameStage(seconds)
 
+	width, height := c.geometry.Bounds.XMax, c.geometry.Bounds.YMax
 	x := math.Min(width, math.Max(p.X, 0))
 	xKey := int64(x / (width / c.gridSize))
 
@@ -60,6 +65,17 @@ func (c *CoverageMap) Add(seconds float64, p baseview.Position) {
 
 	c.stageToSeen[stage][gridKey]++
 	c.stageToSeen["full"][gridKey]++
+
+	area := c.geometry.AreaAt(p)
+	if area == nil {
+		return
+	}
+	for _, s := range []string{stage, "full"} {
+		if _, found := c.stageToAreas[s]; !found {
+			c.stageToAreas[s] = map[string]int64{}
+		}
+		c.stageToAreas[s][area.Name]++
+	}
 }
 
 // Percent returns the percentage of "visited" grid squares relative to the
@@ -74,3 +90,15 @@ func (c *CoverageMap) Percents() map[string]float64 {
 
 	return res
 }
+
+// AreaPercents returns the percentage of the map's areas visited.
+func (c *CoverageMap) AreaPercents() map[string]float64 {
+	res := map[string]float64{}
+	max := float64(len(c.geometry.Areas()))
+
+	for stage, areas := range c.stageToAreas {
+		res[stage] = 100.0 * float64(len(areas)) / max
+	}
+
+	return res
+}
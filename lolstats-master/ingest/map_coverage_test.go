@@ -11,8 +11,8 @@ import (
 func TestMapCoverage(t *testing.T) {
 	is := is.New(t)
 
-	mcSmall := NewCoverageMap(1)
-	mcBig := NewCoverageMap(12)
+	mcSmall := NewCoverageMap(baseview.SummonersRift(), 1)
+	mcBig := NewCoverageMap(baseview.SummonersRift(), 12)
 
 	positionTimes := []PositionTime{
 		{Seconds: 500, Position: baseview.Position{X: 500, Y: 500}},      // early
@@ -37,4 +37,10 @@ func TestMapCoverage(t *testing.T) {
 	is.Equal(100*4.0/144.0, bigPercents["full"])
 	is.Equal(100*1.0/144, bigPercents["early"])
 	is.Equal(100*2.0/144, bigPercents["late"])
+
+	areaPercents := mcBig.AreaPercents()
+	// the blue base, top lane and bot lane, and the red mid lane.
+	is.Equal(100*4.0/14, areaPercents["full"])
+	is.Equal(100*1.0/14, areaPercents["early"])
+	is.Equal(100*2.0/14, areaPercents["late"])
 }
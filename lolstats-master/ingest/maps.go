@@ -0,0 +1,38 @@
+package ingest
+
+import (
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// Analyses that only make sense on some maps.
+const (
+	// roles (and the minion and buddy tracking they are computed from, and
+	// carry focus, which depends on them) assume three lanes and a jungle.
+	analysisRoles = "roles"
+	// time management splits time between lanes, the jungle and objectives.
+	analysisTimeManagement = "time_management"
+	// wards can't be bought on howling abyss.
+	analysisWards = "wards"
+)
+
+// analysisMaps are the maps that each of the analyses above supports. Every
+// other analysis runs on any map that baseview has the geometry of.
+var analysisMaps = map[string][]int64{
+	analysisRoles:          {baseview.MapSummonersRift},
+	analysisTimeManagement: {baseview.MapSummonersRift},
+	analysisWards:          {baseview.MapSummonersRift},
+}
+
+// supports returns true if the analysis runs on the map.
+func supports(analysis string, m *baseview.Map) bool {
+	maps, found := analysisMaps[analysis]
+	if !found {
+		return true
+	}
+	for _, id := range maps {
+		if id == m.ID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,425 @@
+package ingest
+
+import (
+	"math"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+const (
+	// objectiveRadius is how close to an objective a champion (or ward) has
+	// to be to count as being at it.
+	objectiveRadius = 2000
+	// objectiveSetupSeconds is how long before a take its setup is measured.
+	objectiveSetupSeconds = 60
+	// objectiveSampleSeconds is how often positions are sampled during the
+	// setup.
+	objectiveSampleSeconds = 5
+	// objectiveContestSeconds is how long before a take an enemy at the
+	// objective makes it contested.
+	objectiveContestSeconds = 15
+)
+
+// Kinds of objectives.
+const (
+	ObjectiveDragon      = "dragon"
+	ObjectiveElderDragon = "elder_dragon"
+	ObjectiveBaron       = "baron"
+	ObjectiveHerald      = "herald"
+	ObjectiveTurret      = "turret"
+)
+
+// objectivePits are the pits (features of the map) of the epic monsters.
+var objectivePits = map[string]string{
+	ObjectiveDragon:      "dragon_pit",
+	ObjectiveElderDragon: "dragon_pit",
+	ObjectiveBaron:       "baron_pit",
+	ObjectiveHerald:      "baron_pit",
+}
+
+func init() {
+	Register(objectiveAnalyzer{})
+}
+
+// ObjectiveResult is the result of the objectives analyzer: how the summoner
+// and their team set up the objectives taken in the match.
+type ObjectiveResult struct {
+	// Takes are the objectives the summoner's team took, or contested. The
+	// other team's stats have the rest.
+	Takes  []*ObjectiveTake `json:"takes"`
+	Player ObjectivePlayer  `json:"player"`
+	Team   ObjectiveTeam    `json:"team"`
+}
+
+// ObjectiveTake is an epic monster or turret taken by a team.
+type ObjectiveTake struct {
+	Kind string `json:"kind"`
+	// ID is the monster or building id.
+	ID       int64             `json:"id"`
+	Seconds  float64           `json:"seconds"`
+	TeamID   int64             `json:"team_id"`
+	KillerID int64             `json:"killer_id,omitempty"`
+	Position baseview.Position `json:"position"`
+	// Contested is true if the other team was at the objective shortly
+	// before it was taken.
+	Contested bool `json:"contested"`
+	// Sides are the taking team's, and then the other team's.
+	Sides [2]*ObjectiveSide `json:"sides"`
+}
+
+// ObjectiveSide is what a team did around an objective take.
+type ObjectiveSide struct {
+	TeamID int64 `json:"team_id"`
+	// Present were at the objective when it was taken, Absent were alive
+	// elsewhere, and Dead were dead.
+	Present []int64 `json:"present"`
+	Absent  []int64 `json:"absent"`
+	Dead    []int64 `json:"dead"`
+
+	// Presence is the average number of the team at the objective during the
+	// setup.
+	Presence float64 `json:"presence"`
+	// Wards is the number of the team's wards at the objective when it was
+	// taken, and WardsPlaced how many were placed there during the setup.
+	Wards       int `json:"wards"`
+	WardsPlaced int `json:"wards_placed"`
+	// SetupScore (0 to 100) is the team's share of the presence and of the
+	// wards at the objective, each counting half. Shares are even when
+	// neither team has any.
+	SetupScore float64 `json:"setup_score"`
+
+	// GoldElsewhere is the gold earned during the setup by the team's
+	// players who weren't at the objective, and TakesElsewhere are the kinds
+	// of other objectives the team took in that time: what they traded for
+	// it.
+	GoldElsewhere  int64    `json:"gold_elsewhere"`
+	TakesElsewhere []string `json:"takes_elsewhere,omitempty"`
+}
+
+// ObjectivePlayer sums a player's part in the objective takes.
+type ObjectivePlayer struct {
+	ParticipantID int64 `json:"participant_id"`
+	TeamID        int64 `json:"team_id"`
+	// Present is the number of their team's takes they were at, and
+	// Participation that as a percent of their team's takes.
+	Present       int     `json:"present"`
+	Participation float64 `json:"participation"`
+	// Contested is the number of the other team's takes they were at.
+	Contested int `json:"contested"`
+	// SetupScore is the average percent of the setup of every take (by
+	// either team) that they spent at the objective.
+	SetupScore  float64 `json:"setup_score"`
+	WardsPlaced int     `json:"wards_placed"`
+}
+
+// ObjectiveTeam sums a team's objective takes and setups.
+type ObjectiveTeam struct {
+	TeamID int64 `json:"team_id"`
+	// Taken is the number of takes of each kind.
+	Taken map[string]int `json:"taken"`
+	// Contested is the number of their takes that were contested.
+	Contested int `json:"contested"`
+	// Participation is the average number of the team at their takes.
+	Participation float64 `json:"participation"`
+	// SetupScore is the average of the team's setup scores of every take
+	// (by either team).
+	SetupScore    float64 `json:"setup_score"`
+	GoldElsewhere int64   `json:"gold_elsewhere"`
+}
+
+// objectiveAnalyzer finds the objectives taken in the match, and measures
+// each team's positioning, vision and trades around them.
+type objectiveAnalyzer struct{}
+
+func (objectiveAnalyzer) Name() string                   { return "objectives" }
+func (objectiveAnalyzer) Version() int                   { return 2 }
+func (objectiveAnalyzer) Maps() []int64                  { return []int64{baseview.MapSummonersRift} }
+func (objectiveAnalyzer) Roles() []baseview.RolePosition { return nil }
+func (objectiveAnalyzer) Dependencies() []string         { return nil }
+
+func (objectiveAnalyzer) Analyze(m *Match, deps map[string]interface{}) (interface{}, error) {
+	takes := m.shared("objective_takes", func() interface{} { return analyzeTakes(m) }).([]*ObjectiveTake)
+	id := m.Participant.ParticipantID
+	teamID := baseview.TeamID(id)
+	involved := []*ObjectiveTake{}
+	for _, take := range takes {
+		if take.TeamID == teamID || take.Contested {
+			involved = append(involved, take)
+		}
+	}
+	return &ObjectiveResult{
+		Takes:  involved,
+		Player: objectivePlayer(m, takes, id),
+		Team:   objectiveTeam(takes, teamID),
+	}, nil
+}
+
+// analyzeTakes returns the objectives taken in the match, with what each team
+// did around them.
+func analyzeTakes(m *Match) []*ObjectiveTake {
+	takes := objectiveTakes(m)
+	for _, take := range takes {
+		take.Sides[0] = objectiveSide(m, take, take.TeamID, takes)
+		take.Sides[1] = objectiveSide(m, take, otherTeam(take.TeamID), takes)
+		take.Contested = contested(m, take)
+		setupScores(take.Sides[0], take.Sides[1])
+	}
+	return takes
+}
+
+// objectiveTakes returns the epic monsters (with a champion killer) and
+// turrets taken in the match, in order.
+func objectiveTakes(m *Match) []*ObjectiveTake {
+	takes := []*ObjectiveTake{}
+	for _, e := range m.Index.EventsBetween(math.Inf(-1), math.Inf(1), "epic_monster_kill", "building_kill") {
+		take := &ObjectiveTake{Seconds: e.Seconds()}
+		switch t := e.(type) {
+		case *baseview.EpicMonsterKill:
+			take.ID, take.KillerID, take.TeamID = t.VictimID, t.KillerID, baseview.TeamID(t.KillerID)
+			take.Kind = monsterObjective(t.VictimID)
+			pit := m.Geometry.Region(objectivePits[take.Kind])
+			if take.Kind == "" || take.TeamID == 0 || pit == nil {
+				continue
+			}
+			take.Position = polygonCenter(pit.Polygon)
+		case *baseview.BuildingKill:
+			position, found := m.Geometry.Turrets[t.BuildingID]
+			if t.BuildingType != baseview.ActorTurret || !found {
+				continue
+			}
+			take.ID, take.Kind, take.Position = t.BuildingID, ObjectiveTurret, position
+			// building ids start with the id of their team.
+			take.TeamID = baseview.BlueTeam
+			if t.BuildingID/100*100 == baseview.BlueTeam {
+				take.TeamID = baseview.RedTeam
+			}
+		}
+		takes = append(takes, take)
+	}
+	return takes
+}
+
+func monsterObjective(id int64) string {
+	switch id {
+	case baseview.MonsterDragonElemental:
+		return ObjectiveDragon
+	case baseview.MonsterDragonElder:
+		return ObjectiveElderDragon
+	case baseview.MonsterBaron:
+		return ObjectiveBaron
+	case baseview.MonsterRiftHerald:
+		return ObjectiveHerald
+	}
+	return ""
+}
+
+// polygonCenter returns the average of the polygon's vertices.
+func polygonCenter(poly baseview.Polygon) baseview.Position {
+	center := baseview.Position{}
+	for _, v := range poly {
+		center.X += v[0] / float64(len(poly))
+		center.Y += v[1] / float64(len(poly))
+	}
+	return center
+}
+
+// setupSamples returns the times positions are sampled at during the setup
+// of the take, ending with the take.
+func setupSamples(take *ObjectiveTake) []float64 {
+	samples := []float64{}
+	for s := take.Seconds - objectiveSetupSeconds; s <= take.Seconds; s += objectiveSampleSeconds {
+		samples = append(samples, s)
+	}
+	return samples
+}
+
+// atObjective returns true if the participant was alive within
+// objectiveRadius of the take's position at t.
+func atObjective(m *Match, take *ObjectiveTake, participantID int64, t float64) bool {
+	state, ok := m.Index.StateAt(participantID, t)
+	return ok && m.Index.AliveAt(participantID, t) && state.Position.DistanceXY(take.Position) < objectiveRadius
+}
+
+// otherTeam returns the id of the team playing teamID.
+func otherTeam(teamID int64) int64 {
+	if teamID == baseview.BlueTeam {
+		return baseview.RedTeam
+	}
+	return baseview.BlueTeam
+}
+
+func teamMembers(m *Match, teamID int64) []int64 {
+	members := []int64{}
+	for _, id := range m.Index.Participants() {
+		if baseview.TeamID(id) == teamID {
+			members = append(members, id)
+		}
+	}
+	return members
+}
+
+// objectiveSide returns what the team did around the take, except for the
+// setup score, which needs both sides.
+func objectiveSide(m *Match, take *ObjectiveTake, teamID int64, takes []*ObjectiveTake) *ObjectiveSide {
+	side := &ObjectiveSide{TeamID: teamID, Present: []int64{}, Absent: []int64{}, Dead: []int64{}}
+	members := teamMembers(m, teamID)
+	for _, id := range members {
+		switch {
+		case atObjective(m, take, id, take.Seconds):
+			side.Present = append(side.Present, id)
+		case m.Index.AliveAt(id, take.Seconds):
+			side.Absent = append(side.Absent, id)
+			side.GoldElsewhere += goldEarned(m, id, take.Seconds-objectiveSetupSeconds, take.Seconds)
+		default:
+			side.Dead = append(side.Dead, id)
+		}
+	}
+
+	samples := setupSamples(take)
+	for _, t := range samples {
+		for _, id := range members {
+			if atObjective(m, take, id, t) {
+				side.Presence++
+			}
+		}
+	}
+	side.Presence /= float64(len(samples))
+
+	for pid, lives := range m.Stats.WardLives {
+		if baseview.TeamID(pid) != teamID {
+			continue
+		}
+		for _, w := range lives {
+			if w.Position == nil || w.Position.DistanceXY(take.Position) >= objectiveRadius {
+				continue
+			}
+			if w.Begin <= take.Seconds && w.End >= take.Seconds {
+				side.Wards++
+			}
+			if w.Begin >= take.Seconds-objectiveSetupSeconds && w.Begin <= take.Seconds {
+				side.WardsPlaced++
+			}
+		}
+	}
+
+	for _, other := range takes {
+		if other != take && other.TeamID == teamID && other.Position != take.Position &&
+			other.Seconds >= take.Seconds-objectiveSetupSeconds && other.Seconds <= take.Seconds {
+			side.TakesElsewhere = append(side.TakesElsewhere, other.Kind)
+		}
+	}
+	return side
+}
+
+// goldEarned returns the gold the participant earned from t1 to t2, i.e. the
+// increases of their gold (which goes down when they spend it).
+func goldEarned(m *Match, participantID int64, t1, t2 float64) int64 {
+	earned := int64(0)
+	last := m.Index.LastState(participantID, t1)
+	for _, e := range m.Index.EventsBetween(t1, t2, "state_update") {
+		s := e.(*baseview.StateUpdate)
+		if s.ParticipantID != participantID {
+			continue
+		}
+		if last != nil && s.Gold > last.Gold {
+			earned += s.Gold - last.Gold
+		}
+		last = s
+	}
+	return earned
+}
+
+// contested returns true if any of the other team was at the objective in
+// the objectiveContestSeconds before it was taken.
+func contested(m *Match, take *ObjectiveTake) bool {
+	for _, t := range setupSamples(take) {
+		if t < take.Seconds-objectiveContestSeconds {
+			continue
+		}
+		for _, id := range teamMembers(m, otherTeam(take.TeamID)) {
+			if atObjective(m, take, id, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func setupScores(a, b *ObjectiveSide) {
+	presenceA, presenceB := share(a.Presence, b.Presence)
+	wardsA, wardsB := share(float64(a.Wards), float64(b.Wards))
+	a.SetupScore = 50*presenceA + 50*wardsA
+	b.SetupScore = 50*presenceB + 50*wardsB
+}
+
+// share returns the shares of a and b of their total, which are even if it
+// is 0.
+func share(a, b float64) (float64, float64) {
+	if a+b == 0 {
+		return 0.5, 0.5
+	}
+	return a / (a + b), b / (a + b)
+}
+
+func objectivePlayer(m *Match, takes []*ObjectiveTake, id int64) ObjectivePlayer {
+	teamID := baseview.TeamID(id)
+	p := ObjectivePlayer{ParticipantID: id, TeamID: teamID}
+	teamTakes, setup := 0, 0.0
+	for _, take := range takes {
+		if containsInt64(take.Sides[0].Present, id) || containsInt64(take.Sides[1].Present, id) {
+			if take.TeamID == teamID {
+				p.Present++
+			} else {
+				p.Contested++
+			}
+		}
+		if take.TeamID == teamID {
+			teamTakes++
+		}
+		samples := setupSamples(take)
+		for _, t := range samples {
+			if atObjective(m, take, id, t) {
+				setup += 100 / float64(len(samples))
+			}
+		}
+	}
+	for _, w := range m.Stats.WardLives[id] {
+		for _, take := range takes {
+			if w.Position != nil && w.Position.DistanceXY(take.Position) < objectiveRadius &&
+				w.Begin >= take.Seconds-objectiveSetupSeconds && w.Begin <= take.Seconds {
+				p.WardsPlaced++
+				break
+			}
+		}
+	}
+	if teamTakes > 0 {
+		p.Participation = 100 * float64(p.Present) / float64(teamTakes)
+	}
+	if len(takes) > 0 {
+		p.SetupScore = setup / float64(len(takes))
+	}
+	return p
+}
+
+func objectiveTeam(takes []*ObjectiveTake, teamID int64) ObjectiveTeam {
+	team := ObjectiveTeam{TeamID: teamID, Taken: map[string]int{}}
+	taken := 0
+	for _, take := range takes {
+		side := take.Sides[1]
+		if take.TeamID == teamID {
+			side = take.Sides[0]
+			taken++
+			team.Taken[take.Kind]++
+			team.Participation += float64(len(side.Present))
+			if take.Contested {
+				team.Contested++
+			}
+		}
+		team.SetupScore += side.SetupScore / float64(len(takes))
+		team.GoldElsewhere += side.GoldElsewhere
+	}
+	if taken > 0 {
+		team.Participation /= float64(taken)
+	}
+	return team
+}
@@ -0,0 +1,154 @@
+package ingest
+
+import (
+	"math"
+	"testing"
+
+	"gopkg.in/tylerb/is.v1"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// objectiveMatch returns a match in which blue (1, 2 and 3) set up dragon for
+// a minute, with a ward, while 4 and 5 farm and take red's top outer turret.
+// Red's 6 contests the dragon late, and 7 is dead. 1 kills it at 160s.
+func objectiveMatch() *Match {
+	bv := &baseview.Baseview{Version: baseview.CurrentVersion, MapID: baseview.MapSummonersRift}
+	for id := int64(1); id <= 10; id++ {
+		bv.Participants = append(bv.Participants, baseview.Participant{ParticipantID: id, SummonerID: 10 + id})
+	}
+	dragon := baseview.Position{X: 9900, Y: 4600}
+	top := baseview.SummonersRift().Turrets[baseview.TurretRedTopOuter]
+	base := map[int64]baseview.Position{6: {X: 9000, Y: 9000}, 8: {X: 13000, Y: 13000}, 9: {X: 13000, Y: 13000}, 10: {X: 13000, Y: 13000}}
+	wards := NewWardAnalysis()
+
+	for s := 0; s <= 200; s++ {
+		seconds := float64(s)
+		switch s {
+		case 110:
+			w := matchEvent(&baseview.WardPlaced{ParticipantID: 2, WardID: 1, WardItemType: baseview.YellowTrinket,
+				Position: baseview.Position{X: 9500, Y: 5000}, TeamID: baseview.BlueTeam}, "ward_placed", seconds).(*baseview.WardPlaced)
+			bv.Events = append(bv.Events, w)
+			wards.AddWardPlaced(w, 200)
+		case 130:
+			bv.Events = append(bv.Events, matchEvent(&baseview.Death{VictimID: 7}, "death", seconds))
+		case 155:
+			bv.Events = append(bv.Events, matchEvent(&baseview.BuildingKill{BuildingType: baseview.ActorTurret,
+				BuildingID: baseview.TurretRedTopOuter}, "building_kill", seconds))
+		case 160:
+			bv.Events = append(bv.Events, matchEvent(&baseview.EpicMonsterKill{VictimID: baseview.MonsterDragonElemental, KillerID: 1}, "epic_monster_kill", seconds))
+			// not killed by a champion.
+			bv.Events = append(bv.Events, matchEvent(&baseview.EpicMonsterKill{VictimID: baseview.MonsterBaron}, "epic_monster_kill", seconds))
+		}
+
+		for id := int64(1); id <= 10; id++ {
+			position, health := base[id], 500.0
+			switch {
+			case id <= 3 && s >= 100:
+				position = dragon
+			case id == 4 || id == 5:
+				position = top
+			case id == 6 && s >= 150:
+				position = dragon
+			case id == 7 && s >= 130:
+				health = 0
+			}
+			bv.Events = append(bv.Events, matchEvent(&baseview.StateUpdate{ParticipantID: id, Position: position,
+				Health: health, HealthMax: 500, Gold: int64(10 * s)}, "state_update", seconds))
+		}
+	}
+
+	return &Match{
+		Baseview:      bv,
+		Index:         baseview.NewIndex(bv),
+		Geometry:      baseview.SummonersRift(),
+		Participant:   &bv.Participants[0],
+		MatchDuration: 200,
+		Stats:         &AdvancedStats{SummonerID: 11, WardLives: wards.ExportPlayerWards()},
+	}
+}
+
+func TestObjectives(t *testing.T) {
+	is := is.New(t)
+
+	m := objectiveMatch()
+	takes := analyzeTakes(m)
+	is.Equal(2, len(takes))
+	turret, dragon := takes[0], takes[1]
+	is.Equal(ObjectiveTurret, turret.Kind)
+	is.Equal(baseview.BlueTeam, turret.TeamID)
+	is.Equal([]int64{4, 5}, turret.Sides[0].Present)
+	is.False(turret.Contested)
+
+	is.Equal(ObjectiveDragon, dragon.Kind)
+	is.Equal(baseview.BlueTeam, dragon.TeamID)
+	is.Equal(int64(1), dragon.KillerID)
+	is.True(dragon.Contested)
+
+	blue, red := dragon.Sides[0], dragon.Sides[1]
+	is.Equal([]int64{1, 2, 3}, blue.Present)
+	is.Equal([]int64{4, 5}, blue.Absent)
+	is.Equal([]int64{}, blue.Dead)
+	is.Equal([]int64{6}, red.Present)
+	is.Equal([]int64{7}, red.Dead)
+	// 3 of blue for the last 13 samples, and 6 for the last 3.
+	is.Equal(3.0, blue.Presence)
+	is.Equal(3.0/13, red.Presence)
+	is.Equal(1, blue.Wards)
+	is.Equal(1, blue.WardsPlaced)
+	is.Equal(0, red.Wards)
+	// blue has 13/14 of the presence and all the wards.
+	is.True(math.Abs(50*13.0/14+50-blue.SetupScore) < 1e-9)
+	is.True(math.Abs(50*1.0/14-red.SetupScore) < 1e-9)
+	// 4 and 5 earned 10 gold a second, and took the turret.
+	is.Equal(int64(2*600), blue.GoldElsewhere)
+	is.Equal([]string{ObjectiveTurret}, blue.TakesElsewhere)
+	is.Nil(red.TakesElsewhere)
+
+	// the result only has the summoner's row, and their team's.
+	res, err := objectiveAnalyzer{}.Analyze(m, nil)
+	is.NotErr(err)
+	objectives := res.(*ObjectiveResult)
+	p1 := objectives.Player
+	is.Equal(int64(1), p1.ParticipantID)
+	is.Equal(1, p1.Present)
+	is.Equal(50.0, p1.Participation)
+
+	// both takes were blue's.
+	is.Equal(takes, objectives.Takes)
+
+	blueTeam := objectives.Team
+	is.Equal(baseview.BlueTeam, blueTeam.TeamID)
+	is.Equal(map[string]int{ObjectiveDragon: 1, ObjectiveTurret: 1}, blueTeam.Taken)
+	is.Equal(1, blueTeam.Contested)
+	is.Equal(2.5, blueTeam.Participation)
+
+	is.Equal(1, objectivePlayer(m, takes, 2).WardsPlaced)
+	m.Participant = &m.Baseview.Participants[5]
+	res, err = objectiveAnalyzer{}.Analyze(m, nil)
+	is.NotErr(err)
+	p6 := res.(*ObjectiveResult).Player
+	is.Equal(1, p6.Contested)
+	is.Equal(0.0, p6.Participation)
+	is.Equal(100*3.0/13/2, p6.SetupScore)
+	is.Equal(0, len(res.(*ObjectiveResult).Team.Taken))
+	// red only contested the dragon.
+	is.Equal([]*ObjectiveTake{dragon}, res.(*ObjectiveResult).Takes)
+}
+
+func TestObjectivesRegistered(t *testing.T) {
+	is := is.New(t)
+
+	s, err := ComputeAdvanced(*advancedMatch(1), 101, 1, "NA1")
+	is.NotErr(err)
+	r := s.Analyses[AnalysisKey("objectives", 2)]
+	is.NotNil(r)
+	is.Equal("", r.Error)
+	is.Equal(0, len(r.Result.(*ObjectiveResult).Team.Taken))
+
+	bv := advancedMatch(1)
+	bv.MapID = baseview.MapHowlingAbyss
+	s, err = ComputeAdvanced(*bv, 101, 1, "NA1")
+	is.NotErr(err)
+	is.Equal("unsupported map 12", s.Analyses[AnalysisKey("objectives", 2)].Skipped)
+}
@@ -0,0 +1,344 @@
+package ingest
+
+import (
+	"math"
+	"sort"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+const (
+	// recallMinDistance is how far from their fountain a player has to jump
+	// from for it to be a recall.
+	recallMinDistance = 3000
+	// recallFarmSeconds is how long before a recall the player's farming rate
+	// is measured, to estimate the cs they lost while away.
+	recallFarmSeconds = 60
+	// recallWaveSeconds is how long before a recall (which takes 8 seconds to
+	// channel) the player must have attacked recallWaveAttacks minions for
+	// the wave to count as pushed.
+	recallWaveSeconds = 20
+	recallWaveAttacks = 3
+	// a recall is worthwhile with at least recallWorthGold to spend, or with
+	// less than recallLowHealth percent health.
+	recallWorthGold = 1000
+	recallLowHealth = 35
+	// recallSpawnAfter is how soon after the player gets back an objective
+	// has to spawn for the recall to line up with it.
+	recallSpawnAfter = 90
+)
+
+// Objective spawn times (seconds into the match), and how long after a take
+// they respawn. The herald only spawns once.
+const (
+	dragonSpawn   = 150
+	dragonRespawn = 360
+	baronSpawn    = 1200
+	baronRespawn  = 420
+	heraldSpawn   = 600
+)
+
+// levelExperience is the total experience needed to reach each level.
+var levelExperience = []float64{0, 0, 280, 660, 1140, 1720, 2400, 3180, 4060, 5040,
+	6120, 7300, 8580, 9960, 11440, 13020, 14700, 16480, 18360}
+
+func init() {
+	Register(recallAnalyzer{})
+}
+
+// RecallResult is the result of the recalls analyzer: every recall of the
+// player, and how well they were spent.
+type RecallResult struct {
+	Recalls []*Recall     `json:"recalls"`
+	Summary RecallSummary `json:"summary"`
+}
+
+// Recall is a recall of the player to their fountain.
+type Recall struct {
+	// Seconds is when the player arrived at the fountain, and From where
+	// they recalled from.
+	Seconds float64           `json:"seconds"`
+	From    baseview.Position `json:"from"`
+	Area    string            `json:"area,omitempty"`
+
+	// Gold is the gold in hand, and HealthPercent and ManaPercent the
+	// player's health and mana, when they recalled.
+	Gold          int64   `json:"gold"`
+	HealthPercent float64 `json:"health_percent"`
+	ManaPercent   float64 `json:"mana_percent"`
+
+	// ReturnSeconds is when the player got back as far from their fountain
+	// as they recalled from (or died, recalled again, or the match ended).
+	ReturnSeconds float64 `json:"return_seconds"`
+	AwaySeconds   float64 `json:"away_seconds"`
+	// LostCS and LostXP estimate what the player would have earned while
+	// away at their rate before the recall. Baseviews have no experience, so
+	// its rate is the average up to the recall, from the player's level.
+	LostCS float64 `json:"lost_cs"`
+	LostXP float64 `json:"lost_xp"`
+
+	// MinionAttacks are the player's attacks on minions shortly before the
+	// recall, and Pushed is true if there were enough of them that they
+	// pushed the wave first.
+	MinionAttacks int  `json:"minion_attacks"`
+	Pushed        bool `json:"pushed"`
+
+	// LinedUp is the objective that spawned soon after the player got back,
+	// and Missed the one that spawned while they were away.
+	LinedUp string `json:"lined_up,omitempty"`
+	Missed  string `json:"missed,omitempty"`
+
+	// Score (0 to 100) counts 40 if the recall was worthwhile (enough gold,
+	// or low health), 30 if the wave was pushed, and 30 if it lined up with
+	// an objective (15 if it neither lined up nor missed one).
+	Score float64 `json:"score"`
+}
+
+// RecallSummary sums the player's recalls.
+type RecallSummary struct {
+	Recalls              int     `json:"recalls"`
+	AverageGold          float64 `json:"average_gold"`
+	AverageHealthPercent float64 `json:"average_health_percent"`
+	AverageAwaySeconds   float64 `json:"average_away_seconds"`
+	LostCS               float64 `json:"lost_cs"`
+	LostXP               float64 `json:"lost_xp"`
+	Pushed               int     `json:"pushed"`
+	LinedUp              int     `json:"lined_up"`
+	Missed               int     `json:"missed"`
+	// EfficiencyScore is the average score of the recalls.
+	EfficiencyScore float64 `json:"efficiency_score"`
+}
+
+// recallAnalyzer finds the player's recalls, from their jumps to their
+// fountain, and measures how well timed they were.
+type recallAnalyzer struct{}
+
+func (recallAnalyzer) Name() string                   { return "recalls" }
+func (recallAnalyzer) Version() int                   { return 1 }
+func (recallAnalyzer) Maps() []int64                  { return []int64{baseview.MapSummonersRift} }
+func (recallAnalyzer) Roles() []baseview.RolePosition { return nil }
+func (recallAnalyzer) Dependencies() []string         { return nil }
+
+func (recallAnalyzer) Analyze(m *Match, deps map[string]interface{}) (interface{}, error) {
+	pid := m.Participant.ParticipantID
+	recalls := findRecalls(m, pid)
+	spawns := m.shared("objective_spawns", func() interface{} { return objectiveSpawns(m) }).([]objectiveSpawn)
+
+	for i, r := range recalls {
+		end := m.MatchDuration
+		if i+1 < len(recalls) {
+			end = recalls[i+1].Seconds
+		}
+		r.ReturnSeconds = recallReturn(m, pid, r, end)
+		r.AwaySeconds = r.ReturnSeconds - r.Seconds
+		r.LostCS = farmRate(m, pid, r.Seconds) * r.AwaySeconds
+		if r.Seconds > 0 {
+			r.LostXP = levelExperience[levelAt(m, pid, r.Seconds)] / r.Seconds * r.AwaySeconds
+		}
+
+		for _, e := range m.Index.EventsBetween(r.Seconds-recallWaveSeconds, r.Seconds, "attack") {
+			if a := e.(*baseview.Attack); a.AttackerID == pid && a.TargetType == baseview.ActorMinion {
+				r.MinionAttacks++
+			}
+		}
+		r.Pushed = r.MinionAttacks >= recallWaveAttacks
+
+		for _, s := range spawns {
+			switch {
+			case s.seconds > r.Seconds && s.seconds < r.ReturnSeconds && r.Missed == "":
+				r.Missed = s.kind
+			case s.seconds >= r.ReturnSeconds && s.seconds <= r.ReturnSeconds+recallSpawnAfter && r.LinedUp == "":
+				r.LinedUp = s.kind
+			}
+		}
+		r.Score = recallScore(r)
+	}
+
+	return &RecallResult{Recalls: recalls, Summary: summarizeRecalls(recalls)}, nil
+}
+
+// findRecalls returns the times the player jumped (without dying) from at
+// least recallMinDistance away to their fountain.
+func findRecalls(m *Match, pid int64) []*Recall {
+	recalls := []*Recall{}
+	teamID := baseview.TeamID(pid)
+	fountain, found := m.Geometry.Fountains[teamID]
+	if !found {
+		return recalls
+	}
+
+	var last *baseview.StateUpdate
+	for _, e := range m.Index.EventsBetween(math.Inf(-1), math.Inf(1), "state_update", "death") {
+		switch t := e.(type) {
+		case *baseview.Death:
+			if t.VictimID == pid {
+				last = nil
+			}
+		case *baseview.StateUpdate:
+			if t.ParticipantID != pid {
+				continue
+			}
+			if last != nil && last.Health > 0 && last.Position.DistanceXY(fountain) >= recallMinDistance &&
+				inFountain(m.Geometry, teamID, t.Position) {
+				r := &Recall{
+					Seconds:       t.Seconds(),
+					From:          last.Position,
+					Gold:          last.Gold,
+					HealthPercent: percent(last.Health, last.HealthMax),
+					ManaPercent:   percent(last.Mana, last.ManaMax),
+				}
+				if area := m.Geometry.AreaAt(last.Position); area != nil {
+					r.Area = area.Name
+				}
+				recalls = append(recalls, r)
+			}
+			last = t
+		}
+	}
+	return recalls
+}
+
+func inFountain(geometry *baseview.Map, teamID int64, p baseview.Position) bool {
+	for _, r := range geometry.RegionsAt(p) {
+		if r.Kind == baseview.KindFountain && r.Team == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+func percent(value, max float64) float64 {
+	if max <= 0 {
+		return 0
+	}
+	return 100 * value / max
+}
+
+// recallReturn returns when the player got back as far from their fountain
+// as they recalled from, or died, or end.
+func recallReturn(m *Match, pid int64, r *Recall, end float64) float64 {
+	fountain := m.Geometry.Fountains[baseview.TeamID(pid)]
+	distance := r.From.DistanceXY(fountain)
+	for _, e := range m.Index.EventsBetween(r.Seconds, end, "state_update", "death") {
+		switch t := e.(type) {
+		case *baseview.Death:
+			if t.VictimID == pid {
+				return t.Seconds()
+			}
+		case *baseview.StateUpdate:
+			if t.ParticipantID == pid && t.Position.DistanceXY(fountain) >= distance {
+				return t.Seconds()
+			}
+		}
+	}
+	return math.Max(end, r.Seconds)
+}
+
+// farmRate returns the minions (lane and jungle) the player killed per second
+// in the recallFarmSeconds before t.
+func farmRate(m *Match, pid int64, t float64) float64 {
+	before, at := m.Index.LastState(pid, t-recallFarmSeconds), m.Index.LastState(pid, t)
+	if before == nil || at == nil || at.Seconds() <= before.Seconds() {
+		return 0
+	}
+	cs := at.MinionsKilled + at.NeutralMinionsKilled - before.MinionsKilled - before.NeutralMinionsKilled
+	return float64(cs) / (at.Seconds() - before.Seconds())
+}
+
+// levelAt returns the player's level at t.
+func levelAt(m *Match, pid int64, t float64) int64 {
+	level := int64(1)
+	for _, e := range m.Index.EventsBetween(math.Inf(-1), t, "level_up") {
+		if l := e.(*baseview.LevelUp); l.ParticipantID == pid && l.Level > level {
+			level = l.Level
+		}
+	}
+	if level >= int64(len(levelExperience)) {
+		level = int64(len(levelExperience)) - 1
+	}
+	return level
+}
+
+type objectiveSpawn struct {
+	kind    string
+	seconds float64
+}
+
+// objectiveSpawns returns when the dragon, baron and herald spawned during
+// the match, in order.
+func objectiveSpawns(m *Match) []objectiveSpawn {
+	spawns := []objectiveSpawn{
+		{ObjectiveDragon, dragonSpawn},
+		{ObjectiveHerald, heraldSpawn},
+		{ObjectiveBaron, baronSpawn},
+	}
+	for _, e := range m.Index.EventsBetween(math.Inf(-1), math.Inf(1), "epic_monster_kill") {
+		t := e.(*baseview.EpicMonsterKill)
+		switch t.VictimID {
+		case baseview.MonsterDragonElemental, baseview.MonsterDragonElder:
+			spawns = append(spawns, objectiveSpawn{ObjectiveDragon, t.Seconds() + dragonRespawn})
+		case baseview.MonsterBaron:
+			spawns = append(spawns, objectiveSpawn{ObjectiveBaron, t.Seconds() + baronRespawn})
+		}
+	}
+	sort.Stable(bySpawnSeconds(spawns))
+	for i, s := range spawns {
+		if s.seconds > m.MatchDuration {
+			return spawns[:i]
+		}
+	}
+	return spawns
+}
+
+type bySpawnSeconds []objectiveSpawn
+
+func (s bySpawnSeconds) Len() int           { return len(s) }
+func (s bySpawnSeconds) Less(i, j int) bool { return s[i].seconds < s[j].seconds }
+func (s bySpawnSeconds) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func recallScore(r *Recall) float64 {
+	score := 0.0
+	if r.Gold >= recallWorthGold || r.HealthPercent < recallLowHealth {
+		score += 40
+	}
+	if r.Pushed {
+		score += 30
+	}
+	switch {
+	case r.LinedUp != "":
+		score += 30
+	case r.Missed == "":
+		score += 15
+	}
+	return score
+}
+
+func summarizeRecalls(recalls []*Recall) RecallSummary {
+	s := RecallSummary{Recalls: len(recalls)}
+	if len(recalls) == 0 {
+		return s
+	}
+	for _, r := range recalls {
+		s.AverageGold += float64(r.Gold)
+		s.AverageHealthPercent += r.HealthPercent
+		s.AverageAwaySeconds += r.AwaySeconds
+		s.LostCS += r.LostCS
+		s.LostXP += r.LostXP
+		s.EfficiencyScore += r.Score
+		if r.Pushed {
+			s.Pushed++
+		}
+		if r.LinedUp != "" {
+			s.LinedUp++
+		}
+		if r.Missed != "" {
+			s.Missed++
+		}
+	}
+	n := float64(len(recalls))
+	s.AverageGold /= n
+	s.AverageHealthPercent /= n
+	s.AverageAwaySeconds /= n
+	s.EfficiencyScore /= n
+	return s
+}
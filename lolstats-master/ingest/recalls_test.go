@@ -0,0 +1,120 @@
+package ingest
+
+import (
+	"math"
+	"testing"
+
+	"gopkg.in/tylerb/is.v1"
+
+	"github.com/VantageSports/lolstats/baseview"
+)
+
+// recallMatch returns a match in which participant 1 farms top, pushes the
+// wave and recalls with 1200 gold at 100s, walking back in time for the
+// first dragon. They die at 200s, and recall again with no gold at 300s,
+// sitting in base through the dragon's respawn.
+func recallMatch() *Match {
+	bv := &baseview.Baseview{Version: baseview.CurrentVersion, MapID: baseview.MapSummonersRift,
+		Participants: []baseview.Participant{{ParticipantID: 1, SummonerID: 11}}}
+	fountain := baseview.Position{X: 400, Y: 420}
+	lane := baseview.Position{X: 1500, Y: 9000}
+
+	for s := 0; s < 590; s++ {
+		seconds := float64(s)
+		switch s {
+		case 60:
+			bv.Events = append(bv.Events, matchEvent(&baseview.LevelUp{ParticipantID: 1, Level: 3}, "level_up", seconds))
+		case 85, 86, 87, 88:
+			bv.Events = append(bv.Events, matchEvent(&baseview.Attack{AttackerID: 1, TargetType: baseview.ActorMinion}, "attack", seconds))
+		case 160:
+			bv.Events = append(bv.Events, matchEvent(&baseview.EpicMonsterKill{VictimID: baseview.MonsterDragonElemental, KillerID: 6}, "epic_monster_kill", seconds))
+		case 200:
+			bv.Events = append(bv.Events, matchEvent(&baseview.Death{VictimID: 1, Position: lane}, "death", seconds))
+		}
+
+		state := &baseview.StateUpdate{ParticipantID: 1, Position: lane, Health: 300, HealthMax: 500,
+			Mana: 100, ManaMax: 400, Gold: 1200, MinionsKilled: int64(s / 10)}
+		switch {
+		case s == 0 || s >= 100 && s < 120 || s >= 210 && s < 220 || s >= 300:
+			state.Position = fountain
+		case s >= 120 && s < 140:
+			state.Position = baseview.Position{X: 1000, Y: 4500}
+		case s > 200 && s < 210:
+			continue
+		}
+		if s >= 100 && s < 140 {
+			state.MinionsKilled = 10
+		}
+		if s == 200 {
+			state.Health = 0
+		} else if s > 200 {
+			state.Gold, state.Health = 200, 500
+		}
+		bv.Events = append(bv.Events, matchEvent(state, "state_update", seconds))
+	}
+
+	return &Match{
+		Baseview:      bv,
+		Index:         baseview.NewIndex(bv),
+		Geometry:      baseview.SummonersRift(),
+		Participant:   &bv.Participants[0],
+		MatchDuration: 590,
+		Stats:         &AdvancedStats{SummonerID: 11},
+	}
+}
+
+func TestRecalls(t *testing.T) {
+	is := is.New(t)
+
+	res, err := recallAnalyzer{}.Analyze(recallMatch(), nil)
+	is.NotErr(err)
+	recalls := res.(*RecallResult)
+
+	// the respawn after the death isn't a recall.
+	is.Equal(2, len(recalls.Recalls))
+	first, second := recalls.Recalls[0], recalls.Recalls[1]
+
+	is.Equal(100.0, first.Seconds)
+	is.Equal(baseview.Position{X: 1500, Y: 9000}, first.From)
+	is.Equal(int64(1200), first.Gold)
+	is.Equal(60.0, first.HealthPercent)
+	is.Equal(25.0, first.ManaPercent)
+	is.Equal(140.0, first.ReturnSeconds)
+	is.Equal(40.0, first.AwaySeconds)
+	// 6 cs in the minute before, and level 3 (660 xp) at 100s.
+	is.True(math.Abs(first.LostCS-4) < 1e-9)
+	is.True(math.Abs(first.LostXP-264) < 1e-9)
+	is.Equal(4, first.MinionAttacks)
+	is.True(first.Pushed)
+	is.Equal(ObjectiveDragon, first.LinedUp)
+	is.Equal("", first.Missed)
+	is.Equal(100.0, first.Score)
+
+	is.Equal(300.0, second.Seconds)
+	is.Equal(int64(200), second.Gold)
+	is.Equal(100.0, second.HealthPercent)
+	is.Equal(590.0, second.ReturnSeconds)
+	is.False(second.Pushed)
+	// the dragon respawned at 520s.
+	is.Equal(ObjectiveDragon, second.Missed)
+	is.Equal(0.0, second.Score)
+
+	summary := recalls.Summary
+	is.Equal(2, summary.Recalls)
+	is.Equal(700.0, summary.AverageGold)
+	is.Equal(1, summary.Pushed)
+	is.Equal(1, summary.LinedUp)
+	is.Equal(1, summary.Missed)
+	is.Equal(50.0, summary.EfficiencyScore)
+}
+
+func TestRecallsRegistered(t *testing.T) {
+	is := is.New(t)
+
+	s, err := ComputeAdvanced(*advancedMatch(1), 101, 1, "NA1")
+	is.NotErr(err)
+	r := s.Analyses[AnalysisKey("recalls", 1)]
+	is.NotNil(r)
+	is.Equal("", r.Error)
+	is.NotNil(r.Result.(*RecallResult))
+}
@@ -211,7 +211,7 @@ func (st *augmentedState) friendlyAndEnemyTeamGold(teamID int64) (int64, int64)
 	return teamTotalGold, enemyTotalGold
 }
 
-func NewAugmentedState(stateSeconds float64, previousState *augmentedState) *augmentedState {
+func NewAugmentedState(stateSeconds float64, previousState *augmentedState, turrets map[int64]baseview.Position) *augmentedState {
 	currentState := &augmentedState{
 		Seconds:                 stateSeconds,
 		IsAlive:                 map[int64]bool{},
@@ -242,7 +242,7 @@ func NewAugmentedState(stateSeconds float64, previousState *augmentedState) *aug
 			}
 			currentState.SummonerSpellsAvailable[i] = ssMap
 		}
-		for turretID, _ := range baseview.TurretPositions {
+		for turretID, _ := range turrets {
 			currentState.LiveTurrets[turretID] = previousState.LiveTurrets[turretID]
 		}
 	} else {
@@ -252,43 +252,66 @@ func NewAugmentedState(stateSeconds float64, previousState *augmentedState) *aug
 			currentState.ChampLevels[i] = 1
 			currentState.SummonerSpellsAvailable[i] = map[string]float64{"Summoner1": 0.0, "Summoner2": 0.0}
 		}
-		for turretID, _ := range baseview.TurretPositions {
+		for turretID, _ := range turrets {
 			currentState.LiveTurrets[turretID] = true
 		}
 	}
 	return currentState
 }
 
-type teamFightAnalysis struct {
-	ParticipantID          int64
+// fightState is the recent history of the match that team fights are found
+// in. It is the same for every participant, so the team fight analyses of
+// several participants can share one, as long as it is only updated once per
+// event (see computeAdvanced).
+type fightState struct {
 	RecentStates           *list.List
 	PlayerContinuousDamage map[int64]*continuousDamage
 	PlayerContinuousAttack map[int64]*continuousAttack
-	TeamFights             []*TeamFight
-	MatchDuration          float64
-	WardAnalysis           *wardAnalysis
+	Map                    *baseview.Map
 }
 
-func NewTeamFightAnalysis(participantID int64, matchDuration float64, wardAnalysis *wardAnalysis) *teamFightAnalysis {
+func newFightState(m *baseview.Map) *fightState {
 	continuousDamageMap := map[int64]*continuousDamage{}
 	continuousAttackMap := map[int64]*continuousAttack{}
 	for i := int64(1); i <= 10; i++ {
 		continuousDamageMap[i] = &continuousDamage{}
 		continuousAttackMap[i] = &continuousAttack{}
 	}
-	return &teamFightAnalysis{
-		ParticipantID:          participantID,
+	return &fightState{
 		RecentStates:           list.New(),
 		PlayerContinuousDamage: continuousDamageMap,
 		PlayerContinuousAttack: continuousAttackMap,
-		MatchDuration:          matchDuration,
-		WardAnalysis:           wardAnalysis,
+		Map:                    m,
+	}
+}
+
+type teamFightAnalysis struct {
+	*fightState
+	ParticipantID int64
+	TeamFights    []*TeamFight
+	MatchDuration float64
+	WardAnalysis  *wardAnalysis
+}
+
+func NewTeamFightAnalysis(participantID int64, matchDuration float64, wardAnalysis *wardAnalysis, m *baseview.Map) *teamFightAnalysis {
+	return newTeamFightAnalysis(participantID, matchDuration, wardAnalysis, newFightState(m))
+}
+
+// newTeamFightAnalysis returns a team fight analysis of the participant that
+// shares the state with others. Its Add methods update the state, so use the
+// analyze methods (and update the state once) instead.
+func newTeamFightAnalysis(participantID int64, matchDuration float64, wardAnalysis *wardAnalysis, fs *fightState) *teamFightAnalysis {
+	return &teamFightAnalysis{
+		fightState:    fs,
+		ParticipantID: participantID,
+		MatchDuration: matchDuration,
+		WardAnalysis:  wardAnalysis,
 	}
 }
 
 // findClosest returns the augmentedState closest to the specified time
-func (tfa *teamFightAnalysis) findClosest(time float64) *augmentedState {
-	stateList := tfa.RecentStates
+func (fs *fightState) findClosest(time float64) *augmentedState {
+	stateList := fs.RecentStates
 	closest := 99999.0
 	var prevState *augmentedState
 	for e := stateList.Back(); e != nil; e = e.Prev() {
@@ -307,8 +330,8 @@ func (tfa *teamFightAnalysis) findClosest(time float64) *augmentedState {
 }
 
 // updateRecentHistory adds a new entry to the RecentHistory list if stateSeconds is later than the last entry
-func (tfa *teamFightAnalysis) updateRecentHistory(stateSeconds float64) {
-	stateList := tfa.RecentStates
+func (fs *fightState) updateRecentHistory(stateSeconds float64) {
+	stateList := fs.RecentStates
 	lastElement := stateList.Back()
 	var previousState *augmentedState
 	if lastElement != nil {
@@ -319,7 +342,7 @@ func (tfa *teamFightAnalysis) updateRecentHistory(stateSeconds float64) {
 		}
 	}
 
-	currentState := NewAugmentedState(stateSeconds, previousState)
+	currentState := NewAugmentedState(stateSeconds, previousState, fs.Map.Turrets)
 	stateList.PushBack(currentState)
 
 	// Drop any states that are older than the window duration
@@ -333,12 +356,12 @@ func (tfa *teamFightAnalysis) updateRecentHistory(stateSeconds float64) {
 	}
 }
 
-func (tfa *teamFightAnalysis) updateState(t *baseview.StateUpdate) {
-	if tfa.RecentStates.Back() == nil {
+func (fs *fightState) updateState(t *baseview.StateUpdate) {
+	if fs.RecentStates.Back() == nil {
 		return
 	}
 
-	st := tfa.RecentStates.Back().Value.(*augmentedState)
+	st := fs.RecentStates.Back().Value.(*augmentedState)
 	st.ChampPositions[t.ParticipantID] = &t.Position
 
 	// Any time a player's gold goes down, add that to their gold spent.
@@ -357,12 +380,12 @@ func (tfa *teamFightAnalysis) updateState(t *baseview.StateUpdate) {
 	st.ChampHealthPercentages[t.ParticipantID] = 100 * t.Health / t.HealthMax
 }
 
-func (tfa *teamFightAnalysis) updateDeath(t *baseview.Death) {
-	if tfa.RecentStates.Back() == nil {
+func (fs *fightState) updateDeath(t *baseview.Death) {
+	if fs.RecentStates.Back() == nil {
 		return
 	}
 
-	st := tfa.RecentStates.Back().Value.(*augmentedState)
+	st := fs.RecentStates.Back().Value.(*augmentedState)
 
 	// Setting alive to false at the end allows us to capture team fights where we died
 	st.IsAlive[t.VictimID] = false
@@ -370,15 +393,24 @@ func (tfa *teamFightAnalysis) updateDeath(t *baseview.Death) {
 }
 
 func (tfa *teamFightAnalysis) AddDamage(t *baseview.Damage) {
-	tfa.PlayerContinuousDamage[t.VictimID].Add(t)
+	tfa.addDamage(t)
+	tfa.analyzeDamage(t)
+}
+
+func (fs *fightState) addDamage(t *baseview.Damage) {
+	fs.PlayerContinuousDamage[t.VictimID].Add(t)
 
 	// Keep track of players dealing champion damage
 	if baseview.TeamID(t.AttackerID) != 0 {
-		tfa.PlayerContinuousAttack[t.AttackerID].Add(t)
+		fs.PlayerContinuousAttack[t.AttackerID].Add(t)
 	} else {
-		tfa.findClosest(t.Seconds()).NeutralDamageTaken[t.VictimID] += t.Percent
+		fs.findClosest(t.Seconds()).NeutralDamageTaken[t.VictimID] += t.Percent
 	}
+}
 
+// analyzeDamage starts or updates a team fight with damage that the state
+// already includes.
+func (tfa *teamFightAnalysis) analyzeDamage(t *baseview.Damage) {
 	firstDamage := false
 	// See if this creates a team fight
 	// If someone takes 30% hp damage in multiple hits over a short time period, then start a team fight
@@ -442,9 +474,18 @@ func (tfa *teamFightAnalysis) AddDamage(t *baseview.Damage) {
 }
 
 func (tfa *teamFightAnalysis) AddStateUpdate(t *baseview.StateUpdate) {
-	tfa.updateRecentHistory(t.Seconds())
-	tfa.updateState(t)
+	tfa.addStateUpdate(t)
+	tfa.analyzeStateUpdate(t)
+}
+
+func (fs *fightState) addStateUpdate(t *baseview.StateUpdate) {
+	fs.updateRecentHistory(t.Seconds())
+	fs.updateState(t)
+}
 
+// analyzeStateUpdate ends the current team fight, if the state update (which
+// the state already includes) shows that it is over.
+func (tfa *teamFightAnalysis) analyzeStateUpdate(t *baseview.StateUpdate) {
 	// If we're currently in a team fight, then check to see whether it's over or not
 	curFight := tfa.currentFight()
 	if curFight != nil {
@@ -462,6 +503,13 @@ func (tfa *teamFightAnalysis) AddStateUpdate(t *baseview.StateUpdate) {
 }
 
 func (tfa *teamFightAnalysis) AddDeath(t *baseview.Death) {
+	tfa.analyzeDeath(t)
+	tfa.updateDeath(t)
+}
+
+// analyzeDeath starts or updates a team fight with a death that the state
+// doesn't include yet.
+func (tfa *teamFightAnalysis) analyzeDeath(t *baseview.Death) {
 	curFight := tfa.currentFight()
 
 	// If we're not in a team fight, then start a team fight (provided we're alive and close)
@@ -488,8 +536,6 @@ func (tfa *teamFightAnalysis) AddDeath(t *baseview.Death) {
 			}
 		}
 	}
-
-	tfa.updateDeath(t)
 }
 
 // startTeamFight creates a team fight centered around a particular participant
@@ -579,32 +625,32 @@ func (tfa *teamFightAnalysis) startTeamFight(pID int64, triggerTime float64) {
 	tfa.TeamFights = append(tfa.TeamFights, tf)
 }
 
-func (tfa *teamFightAnalysis) AddLevelUp(t *baseview.LevelUp) {
-	if tfa.RecentStates.Back() == nil {
+func (fs *fightState) AddLevelUp(t *baseview.LevelUp) {
+	if fs.RecentStates.Back() == nil {
 		return
 	}
 
-	st := tfa.RecentStates.Back().Value.(*augmentedState)
+	st := fs.RecentStates.Back().Value.(*augmentedState)
 	st.ChampLevels[t.ParticipantID]++
 }
 
-func (tfa *teamFightAnalysis) AddAttack(t *baseview.Attack) {
-	if tfa.RecentStates.Back() == nil {
+func (fs *fightState) AddAttack(t *baseview.Attack) {
+	if fs.RecentStates.Back() == nil {
 		return
 	}
 
-	st := tfa.RecentStates.Back().Value.(*augmentedState)
+	st := fs.RecentStates.Back().Value.(*augmentedState)
 	if t.Slot == "Summoner1" || t.Slot == "Summoner2" {
 		st.SummonerSpellsAvailable[t.AttackerID][t.Slot] = t.CooldownExpires
 	}
 }
 
-func (tfa *teamFightAnalysis) AddTurretKill(t *baseview.BuildingKill) {
-	if tfa.RecentStates.Back() == nil {
+func (fs *fightState) AddTurretKill(t *baseview.BuildingKill) {
+	if fs.RecentStates.Back() == nil {
 		return
 	}
 
-	st := tfa.RecentStates.Back().Value.(*augmentedState)
+	st := fs.RecentStates.Back().Value.(*augmentedState)
 	st.LiveTurrets[t.BuildingID] = false
 }
 
@@ -643,7 +689,7 @@ func (tfa *teamFightAnalysis) IsInVision(pID int64, time float64) bool {
 
 		// Only count champs in the jungle as being out of vision.
 		// This is an oversimplification, but it's closer to being correct
-		if baseview.AreaToRegion(baseview.AreaID(*st.ChampPositions[pID])) != baseview.RegionJungle {
+		if baseview.AreaToRegion(tfa.Map.AreaID(*st.ChampPositions[pID])) != baseview.RegionJungle {
 			return true
 		}
 
@@ -660,7 +706,7 @@ func (tfa *teamFightAnalysis) IsInVision(pID int64, time float64) bool {
 		}
 
 		// Track Towers
-		for turretID, turretPosition := range baseview.TurretPositions {
+		for turretID, turretPosition := range tfa.Map.Turrets {
 			if turretID/100 != baseview.TeamID(pID)/100 && st.LiveTurrets[turretID] {
 				if st.ChampPositions[pID].DistanceXY(turretPosition) < 1095 {
 					return true
@@ -23,6 +23,9 @@ type wardLife struct {
 	AttacksAgainst []baseview.Attack
 	ClearedBy      int64
 	Reveals        map[int64][]*TimeInterval
+	// Area and Features name the regions of summoner's rift the ward is in.
+	Area     string
+	Features []string
 }
 
 // JsonWardLife is a slimmed down version of WardLife, suitable for use outside this file.
@@ -34,6 +37,8 @@ type JsonWardLife struct {
 	Type      string             `json:"type"`
 	EndReason string             `json:"end_reason"`
 	ClearedBy int64              `json:"cleared_by,omitempty"`
+	Area      string             `json:"area,omitempty"`
+	Features  []string           `json:"features,omitempty"`
 	// map of participantID -> number of times that participant was revealed by this ward
 	Reveals map[int64]int `json:"reveals"`
 }
@@ -66,6 +71,13 @@ func (wa *wardAnalysis) AddWardPlaced(t *baseview.WardPlaced, gameLengthSeconds
 		AttacksAgainst: []baseview.Attack{},
 		Reveals:        map[int64][]*TimeInterval{},
 	}
+	sr := baseview.SummonersRift()
+	if area := sr.AreaAt(t.Position); area != nil {
+		life.Area = area.Name
+	}
+	for _, f := range sr.FeaturesAt(t.Position) {
+		life.Features = append(life.Features, f.Name)
+	}
 	wa.ActiveWards[t.WardID] = life
 	wa.PlayerWards[t.ParticipantID] = append(wa.PlayerWards[t.ParticipantID], life)
 }
@@ -229,6 +241,8 @@ func (wa *wardAnalysis) ExportPlayerWards() map[int64][]*JsonWardLife {
 				Type:      wl.Type,
 				EndReason: wl.EndReason,
 				ClearedBy: wl.ClearedBy,
+				Area:      wl.Area,
+				Features:  wl.Features,
 				Reveals:   revealMap,
 			})
 		}
@@ -24,6 +24,8 @@ func TestWardPlaced(t *testing.T) {
 
 	// We should have an active ward with id 90 linked to the creator
 	is.Equal(1, wa.ActiveWards[90].Creator)
+	is.Equal("blue_base", wa.ActiveWards[90].Area)
+	is.Equal(0, len(wa.ActiveWards[90].Features))
 }
 
 func TestWardDeathCleared(t *testing.T) {
@@ -24,6 +24,8 @@ It has these top-level messages:
 	SearchRequest
 	ReplayEntry
 	SearchResponse
+	HeatmapRequest
+	HeatmapResponse
 	GoalCreateStatsRequest
 	GoalCreateCustomRequest
 	GoalGetRequest
@@ -885,6 +887,80 @@ func (m *SearchResponse) GetCount() int64 {
 	return 0
 }
 
+type HeatmapRequest struct {
+	SummonerId   int64  `protobuf:"varint,1,opt,name=summoner_id,json=summonerId" json:"summoner_id,omitempty"`
+	Platform     string `protobuf:"bytes,2,opt,name=platform" json:"platform,omitempty"`
+	LastN        int32  `protobuf:"varint,3,opt,name=last_n,json=lastN" json:"last_n,omitempty"`
+	RolePosition string `protobuf:"bytes,4,opt,name=role_position,json=rolePosition" json:"role_position,omitempty"`
+	QueueType    string `protobuf:"bytes,5,opt,name=queue_type,json=queueType" json:"queue_type,omitempty"`
+}
+
+func (m *HeatmapRequest) Reset()                    { *m = HeatmapRequest{} }
+func (m *HeatmapRequest) String() string            { return proto.CompactTextString(m) }
+func (*HeatmapRequest) ProtoMessage()               {}
+func (*HeatmapRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *HeatmapRequest) GetSummonerId() int64 {
+	if m != nil {
+		return m.SummonerId
+	}
+	return 0
+}
+
+func (m *HeatmapRequest) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *HeatmapRequest) GetLastN() int32 {
+	if m != nil {
+		return m.LastN
+	}
+	return 0
+}
+
+func (m *HeatmapRequest) GetRolePosition() string {
+	if m != nil {
+		return m.RolePosition
+	}
+	return ""
+}
+
+func (m *HeatmapRequest) GetQueueType() string {
+	if m != nil {
+		return m.QueueType
+	}
+	return ""
+}
+
+// heatmapJson is the summoner's ingest.Heatmaps merged over the last_n
+// matches, of which count had heatmaps.
+type HeatmapResponse struct {
+	HeatmapJson string `protobuf:"bytes,1,opt,name=heatmapJson" json:"heatmapJson,omitempty"`
+	Count       int64  `protobuf:"varint,2,opt,name=count" json:"count,omitempty"`
+}
+
+func (m *HeatmapResponse) Reset()                    { *m = HeatmapResponse{} }
+func (m *HeatmapResponse) String() string            { return proto.CompactTextString(m) }
+func (*HeatmapResponse) ProtoMessage()               {}
+func (*HeatmapResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *HeatmapResponse) GetHeatmapJson() string {
+	if m != nil {
+		return m.HeatmapJson
+	}
+	return ""
+}
+
+func (m *HeatmapResponse) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
 type GoalCreateStatsRequest struct {
 	SummonerId             int64          `protobuf:"varint,1,opt,name=summoner_id,json=summonerId" json:"summoner_id,omitempty"`
 	Platform               string         `protobuf:"bytes,2,opt,name=platform" json:"platform,omitempty"`
@@ -897,7 +973,7 @@ type GoalCreateStatsRequest struct {
 func (m *GoalCreateStatsRequest) Reset()                    { *m = GoalCreateStatsRequest{} }
 func (m *GoalCreateStatsRequest) String() string            { return proto.CompactTextString(m) }
 func (*GoalCreateStatsRequest) ProtoMessage()               {}
-func (*GoalCreateStatsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*GoalCreateStatsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 func (m *GoalCreateStatsRequest) GetSummonerId() int64 {
 	if m != nil {
@@ -951,7 +1027,7 @@ type GoalCreateCustomRequest struct {
 func (m *GoalCreateCustomRequest) Reset()                    { *m = GoalCreateCustomRequest{} }
 func (m *GoalCreateCustomRequest) String() string            { return proto.CompactTextString(m) }
 func (*GoalCreateCustomRequest) ProtoMessage()               {}
-func (*GoalCreateCustomRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*GoalCreateCustomRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *GoalCreateCustomRequest) GetSummonerId() int64 {
 	if m != nil {
@@ -992,7 +1068,7 @@ type GoalGetRequest struct {
 func (m *GoalGetRequest) Reset()                    { *m = GoalGetRequest{} }
 func (m *GoalGetRequest) String() string            { return proto.CompactTextString(m) }
 func (*GoalGetRequest) ProtoMessage()               {}
-func (*GoalGetRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*GoalGetRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
 func (m *GoalGetRequest) GetSummonerId() int64 {
 	if m != nil {
@@ -1036,7 +1112,7 @@ type GoalGetResponse struct {
 func (m *GoalGetResponse) Reset()                    { *m = GoalGetResponse{} }
 func (m *GoalGetResponse) String() string            { return proto.CompactTextString(m) }
 func (*GoalGetResponse) ProtoMessage()               {}
-func (*GoalGetResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (*GoalGetResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *GoalGetResponse) GetGoals() []*Goal {
 	if m != nil {
@@ -1055,7 +1131,7 @@ type GoalUpdateStatusRequest struct {
 func (m *GoalUpdateStatusRequest) Reset()                    { *m = GoalUpdateStatusRequest{} }
 func (m *GoalUpdateStatusRequest) String() string            { return proto.CompactTextString(m) }
 func (*GoalUpdateStatusRequest) ProtoMessage()               {}
-func (*GoalUpdateStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+func (*GoalUpdateStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
 func (m *GoalUpdateStatusRequest) GetSummonerId() int64 {
 	if m != nil {
@@ -1094,7 +1170,7 @@ type GoalDeleteRequest struct {
 func (m *GoalDeleteRequest) Reset()                    { *m = GoalDeleteRequest{} }
 func (m *GoalDeleteRequest) String() string            { return proto.CompactTextString(m) }
 func (*GoalDeleteRequest) ProtoMessage()               {}
-func (*GoalDeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+func (*GoalDeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
 
 func (m *GoalDeleteRequest) GetSummonerId() int64 {
 	if m != nil {
@@ -1139,7 +1215,7 @@ type Goal struct {
 func (m *Goal) Reset()                    { *m = Goal{} }
 func (m *Goal) String() string            { return proto.CompactTextString(m) }
 func (*Goal) ProtoMessage()               {}
-func (*Goal) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+func (*Goal) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 func (m *Goal) GetId() string {
 	if m != nil {
@@ -1260,7 +1336,7 @@ type CountResponse struct {
 func (m *CountResponse) Reset()                    { *m = CountResponse{} }
 func (m *CountResponse) String() string            { return proto.CompactTextString(m) }
 func (*CountResponse) ProtoMessage()               {}
-func (*CountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+func (*CountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 func (m *CountResponse) GetCount() int64 {
 	if m != nil {
@@ -1275,7 +1351,7 @@ type SimpleResponse struct {
 func (m *SimpleResponse) Reset()                    { *m = SimpleResponse{} }
 func (m *SimpleResponse) String() string            { return proto.CompactTextString(m) }
 func (*SimpleResponse) ProtoMessage()               {}
-func (*SimpleResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+func (*SimpleResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
 
 func init() {
 	proto.RegisterType((*MatchSummary)(nil), "lolstats.MatchSummary")
@@ -1294,6 +1370,8 @@ func init() {
 	proto.RegisterType((*SearchRequest)(nil), "lolstats.SearchRequest")
 	proto.RegisterType((*ReplayEntry)(nil), "lolstats.ReplayEntry")
 	proto.RegisterType((*SearchResponse)(nil), "lolstats.SearchResponse")
+	proto.RegisterType((*HeatmapRequest)(nil), "lolstats.HeatmapRequest")
+	proto.RegisterType((*HeatmapResponse)(nil), "lolstats.HeatmapResponse")
 	proto.RegisterType((*GoalCreateStatsRequest)(nil), "lolstats.GoalCreateStatsRequest")
 	proto.RegisterType((*GoalCreateCustomRequest)(nil), "lolstats.GoalCreateCustomRequest")
 	proto.RegisterType((*GoalGetRequest)(nil), "lolstats.GoalGetRequest")
@@ -1327,6 +1405,7 @@ type LolstatsClient interface {
 	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
 	TeamDetails(ctx context.Context, in *TeamRequest, opts ...grpc.CallOption) (*TeamDetailsResponse, error)
 	TeamAdvanced(ctx context.Context, in *TeamRequest, opts ...grpc.CallOption) (*TeamAdvancedStats, error)
+	Heatmap(ctx context.Context, in *HeatmapRequest, opts ...grpc.CallOption) (*HeatmapResponse, error)
 }
 
 type lolstatsClient struct {
@@ -1409,6 +1488,15 @@ func (c *lolstatsClient) TeamAdvanced(ctx context.Context, in *TeamRequest, opts
 	return out, nil
 }
 
+func (c *lolstatsClient) Heatmap(ctx context.Context, in *HeatmapRequest, opts ...grpc.CallOption) (*HeatmapResponse, error) {
+	out := new(HeatmapResponse)
+	err := grpc.Invoke(ctx, "/lolstats.Lolstats/Heatmap", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Lolstats service
 
 type LolstatsServer interface {
@@ -1420,6 +1508,7 @@ type LolstatsServer interface {
 	Search(context.Context, *SearchRequest) (*SearchResponse, error)
 	TeamDetails(context.Context, *TeamRequest) (*TeamDetailsResponse, error)
 	TeamAdvanced(context.Context, *TeamRequest) (*TeamAdvancedStats, error)
+	Heatmap(context.Context, *HeatmapRequest) (*HeatmapResponse, error)
 }
 
 func RegisterLolstatsServer(s *grpc.Server, srv LolstatsServer) {
@@ -1570,6 +1659,24 @@ func _Lolstats_TeamAdvanced_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lolstats_Heatmap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeatmapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LolstatsServer).Heatmap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lolstats.Lolstats/Heatmap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LolstatsServer).Heatmap(ctx, req.(*HeatmapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Lolstats_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "lolstats.Lolstats",
 	HandlerType: (*LolstatsServer)(nil),
@@ -1606,6 +1713,10 @@ var _Lolstats_serviceDesc = grpc.ServiceDesc{
 			MethodName: "TeamAdvanced",
 			Handler:    _Lolstats_TeamAdvanced_Handler,
 		},
+		{
+			MethodName: "Heatmap",
+			Handler:    _Lolstats_Heatmap_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "lolstats.proto",
@@ -1810,133 +1921,137 @@ var _Lolgoals_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("lolstats.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 2038 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xbc, 0x58, 0x4b, 0x73, 0xe3, 0xc6,
-	0x11, 0x16, 0x48, 0xf1, 0xd5, 0xa4, 0x48, 0x6a, 0xf4, 0xc2, 0x72, 0xb3, 0x65, 0x19, 0x6b, 0x27,
-	0x2a, 0x39, 0xd1, 0x6e, 0x29, 0xa9, 0x64, 0xe3, 0xf2, 0xba, 0x42, 0x53, 0x28, 0xad, 0x6c, 0x89,
-	0x52, 0x40, 0xae, 0xb7, 0x92, 0x54, 0x82, 0xc2, 0x12, 0x43, 0x0a, 0x65, 0x00, 0x03, 0x03, 0x03,
-	0x79, 0x99, 0x7f, 0x90, 0x4b, 0x2e, 0x39, 0xe5, 0x90, 0x9b, 0x73, 0xc8, 0x21, 0x3f, 0x22, 0xff,
-	0x22, 0x3f, 0x22, 0xd7, 0xdc, 0x72, 0x48, 0xcd, 0x0c, 0xde, 0x7c, 0xec, 0xc6, 0x76, 0xf6, 0x86,
-	0xee, 0x69, 0x4c, 0xf7, 0x74, 0xf7, 0xf7, 0xcd, 0x03, 0xda, 0x36, 0xb1, 0x03, 0x6a, 0xd0, 0xe0,
-	0xc4, 0xf3, 0x09, 0x25, 0xa8, 0x1e, 0xcb, 0xbd, 0x77, 0x66, 0x84, 0xcc, 0x6c, 0xfc, 0x88, 0xeb,
-	0x5f, 0x86, 0xd3, 0x47, 0xd4, 0x72, 0x70, 0x40, 0x0d, 0xc7, 0x13, 0xa6, 0xca, 0xd7, 0x15, 0x68,
-	0x5d, 0x19, 0x74, 0x72, 0x3b, 0x0a, 0x1d, 0xc7, 0xf0, 0xe7, 0xe8, 0x1d, 0x68, 0x06, 0xa1, 0xe3,
-	0x10, 0x17, 0xfb, 0xba, 0x65, 0xca, 0xd2, 0xa1, 0x74, 0x54, 0xd6, 0x20, 0x56, 0x5d, 0x98, 0xa8,
-	0x07, 0x75, 0xcf, 0x36, 0xe8, 0x94, 0xf8, 0x8e, 0x5c, 0x3a, 0x94, 0x8e, 0x1a, 0x5a, 0x22, 0xa3,
-	0xf7, 0xa1, 0xed, 0xb0, 0xc9, 0xf4, 0x89, 0x8f, 0x0d, 0x6a, 0x11, 0x57, 0xde, 0xe4, 0xff, 0x6f,
-	0x71, 0xed, 0x20, 0x52, 0x32, 0x1f, 0x93, 0x5b, 0xc3, 0xf1, 0x2c, 0xe2, 0x32, 0x1f, 0x95, 0x43,
-	0xe9, 0xa8, 0xa2, 0x41, 0xac, 0xba, 0x30, 0x11, 0x82, 0x4d, 0xdb, 0x70, 0xb1, 0x5c, 0xe5, 0xf3,
-	0xf3, 0x6f, 0x36, 0x77, 0xfa, 0x93, 0x6b, 0xe2, 0x57, 0x72, 0x8d, 0xff, 0xb7, 0x95, 0xfc, 0xc7,
-	0x94, 0x68, 0x0f, 0xaa, 0x8e, 0xe1, 0xb1, 0x69, 0xeb, 0x7c, 0xb8, 0xe2, 0x18, 0xde, 0x85, 0x99,
-	0x46, 0x66, 0x86, 0xbe, 0x88, 0xac, 0x71, 0x28, 0x1d, 0x49, 0x51, 0x64, 0x67, 0x91, 0x12, 0xdd,
-	0x83, 0xba, 0x30, 0xb3, 0x4c, 0x19, 0x78, 0xe8, 0x35, 0x2e, 0x5f, 0x98, 0xe8, 0x01, 0x80, 0x18,
-	0x72, 0x88, 0x89, 0xe5, 0x26, 0x8f, 0xac, 0xc1, 0x35, 0x57, 0xc4, 0xc4, 0xe9, 0x30, 0x9d, 0x7b,
-	0x58, 0x6e, 0x65, 0x86, 0xc7, 0x73, 0x0f, 0xa3, 0x87, 0x20, 0x3c, 0xe9, 0x77, 0xd8, 0x0f, 0x98,
-	0xfb, 0x2d, 0x6e, 0xd1, 0xe2, 0xca, 0xcf, 0x85, 0x0e, 0x3d, 0x86, 0x5d, 0xe2, 0x79, 0xc4, 0xc5,
-	0x2e, 0xd5, 0xb3, 0x09, 0xea, 0xf0, 0x95, 0xa0, 0x78, 0x6c, 0x90, 0x26, 0xea, 0x01, 0xc0, 0x97,
-	0x21, 0x0e, 0xb1, 0xf0, 0xda, 0x15, 0x5e, 0xb9, 0x86, 0x7b, 0x45, 0xb0, 0xe9, 0x13, 0x1b, 0xcb,
-	0xdb, 0x22, 0x8f, 0xec, 0x9b, 0x45, 0x92, 0x14, 0xd8, 0x35, 0x1c, 0x2c, 0x23, 0x11, 0x49, 0xac,
-	0x1c, 0x1a, 0x0e, 0x46, 0xfb, 0x50, 0xfd, 0xca, 0x72, 0x5d, 0xec, 0xcb, 0x3b, 0x87, 0xd2, 0x51,
-	0x5d, 0x8b, 0x24, 0x36, 0x21, 0xb5, 0xb0, 0x2f, 0xef, 0x8a, 0x09, 0xd9, 0x37, 0x6b, 0x08, 0xd3,
-	0xba, 0xb3, 0xf8, 0xaa, 0xf6, 0x44, 0x43, 0xc4, 0x32, 0xda, 0x85, 0xca, 0x17, 0x96, 0x6d, 0x07,
-	0xf2, 0x3e, 0x4f, 0xa6, 0x10, 0xd8, 0xec, 0x26, 0x36, 0xe8, 0x6d, 0x20, 0x1f, 0x70, 0x75, 0x24,
-	0x21, 0x19, 0x6a, 0x46, 0x10, 0x58, 0x01, 0x0d, 0x64, 0x59, 0x24, 0x3f, 0x12, 0x95, 0x63, 0x80,
-	0x4f, 0x8c, 0xc0, 0x9a, 0x8c, 0x58, 0x57, 0xa3, 0xef, 0x41, 0x83, 0xb7, 0xf7, 0xa7, 0x01, 0x71,
-	0x79, 0x87, 0x36, 0xb4, 0x54, 0xa1, 0xfc, 0x08, 0xb6, 0xfa, 0xe6, 0x9d, 0xe1, 0x4e, 0xb0, 0xf9,
-	0x26, 0xe6, 0x7f, 0x92, 0x60, 0x7b, 0x8c, 0x0d, 0x27, 0xff, 0xcf, 0x27, 0x50, 0xf3, 0x71, 0x10,
-	0xda, 0x34, 0x90, 0xa5, 0xc3, 0xf2, 0x51, 0xf3, 0xf4, 0xe8, 0x24, 0x01, 0xd9, 0x82, 0xf5, 0x89,
-	0x26, 0x4c, 0x55, 0x97, 0xfa, 0x73, 0x2d, 0xfe, 0xb1, 0xf7, 0x21, 0xb4, 0xb2, 0x03, 0xa8, 0x0b,
-	0xe5, 0x2f, 0xf0, 0x3c, 0x82, 0x14, 0xfb, 0x64, 0xe9, 0xb9, 0x33, 0xec, 0x10, 0x47, 0x40, 0x12,
-	0xc2, 0x87, 0xa5, 0x27, 0x92, 0xf2, 0x67, 0x09, 0xda, 0xcf, 0xac, 0x80, 0x12, 0x7f, 0xae, 0xe1,
-	0x2f, 0x43, 0x1c, 0xd0, 0x6f, 0x87, 0xcc, 0x5d, 0xa8, 0xd8, 0x96, 0x63, 0x51, 0xb9, 0x2c, 0x50,
-	0xc1, 0x05, 0x56, 0x88, 0x49, 0xe8, 0x07, 0xc4, 0xe7, 0x18, 0x6c, 0x68, 0x91, 0xc4, 0x32, 0x96,
-	0x34, 0x51, 0x04, 0xc2, 0x54, 0xa1, 0xfc, 0x06, 0x3a, 0x49, 0x68, 0x81, 0x47, 0xdc, 0x00, 0xa3,
-	0xc7, 0x20, 0x70, 0x82, 0xe3, 0x74, 0xed, 0xa7, 0xe9, 0xca, 0xd2, 0x8b, 0x16, 0x9b, 0x65, 0x5c,
-	0x97, 0xb2, 0xae, 0x95, 0x3f, 0x96, 0xa1, 0xc5, 0x93, 0x1a, 0x2f, 0x5b, 0x86, 0x5a, 0x80, 0x6d,
-	0x3c, 0x89, 0x2a, 0xd1, 0xd0, 0x62, 0x71, 0xed, 0x7a, 0xf3, 0xc0, 0x28, 0x17, 0x81, 0xf1, 0x2e,
-	0xb4, 0x3c, 0x0e, 0x47, 0xcf, 0xc7, 0x53, 0xeb, 0x15, 0xa7, 0xa9, 0x86, 0xd6, 0xe4, 0xba, 0x1b,
-	0xae, 0x62, 0x44, 0x62, 0x1b, 0x01, 0xd5, 0xdd, 0x88, 0x9f, 0x2a, 0x4c, 0x1a, 0x26, 0x08, 0xa8,
-	0xae, 0x40, 0x40, 0xad, 0x80, 0x80, 0x98, 0xca, 0xea, 0x19, 0x2a, 0x8b, 0x61, 0xd9, 0xc8, 0xc0,
-	0xb2, 0xc0, 0x89, 0xb0, 0xc0, 0x89, 0xab, 0xc8, 0xa1, 0xb9, 0x92, 0x1c, 0x0a, 0x0d, 0xd3, 0x5a,
-	0x68, 0x98, 0x87, 0xb0, 0xc5, 0x7c, 0xeb, 0x1e, 0x09, 0x2c, 0x9a, 0x21, 0x25, 0xa6, 0xbc, 0x89,
-	0x74, 0xca, 0xbf, 0x24, 0xe8, 0xb2, 0x82, 0x5c, 0x5a, 0x01, 0x4d, 0xea, 0xfd, 0x31, 0x54, 0x45,
-	0x97, 0x47, 0xe5, 0xfe, 0x7e, 0x5a, 0xee, 0xa2, 0x6d, 0x04, 0x0e, 0x81, 0x8d, 0xe8, 0x2f, 0xd6,
-	0x8e, 0x13, 0x12, 0xba, 0x94, 0xd7, 0xad, 0xac, 0x09, 0xa1, 0xa7, 0x40, 0x3d, 0xfe, 0x9b, 0xf5,
-	0x07, 0x47, 0x83, 0xa8, 0xba, 0xa4, 0x45, 0x52, 0xef, 0x77, 0xd0, 0xcc, 0x4c, 0x98, 0xc5, 0x54,
-	0x43, 0x60, 0xea, 0xe7, 0x59, 0x4c, 0x35, 0x4f, 0x1f, 0xae, 0x89, 0x2c, 0x51, 0x64, 0x80, 0x37,
-	0x8d, 0xf6, 0xc3, 0xef, 0x04, 0x75, 0xd9, 0xed, 0xa4, 0x9c, 0xdb, 0x4e, 0x94, 0xa7, 0xd0, 0x39,
-	0xc3, 0xd4, 0xb0, 0xec, 0x20, 0x49, 0xea, 0x31, 0x54, 0x5e, 0x32, 0x92, 0xe3, 0x4e, 0x9a, 0xa7,
-	0xbb, 0x69, 0xe4, 0x29, 0xf7, 0x69, 0xc2, 0x44, 0xf9, 0x2d, 0x34, 0x19, 0x0d, 0xc5, 0x51, 0x1e,
-	0x40, 0x8d, 0x62, 0xc3, 0x49, 0x23, 0xac, 0x32, 0xf1, 0x9b, 0x47, 0xf7, 0x77, 0x09, 0x76, 0xd8,
-	0xfc, 0xc5, 0x10, 0xcf, 0x8a, 0xb4, 0x78, 0x9c, 0xa7, 0xc5, 0x82, 0xfd, 0x0a, 0x62, 0xbc, 0x79,
-	0x2d, 0x31, 0x1e, 0xe7, 0x8b, 0xb8, 0x22, 0x15, 0x69, 0xd5, 0xfe, 0x53, 0x82, 0xad, 0x11, 0x36,
-	0xfc, 0xb4, 0x6e, 0xd9, 0x85, 0x4b, 0x6b, 0xc9, 0xa1, 0xf4, 0x3a, 0x72, 0x28, 0xaf, 0x23, 0x07,
-	0x71, 0xc0, 0x89, 0xc8, 0x61, 0x17, 0x2a, 0x8c, 0x10, 0x02, 0xb9, 0xc2, 0x99, 0x4a, 0x08, 0x8b,
-	0x30, 0xab, 0x2e, 0xc2, 0xac, 0x88, 0xff, 0x9a, 0xe8, 0xb3, 0x37, 0xc0, 0x7f, 0x9d, 0x5b, 0x2e,
-	0xc3, 0xff, 0x3d, 0xa8, 0x53, 0xe2, 0xe9, 0x2c, 0x6f, 0x11, 0xd5, 0xd4, 0x28, 0xf1, 0x58, 0xee,
-	0x58, 0x06, 0x66, 0x84, 0x98, 0xba, 0xd8, 0x9c, 0x81, 0x47, 0xdb, 0x60, 0x9a, 0xcf, 0xf8, 0x06,
-	0x7d, 0x02, 0x3b, 0xf8, 0xd5, 0xc4, 0x0e, 0x4d, 0xac, 0x67, 0x9b, 0xbf, 0xc9, 0x5d, 0x6d, 0x47,
-	0x43, 0xa3, 0x04, 0x03, 0xca, 0x3f, 0x4b, 0x0c, 0x95, 0x9e, 0x6d, 0xcc, 0x45, 0x41, 0xb3, 0x9d,
-	0x25, 0xe5, 0x8f, 0x51, 0xeb, 0x1a, 0x72, 0xd5, 0x12, 0xcb, 0x2b, 0x97, 0xf8, 0x18, 0xf6, 0x66,
-	0xc4, 0x36, 0x75, 0xd3, 0x9a, 0x4e, 0xf5, 0xdf, 0x63, 0x9f, 0xe8, 0x94, 0xe8, 0x14, 0x8b, 0xb2,
-	0x48, 0xda, 0x36, 0x1b, 0x3c, 0xb3, 0xa6, 0xd3, 0x5f, 0x63, 0x9f, 0x8c, 0xc9, 0x18, 0xbb, 0x68,
-	0x90, 0x5b, 0x79, 0x85, 0x37, 0xf1, 0x7b, 0x69, 0x7b, 0x65, 0x56, 0x71, 0x72, 0x1e, 0xe7, 0x43,
-	0xb4, 0x6f, 0x26, 0x3f, 0xcb, 0x36, 0x81, 0x03, 0xa8, 0x4d, 0x02, 0x1e, 0x08, 0x2f, 0x9e, 0xa4,
-	0x55, 0x27, 0x01, 0x73, 0xdd, 0xfb, 0x08, 0xda, 0xf9, 0x99, 0x96, 0x90, 0x56, 0xee, 0x20, 0x50,
-	0xce, 0x76, 0xf6, 0x0b, 0x68, 0xc7, 0x8d, 0x1d, 0x61, 0xf0, 0x51, 0x11, 0x83, 0x7b, 0x4b, 0xc3,
-	0x4f, 0xe0, 0xb6, 0x9c, 0x6c, 0x95, 0x3f, 0x94, 0x60, 0xff, 0x9c, 0x18, 0x36, 0x3f, 0x95, 0xe3,
-	0xdc, 0x96, 0xfb, 0xad, 0x38, 0x6f, 0xa1, 0xdb, 0xcb, 0x4b, 0xba, 0xfd, 0x3e, 0x34, 0xdc, 0xd0,
-	0xd1, 0x67, 0xc4, 0xb0, 0x83, 0x08, 0x42, 0x75, 0x37, 0x74, 0x58, 0x3c, 0x01, 0x7a, 0x02, 0x32,
-	0x35, 0xfc, 0x19, 0xa6, 0xba, 0x31, 0xb9, 0xb5, 0xf0, 0x1d, 0x76, 0x78, 0x43, 0xf0, 0x25, 0x54,
-	0xb8, 0xed, 0xbe, 0x18, 0xef, 0xa7, 0xc3, 0x03, 0x36, 0x8a, 0x7e, 0x0a, 0x30, 0x31, 0x28, 0x9e,
-	0x11, 0xdf, 0xc2, 0x81, 0x5c, 0x3d, 0x2c, 0x1f, 0xb5, 0xb3, 0x27, 0x11, 0xbe, 0x5c, 0x31, 0x3e,
-	0xd7, 0x32, 0x96, 0xca, 0xd7, 0x12, 0x1c, 0xa4, 0xb9, 0x18, 0x84, 0x01, 0x25, 0xce, 0xdb, 0x4b,
-	0xc6, 0x0f, 0xa0, 0x13, 0xba, 0x26, 0xf6, 0xed, 0xb9, 0xe5, 0xce, 0x04, 0x5c, 0xc5, 0x79, 0xa4,
-	0x9d, 0xaa, 0x59, 0x7d, 0x94, 0x7f, 0x48, 0xac, 0x95, 0x0c, 0xfb, 0x1c, 0xd3, 0xb7, 0x17, 0x5d,
-	0x81, 0x98, 0x36, 0x17, 0x88, 0xe9, 0x87, 0x50, 0x65, 0x31, 0x87, 0x01, 0x2f, 0x4e, 0x3b, 0x4b,
-	0xd6, 0x2c, 0xd8, 0x11, 0x1f, 0xd3, 0x22, 0x1b, 0xe5, 0x67, 0xd0, 0x49, 0x96, 0x10, 0x35, 0xf4,
-	0x7b, 0x50, 0x11, 0x8d, 0x20, 0xda, 0xb9, 0x9d, 0xff, 0x5f, 0x13, 0x83, 0xca, 0x5f, 0xa2, 0x1a,
-	0x3d, 0xf7, 0xcc, 0xa8, 0x5f, 0xc3, 0xef, 0xa6, 0x61, 0x0f, 0xa0, 0xc6, 0x3c, 0xc4, 0x44, 0xd3,
-	0xd0, 0xaa, 0x4c, 0xcc, 0x2d, 0x6c, 0xf3, 0x0d, 0x16, 0x66, 0xc1, 0x36, 0xd3, 0x9e, 0x61, 0x1b,
-	0x53, 0xfc, 0x7f, 0x0d, 0x4c, 0xf9, 0x5b, 0x05, 0x36, 0x99, 0x2f, 0xd4, 0x86, 0x52, 0x34, 0x6b,
-	0x43, 0x2b, 0x59, 0x26, 0xfa, 0x09, 0xd4, 0xf8, 0xcd, 0x1b, 0x9b, 0xd1, 0xc6, 0xd9, 0x3b, 0x11,
-	0x0f, 0x00, 0x27, 0xf1, 0x03, 0xc0, 0xc9, 0x38, 0x7e, 0x00, 0xd0, 0x62, 0x53, 0xf4, 0x14, 0x5a,
-	0x7c, 0x33, 0x0b, 0x79, 0x66, 0x85, 0xb3, 0xf5, 0xbf, 0x36, 0x99, 0xbd, 0x28, 0xc4, 0xff, 0x98,
-	0xa6, 0x62, 0x46, 0x2a, 0x6b, 0x33, 0x52, 0x2d, 0x64, 0x64, 0x09, 0x52, 0x6a, 0xcb, 0x90, 0xc2,
-	0xb6, 0xf0, 0x88, 0x42, 0x04, 0xad, 0xd6, 0x39, 0x23, 0x37, 0x85, 0xee, 0x73, 0xa6, 0x42, 0x4f,
-	0x00, 0x26, 0xc4, 0xf1, 0x0c, 0xdf, 0xa0, 0xc4, 0xe7, 0xfb, 0x63, 0xfb, 0x54, 0x2e, 0x70, 0x45,
-	0x32, 0xae, 0x65, 0x6c, 0xd1, 0x07, 0xb0, 0xbd, 0x48, 0x4c, 0xe2, 0xb5, 0xa0, 0x6b, 0x14, 0x29,
-	0x69, 0x1d, 0x99, 0x35, 0xd7, 0x92, 0xd9, 0x07, 0xb0, 0x6d, 0x39, 0x1e, 0xf1, 0x29, 0xbb, 0x68,
-	0xea, 0x5f, 0x61, 0x6b, 0x76, 0x4b, 0xf9, 0x21, 0x5e, 0xd2, 0xba, 0xe9, 0xc0, 0x0b, 0xae, 0x5f,
-	0x84, 0x72, 0xfb, 0xf5, 0x50, 0xee, 0x2c, 0x40, 0xf9, 0x14, 0xea, 0x11, 0x2b, 0xce, 0xf9, 0x63,
-	0xc2, 0x6a, 0xf6, 0x4c, 0xec, 0xd8, 0x51, 0x82, 0x77, 0x8f, 0x48, 0xf4, 0x36, 0x8f, 0xaf, 0xc1,
-	0x34, 0x3c, 0xcd, 0xca, 0xfb, 0xb0, 0xc5, 0x97, 0x93, 0xa0, 0x3d, 0xd9, 0x8d, 0xa4, 0xec, 0x6e,
-	0xd4, 0x85, 0xf6, 0xc8, 0x72, 0x3c, 0x1b, 0xc7, 0x76, 0xc7, 0x53, 0x80, 0xb4, 0x7d, 0x50, 0x07,
-	0x9a, 0xa3, 0x71, 0x7f, 0xfc, 0x7c, 0xa4, 0x0f, 0xaf, 0x87, 0x6a, 0x77, 0x03, 0xd5, 0xa0, 0x3c,
-	0x54, 0x5f, 0x74, 0x25, 0xd4, 0x82, 0x7a, 0x7f, 0x30, 0x50, 0x6f, 0xc6, 0xea, 0x59, 0xb7, 0x84,
-	0xb6, 0xa0, 0x31, 0xb8, 0xbe, 0xba, 0xb9, 0x54, 0x99, 0xb8, 0xc9, 0xc5, 0xfe, 0x70, 0xa0, 0x5e,
-	0x5e, 0xaa, 0x67, 0xdd, 0x0a, 0x42, 0xd0, 0x4e, 0x46, 0xf5, 0x91, 0xaa, 0x0e, 0xbb, 0xd5, 0xe3,
-	0xb9, 0xe0, 0xd4, 0xb4, 0xd6, 0x68, 0x07, 0x3a, 0xcc, 0xaa, 0xaf, 0xf5, 0xc7, 0xd7, 0x5a, 0xec,
-	0xaf, 0x0b, 0xad, 0x73, 0x4d, 0xed, 0x8f, 0x55, 0x4d, 0x1f, 0x3f, 0xeb, 0x0f, 0xbb, 0x12, 0xba,
-	0x07, 0x7b, 0x59, 0x8d, 0x7e, 0xad, 0xe9, 0xea, 0x2f, 0x9f, 0xf7, 0x2f, 0xbb, 0x25, 0xb4, 0x0f,
-	0xe8, 0x52, 0x1d, 0x8d, 0x0a, 0xfa, 0x32, 0x0b, 0x27, 0xd1, 0x77, 0x37, 0x8f, 0x55, 0x68, 0x65,
-	0x93, 0x8a, 0xb6, 0x61, 0x6b, 0xd0, 0x1f, 0xab, 0xe7, 0xd7, 0xda, 0xaf, 0x62, 0xb7, 0x3b, 0xd0,
-	0x39, 0x53, 0x07, 0x17, 0xa3, 0x8b, 0xeb, 0xa1, 0x7e, 0xd5, 0xff, 0xec, 0x62, 0x78, 0xde, 0x95,
-	0xd8, 0x34, 0x57, 0xea, 0xe0, 0x59, 0x7f, 0x78, 0x31, 0x18, 0x75, 0x4b, 0xa7, 0x7f, 0xdd, 0x84,
-	0xfa, 0x65, 0x54, 0x25, 0xf4, 0x0b, 0xa8, 0x45, 0x97, 0x73, 0x94, 0xe9, 0xe6, 0xfc, 0x53, 0x42,
-	0xef, 0xde, 0x92, 0x11, 0x91, 0x76, 0x65, 0x03, 0x0d, 0xa0, 0x79, 0x83, 0xfd, 0x09, 0x76, 0xa9,
-	0x65, 0xb3, 0x8b, 0x7a, 0xfe, 0x02, 0x15, 0x73, 0x6e, 0xaf, 0xb7, 0xfa, 0x62, 0xa5, 0x6c, 0xa0,
-	0x8f, 0xa1, 0x16, 0xdd, 0x05, 0x50, 0xf1, 0x29, 0x60, 0x49, 0x10, 0x85, 0x6b, 0x83, 0xb2, 0x81,
-	0x9e, 0x42, 0x3d, 0x7e, 0x62, 0x59, 0x39, 0xc1, 0x41, 0xaa, 0xcf, 0x3d, 0xc7, 0x28, 0x1b, 0xe8,
-	0x1c, 0x3a, 0xf1, 0xf1, 0xf4, 0x2a, 0x7e, 0x70, 0xf8, 0x46, 0xeb, 0x78, 0x0a, 0x55, 0x71, 0xfc,
-	0x42, 0x19, 0x6f, 0xb9, 0x9b, 0x46, 0x4f, 0x5e, 0x1c, 0x48, 0x7e, 0x57, 0xc5, 0x35, 0x2d, 0x4e,
-	0xc5, 0x5e, 0xfe, 0xb6, 0x14, 0xcf, 0xf0, 0x60, 0xed, 0x25, 0x4a, 0xd9, 0x40, 0x67, 0xd0, 0xca,
-	0x3e, 0x3a, 0xad, 0x9a, 0xe7, 0xfe, 0x9a, 0x37, 0x2a, 0x65, 0xe3, 0xf4, 0xdf, 0x25, 0xde, 0x27,
-	0x7c, 0x3b, 0x45, 0x9f, 0x42, 0x33, 0x73, 0xf2, 0x43, 0x87, 0x05, 0x9c, 0x2f, 0x1c, 0x0a, 0xb3,
-	0xd9, 0xce, 0x01, 0x5a, 0xd9, 0x40, 0x57, 0xd0, 0xca, 0x9e, 0x9c, 0xd0, 0xbb, 0xcb, 0x26, 0xcb,
-	0x9d, 0xaa, 0x72, 0x49, 0xcb, 0xe1, 0x5e, 0xd9, 0x40, 0x1f, 0x41, 0xf9, 0x1c, 0x53, 0x54, 0x20,
-	0xe3, 0xf4, 0xd0, 0x93, 0xed, 0x9c, 0xc2, 0x59, 0x42, 0x04, 0x93, 0x3d, 0x22, 0x14, 0x83, 0x59,
-	0x72, 0x7c, 0x58, 0x1b, 0x4c, 0x1f, 0xaa, 0x62, 0x4b, 0x47, 0xf7, 0xf3, 0x13, 0xe5, 0x36, 0xfa,
-	0x75, 0x53, 0xbc, 0xac, 0xf2, 0x1d, 0xf4, 0xc7, 0xff, 0x0d, 0x00, 0x00, 0xff, 0xff, 0xb1, 0x58,
-	0x34, 0xb8, 0xa7, 0x17, 0x00, 0x00,
+	// 2097 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xbc, 0x58, 0x4b, 0x73, 0x24, 0x47,
+	0x11, 0x56, 0xcf, 0x7b, 0x72, 0x46, 0x33, 0xa3, 0xd2, 0xab, 0x35, 0xcb, 0x86, 0xe5, 0x5e, 0x1b,
+	0x14, 0x32, 0x68, 0x37, 0x04, 0x01, 0x8b, 0xc3, 0xeb, 0x60, 0x3c, 0xea, 0xd0, 0xca, 0x96, 0x46,
+	0xa2, 0x67, 0xd6, 0x1b, 0x40, 0x40, 0x47, 0xef, 0x74, 0x49, 0xea, 0x70, 0xbf, 0xdc, 0x5d, 0x2d,
+	0xef, 0xf0, 0x0f, 0xb8, 0x70, 0xe1, 0xc4, 0x81, 0x9b, 0x0f, 0x70, 0xe0, 0x47, 0xf0, 0x2f, 0xf8,
+	0x07, 0x5c, 0xb8, 0x72, 0xe3, 0x40, 0x54, 0x55, 0x3f, 0xaa, 0x7b, 0x1e, 0xbb, 0xd8, 0xeb, 0xbd,
+	0x75, 0x66, 0x56, 0x57, 0x65, 0x65, 0xe6, 0xf7, 0x55, 0x56, 0x41, 0xc7, 0xf6, 0xec, 0x90, 0x18,
+	0x24, 0x3c, 0xf2, 0x03, 0x8f, 0x78, 0xa8, 0x91, 0xc8, 0xfd, 0x77, 0x6e, 0x3c, 0xef, 0xc6, 0xc6,
+	0x0f, 0x99, 0xfe, 0x45, 0x74, 0xfd, 0x90, 0x58, 0x0e, 0x0e, 0x89, 0xe1, 0xf8, 0x7c, 0xa8, 0xf2,
+	0x75, 0x15, 0xda, 0x17, 0x06, 0x99, 0xde, 0x8e, 0x23, 0xc7, 0x31, 0x82, 0x19, 0x7a, 0x07, 0x5a,
+	0x61, 0xe4, 0x38, 0x9e, 0x8b, 0x03, 0xdd, 0x32, 0x65, 0x69, 0x5f, 0x3a, 0x28, 0x6b, 0x90, 0xa8,
+	0xce, 0x4c, 0xd4, 0x87, 0x86, 0x6f, 0x1b, 0xe4, 0xda, 0x0b, 0x1c, 0xb9, 0xb4, 0x2f, 0x1d, 0x34,
+	0xb5, 0x54, 0x46, 0xef, 0x43, 0xc7, 0xa1, 0x93, 0xe9, 0xd3, 0x00, 0x1b, 0xc4, 0xf2, 0x5c, 0xb9,
+	0xc2, 0xfe, 0x5f, 0x67, 0xda, 0x61, 0xac, 0xa4, 0x6b, 0x4c, 0x6f, 0x0d, 0xc7, 0xb7, 0x3c, 0x97,
+	0xae, 0x51, 0xdd, 0x97, 0x0e, 0xaa, 0x1a, 0x24, 0xaa, 0x33, 0x13, 0x21, 0xa8, 0xd8, 0x86, 0x8b,
+	0xe5, 0x1a, 0x9b, 0x9f, 0x7d, 0xd3, 0xb9, 0xb3, 0x9f, 0x5c, 0x13, 0xbf, 0x94, 0xeb, 0xec, 0xbf,
+	0xf5, 0xf4, 0x3f, 0xaa, 0x44, 0xdb, 0x50, 0x73, 0x0c, 0x9f, 0x4e, 0xdb, 0x60, 0xe6, 0xaa, 0x63,
+	0xf8, 0x67, 0x66, 0xe6, 0x99, 0x19, 0x05, 0xdc, 0xb3, 0xe6, 0xbe, 0x74, 0x20, 0xc5, 0x9e, 0x9d,
+	0xc4, 0x4a, 0xb4, 0x07, 0x0d, 0x3e, 0xcc, 0x32, 0x65, 0x60, 0xae, 0xd7, 0x99, 0x7c, 0x66, 0xa2,
+	0xfb, 0x00, 0xdc, 0xe4, 0x78, 0x26, 0x96, 0x5b, 0xcc, 0xb3, 0x26, 0xd3, 0x5c, 0x78, 0x26, 0xce,
+	0xcc, 0x64, 0xe6, 0x63, 0xb9, 0x2d, 0x98, 0x27, 0x33, 0x1f, 0xa3, 0x07, 0xc0, 0x57, 0xd2, 0xef,
+	0x70, 0x10, 0xd2, 0xe5, 0xd7, 0xd9, 0x88, 0x36, 0x53, 0x7e, 0xce, 0x75, 0xe8, 0x11, 0x6c, 0x79,
+	0xbe, 0xef, 0xb9, 0xd8, 0x25, 0xba, 0x18, 0xa0, 0x2e, 0xdb, 0x09, 0x4a, 0x6c, 0xc3, 0x2c, 0x50,
+	0xf7, 0x01, 0xbe, 0x8c, 0x70, 0x84, 0xf9, 0xaa, 0x3d, 0xbe, 0x2a, 0xd3, 0xb0, 0x55, 0x11, 0x54,
+	0x02, 0xcf, 0xc6, 0xf2, 0x06, 0x8f, 0x23, 0xfd, 0xa6, 0x9e, 0xa4, 0x09, 0x76, 0x0d, 0x07, 0xcb,
+	0x88, 0x7b, 0x92, 0x28, 0x47, 0x86, 0x83, 0xd1, 0x0e, 0xd4, 0xbe, 0xb2, 0x5c, 0x17, 0x07, 0xf2,
+	0xe6, 0xbe, 0x74, 0xd0, 0xd0, 0x62, 0x89, 0x4e, 0x48, 0x2c, 0x1c, 0xc8, 0x5b, 0x7c, 0x42, 0xfa,
+	0x4d, 0x0b, 0xc2, 0xb4, 0xee, 0x2c, 0xb6, 0xab, 0x6d, 0x5e, 0x10, 0x89, 0x8c, 0xb6, 0xa0, 0xfa,
+	0x85, 0x65, 0xdb, 0xa1, 0xbc, 0xc3, 0x82, 0xc9, 0x05, 0x3a, 0xbb, 0x89, 0x0d, 0x72, 0x1b, 0xca,
+	0xbb, 0x4c, 0x1d, 0x4b, 0x48, 0x86, 0xba, 0x11, 0x86, 0x56, 0x48, 0x42, 0x59, 0xe6, 0xc1, 0x8f,
+	0x45, 0xe5, 0x10, 0xe0, 0x13, 0x23, 0xb4, 0xa6, 0x63, 0x5a, 0xd5, 0xe8, 0x7b, 0xd0, 0x64, 0xe5,
+	0xfd, 0x69, 0xe8, 0xb9, 0xac, 0x42, 0x9b, 0x5a, 0xa6, 0x50, 0x7e, 0x04, 0xeb, 0x03, 0xf3, 0xce,
+	0x70, 0xa7, 0xd8, 0x7c, 0x9d, 0xe1, 0x7f, 0x92, 0x60, 0x63, 0x82, 0x0d, 0x27, 0xff, 0xcf, 0x27,
+	0x50, 0x0f, 0x70, 0x18, 0xd9, 0x24, 0x94, 0xa5, 0xfd, 0xf2, 0x41, 0xeb, 0xf8, 0xe0, 0x28, 0x05,
+	0xd9, 0xdc, 0xe8, 0x23, 0x8d, 0x0f, 0x55, 0x5d, 0x12, 0xcc, 0xb4, 0xe4, 0xc7, 0xfe, 0x87, 0xd0,
+	0x16, 0x0d, 0xa8, 0x07, 0xe5, 0x2f, 0xf0, 0x2c, 0x86, 0x14, 0xfd, 0xa4, 0xe1, 0xb9, 0x33, 0xec,
+	0x08, 0xc7, 0x40, 0xe2, 0xc2, 0x87, 0xa5, 0xc7, 0x92, 0xf2, 0x67, 0x09, 0x3a, 0x4f, 0xad, 0x90,
+	0x78, 0xc1, 0x4c, 0xc3, 0x5f, 0x46, 0x38, 0x24, 0xdf, 0x0e, 0x99, 0x5b, 0x50, 0xb5, 0x2d, 0xc7,
+	0x22, 0x72, 0x99, 0xa3, 0x82, 0x09, 0x34, 0x11, 0xd3, 0x28, 0x08, 0xbd, 0x80, 0x61, 0xb0, 0xa9,
+	0xc5, 0x12, 0x8d, 0x58, 0x5a, 0x44, 0x31, 0x08, 0x33, 0x85, 0xf2, 0x1b, 0xe8, 0xa6, 0xae, 0x85,
+	0xbe, 0xe7, 0x86, 0x18, 0x3d, 0x02, 0x8e, 0x13, 0x9c, 0x84, 0x6b, 0x27, 0x0b, 0x97, 0x48, 0x2f,
+	0x5a, 0x32, 0x4c, 0x58, 0xba, 0x24, 0x2e, 0xad, 0xfc, 0xb1, 0x0c, 0x6d, 0x16, 0xd4, 0x64, 0xdb,
+	0x32, 0xd4, 0x43, 0x6c, 0xe3, 0x69, 0x9c, 0x89, 0xa6, 0x96, 0x88, 0x2b, 0xf7, 0x9b, 0x07, 0x46,
+	0xb9, 0x08, 0x8c, 0x77, 0xa1, 0xed, 0x33, 0x38, 0xfa, 0x01, 0xbe, 0xb6, 0x5e, 0x32, 0x9a, 0x6a,
+	0x6a, 0x2d, 0xa6, 0xbb, 0x62, 0x2a, 0x4a, 0x24, 0xb6, 0x11, 0x12, 0xdd, 0x8d, 0xf9, 0xa9, 0x4a,
+	0xa5, 0x51, 0x8a, 0x80, 0xda, 0x12, 0x04, 0xd4, 0x0b, 0x08, 0x48, 0xa8, 0xac, 0x21, 0x50, 0x59,
+	0x02, 0xcb, 0xa6, 0x00, 0xcb, 0x02, 0x27, 0xc2, 0x1c, 0x27, 0x2e, 0x23, 0x87, 0xd6, 0x52, 0x72,
+	0x28, 0x14, 0x4c, 0x7b, 0xae, 0x60, 0x1e, 0xc0, 0x3a, 0x5d, 0x5b, 0xf7, 0xbd, 0xd0, 0x22, 0x02,
+	0x29, 0x51, 0xe5, 0x55, 0xac, 0x53, 0xfe, 0x2d, 0x41, 0x8f, 0x26, 0xe4, 0xdc, 0x0a, 0x49, 0x9a,
+	0xef, 0x8f, 0xa1, 0xc6, 0xab, 0x3c, 0x4e, 0xf7, 0xf7, 0xb3, 0x74, 0x17, 0xc7, 0xc6, 0xe0, 0xe0,
+	0xd8, 0x88, 0xff, 0xa2, 0xe5, 0x38, 0xf5, 0x22, 0x97, 0xb0, 0xbc, 0x95, 0x35, 0x2e, 0xf4, 0x15,
+	0x68, 0x24, 0x7f, 0xd3, 0xfa, 0x60, 0x68, 0xe0, 0x59, 0x97, 0xb4, 0x58, 0xea, 0xff, 0x0e, 0x5a,
+	0xc2, 0x84, 0x22, 0xa6, 0x9a, 0x1c, 0x53, 0x3f, 0x17, 0x31, 0xd5, 0x3a, 0x7e, 0xb0, 0xc2, 0xb3,
+	0x54, 0x21, 0x00, 0xef, 0x3a, 0x3e, 0x0f, 0xdf, 0x08, 0xea, 0xc4, 0xe3, 0xa4, 0x9c, 0x3b, 0x4e,
+	0x94, 0x27, 0xd0, 0x3d, 0xc1, 0xc4, 0xb0, 0xec, 0x30, 0x0d, 0xea, 0x21, 0x54, 0x5f, 0x50, 0x92,
+	0x63, 0x8b, 0xb4, 0x8e, 0xb7, 0x32, 0xcf, 0x33, 0xee, 0xd3, 0xf8, 0x10, 0xe5, 0xb7, 0xd0, 0xa2,
+	0x34, 0x94, 0x78, 0xb9, 0x0b, 0x75, 0x82, 0x0d, 0x27, 0xf3, 0xb0, 0x46, 0xc5, 0x6f, 0xee, 0xdd,
+	0xdf, 0x25, 0xd8, 0xa4, 0xf3, 0x17, 0x5d, 0x3c, 0x29, 0xd2, 0xe2, 0x61, 0x9e, 0x16, 0x0b, 0xe3,
+	0x97, 0x10, 0xe3, 0xd5, 0x2b, 0x89, 0xf1, 0x30, 0x9f, 0xc4, 0x25, 0xa1, 0xc8, 0xb2, 0xf6, 0xdf,
+	0x12, 0xac, 0x8f, 0xb1, 0x11, 0x64, 0x79, 0x13, 0x37, 0x2e, 0xad, 0x24, 0x87, 0xd2, 0xab, 0xc8,
+	0xa1, 0xbc, 0x8a, 0x1c, 0x78, 0x83, 0x13, 0x93, 0xc3, 0x16, 0x54, 0x29, 0x21, 0x84, 0x72, 0x95,
+	0x31, 0x15, 0x17, 0xe6, 0x61, 0x56, 0x9b, 0x87, 0x59, 0x11, 0xff, 0x75, 0x5e, 0x67, 0xaf, 0x81,
+	0xff, 0x06, 0x1b, 0xb9, 0x08, 0xff, 0x7b, 0xd0, 0x20, 0x9e, 0xaf, 0xd3, 0xb8, 0xc5, 0x54, 0x53,
+	0x27, 0x9e, 0x4f, 0x63, 0x47, 0x23, 0x70, 0xe3, 0x79, 0xa6, 0xce, 0x0f, 0x67, 0x60, 0xde, 0x36,
+	0xa9, 0xe6, 0x33, 0xaa, 0x40, 0x47, 0xb0, 0x89, 0x5f, 0x4e, 0xed, 0xc8, 0xc4, 0xba, 0x58, 0xfc,
+	0x2d, 0xb6, 0xd4, 0x46, 0x6c, 0x1a, 0xa7, 0x18, 0x50, 0xfe, 0x59, 0xa2, 0xa8, 0xf4, 0x6d, 0x63,
+	0xc6, 0x13, 0x2a, 0x56, 0x96, 0x94, 0x6f, 0xa3, 0x56, 0x15, 0xe4, 0xb2, 0x2d, 0x96, 0x97, 0x6e,
+	0xf1, 0x11, 0x6c, 0xdf, 0x78, 0xb6, 0xa9, 0x9b, 0xd6, 0xf5, 0xb5, 0xfe, 0x7b, 0x1c, 0x78, 0x3a,
+	0xf1, 0x74, 0x82, 0x79, 0x5a, 0x24, 0x6d, 0x83, 0x1a, 0x4f, 0xac, 0xeb, 0xeb, 0x5f, 0xe3, 0xc0,
+	0x9b, 0x78, 0x13, 0xec, 0xa2, 0x61, 0x6e, 0xe7, 0x55, 0x56, 0xc4, 0xef, 0x65, 0xe5, 0x25, 0xec,
+	0xe2, 0xe8, 0x34, 0x89, 0x07, 0x13, 0xc5, 0xf8, 0x2c, 0x3a, 0x04, 0x76, 0xa1, 0x3e, 0x0d, 0x99,
+	0x23, 0x2c, 0x79, 0x92, 0x56, 0x9b, 0x86, 0x74, 0xe9, 0xfe, 0x47, 0xd0, 0xc9, 0xcf, 0xb4, 0x80,
+	0xb4, 0x72, 0x8d, 0x40, 0x59, 0xac, 0xec, 0xe7, 0xd0, 0x49, 0x0a, 0x3b, 0xc6, 0xe0, 0xc3, 0x22,
+	0x06, 0xb7, 0x17, 0xba, 0x9f, 0xc2, 0x6d, 0x31, 0xd9, 0x2a, 0x7f, 0xa5, 0x1d, 0x06, 0x36, 0x88,
+	0x63, 0xf8, 0x6f, 0x84, 0xeb, 0x32, 0x48, 0x94, 0xc5, 0xf3, 0x72, 0xae, 0xf8, 0x2b, 0x0b, 0x8a,
+	0x3f, 0x0f, 0xc8, 0x6a, 0xb1, 0xe1, 0x38, 0x83, 0x6e, 0xea, 0x69, 0x1c, 0x84, 0x7d, 0x68, 0xdd,
+	0x72, 0x95, 0xd0, 0xd5, 0x89, 0xaa, 0x25, 0xbb, 0xfe, 0x43, 0x09, 0x76, 0x4e, 0x3d, 0xc3, 0x66,
+	0x77, 0x11, 0x9c, 0x6b, 0x34, 0xbe, 0xd5, 0xee, 0xe7, 0xb6, 0x59, 0x5e, 0xb0, 0xcd, 0x7b, 0xd0,
+	0x74, 0x23, 0x47, 0xbf, 0xf1, 0x0c, 0x3b, 0x8c, 0x89, 0xa3, 0xe1, 0x46, 0x0e, 0xf5, 0x27, 0x44,
+	0x8f, 0x41, 0x26, 0x46, 0x70, 0x83, 0x89, 0x6e, 0x4c, 0x6f, 0x2d, 0x7c, 0x87, 0x1d, 0x06, 0x03,
+	0xb6, 0x85, 0x2a, 0x1b, 0xbb, 0xc3, 0xed, 0x83, 0xcc, 0x3c, 0xa4, 0x56, 0xf4, 0x53, 0x80, 0xa9,
+	0x41, 0xf0, 0x8d, 0x17, 0x58, 0x38, 0x94, 0x6b, 0xfb, 0xe5, 0x83, 0x8e, 0xd8, 0x7f, 0xb1, 0xed,
+	0x72, 0xfb, 0x4c, 0x13, 0x46, 0x2a, 0x5f, 0x4b, 0xb0, 0x9b, 0xc5, 0x62, 0x18, 0x85, 0xc4, 0x73,
+	0xde, 0x5e, 0x30, 0x7e, 0x00, 0xdd, 0xc8, 0x35, 0x71, 0x60, 0xcf, 0x2c, 0xf7, 0x86, 0x93, 0x14,
+	0x2f, 0x8d, 0x4e, 0xa6, 0xa6, 0xf9, 0x51, 0xfe, 0x21, 0x51, 0x00, 0x19, 0xf6, 0x29, 0x26, 0x6f,
+	0xcf, 0xbb, 0x02, 0x1d, 0x57, 0xe6, 0xe8, 0xf8, 0x87, 0x50, 0xa3, 0x3e, 0x47, 0x21, 0x4b, 0x4e,
+	0x47, 0x3c, 0xa2, 0xa8, 0xb3, 0x63, 0x66, 0xd3, 0xe2, 0x31, 0xca, 0xcf, 0xa0, 0x9b, 0x6e, 0x21,
+	0xae, 0xe0, 0xf7, 0xa0, 0xca, 0x0b, 0x81, 0x83, 0xb8, 0x93, 0xff, 0x5f, 0xe3, 0x46, 0xe5, 0x2f,
+	0x71, 0x8e, 0x9e, 0xf9, 0x66, 0x5c, 0xaf, 0xd1, 0x9b, 0x29, 0xd8, 0x5d, 0xa8, 0xd3, 0x15, 0x12,
+	0x7a, 0x6d, 0x6a, 0x35, 0x2a, 0xe6, 0x36, 0x56, 0x79, 0x8d, 0x8d, 0x59, 0xb0, 0x41, 0xb5, 0x27,
+	0xd8, 0xc6, 0x04, 0x7f, 0xa7, 0x8e, 0x29, 0x7f, 0xab, 0x42, 0x85, 0xae, 0x85, 0x3a, 0x50, 0x8a,
+	0x67, 0x6d, 0x6a, 0x25, 0xcb, 0x44, 0x3f, 0x81, 0x3a, 0x7b, 0x6f, 0xc0, 0x66, 0xdc, 0x2e, 0xf4,
+	0x8f, 0xf8, 0xb3, 0xc7, 0x51, 0xf2, 0xec, 0x71, 0x34, 0x49, 0x9e, 0x3d, 0xb4, 0x64, 0x28, 0x7a,
+	0x02, 0x6d, 0xc6, 0x57, 0x11, 0x8b, 0x2c, 0x5f, 0x6c, 0xf5, 0xaf, 0x2d, 0x3a, 0x9e, 0x27, 0xe2,
+	0xff, 0x0c, 0x53, 0x31, 0x22, 0xd5, 0x95, 0x11, 0xa9, 0x15, 0x22, 0xb2, 0x00, 0x29, 0xf5, 0x45,
+	0x48, 0xa1, 0x8d, 0x4b, 0x4c, 0x21, 0xfc, 0x30, 0x69, 0xb0, 0x73, 0xa8, 0xc5, 0x75, 0x9f, 0x53,
+	0x15, 0x7a, 0x0c, 0x30, 0xf5, 0x1c, 0xdf, 0x08, 0x0c, 0xe2, 0x05, 0xac, 0x2b, 0xe8, 0x1c, 0xcb,
+	0x05, 0xae, 0x48, 0xed, 0x9a, 0x30, 0x16, 0x7d, 0x00, 0x1b, 0xf3, 0xc4, 0xc4, 0xdf, 0x48, 0x7a,
+	0x46, 0x91, 0x92, 0x56, 0x91, 0x59, 0x6b, 0x25, 0x99, 0x7d, 0x00, 0x1b, 0x96, 0xe3, 0x7b, 0x01,
+	0xa1, 0xd7, 0x6b, 0xfd, 0x2b, 0x6c, 0xdd, 0xdc, 0x12, 0x76, 0x75, 0x91, 0xb4, 0x5e, 0x66, 0x78,
+	0xce, 0xf4, 0xf3, 0x50, 0xee, 0xbc, 0x1a, 0xca, 0xdd, 0x39, 0x28, 0x1f, 0x43, 0x23, 0x66, 0xc5,
+	0x19, 0x7b, 0x42, 0x59, 0xce, 0x9e, 0xe9, 0x38, 0x7a, 0x62, 0xb1, 0xea, 0xe1, 0x81, 0xde, 0x60,
+	0xfe, 0x35, 0xa9, 0x86, 0x85, 0x59, 0x79, 0x1f, 0xd6, 0xd9, 0x76, 0x52, 0xb4, 0xa7, 0xa7, 0x91,
+	0x24, 0x9e, 0x46, 0x3d, 0xe8, 0x8c, 0x2d, 0xc7, 0xb7, 0x71, 0x32, 0xee, 0xf0, 0x1a, 0x20, 0x2b,
+	0x1f, 0xd4, 0x85, 0xd6, 0x78, 0x32, 0x98, 0x3c, 0x1b, 0xeb, 0xa3, 0xcb, 0x91, 0xda, 0x5b, 0x43,
+	0x75, 0x28, 0x8f, 0xd4, 0xe7, 0x3d, 0x09, 0xb5, 0xa1, 0x31, 0x18, 0x0e, 0xd5, 0xab, 0x89, 0x7a,
+	0xd2, 0x2b, 0xa1, 0x75, 0x68, 0x0e, 0x2f, 0x2f, 0xae, 0xce, 0x55, 0x2a, 0x56, 0x98, 0x38, 0x18,
+	0x0d, 0xd5, 0xf3, 0x73, 0xf5, 0xa4, 0x57, 0x45, 0x08, 0x3a, 0xa9, 0x55, 0x1f, 0xab, 0xea, 0xa8,
+	0x57, 0x3b, 0x9c, 0x41, 0x27, 0x9f, 0x6b, 0xb4, 0x09, 0x5d, 0x3a, 0x6a, 0xa0, 0x0d, 0x26, 0x97,
+	0x5a, 0xb2, 0x5e, 0x0f, 0xda, 0xa7, 0x9a, 0x3a, 0x98, 0xa8, 0x9a, 0x3e, 0x79, 0x3a, 0x18, 0xf5,
+	0x24, 0xb4, 0x07, 0xdb, 0xa2, 0x46, 0xbf, 0xd4, 0x74, 0xf5, 0x97, 0xcf, 0x06, 0xe7, 0xbd, 0x12,
+	0xda, 0x01, 0x74, 0xae, 0x8e, 0xc7, 0x05, 0x7d, 0x99, 0xba, 0x93, 0xea, 0x7b, 0x95, 0x43, 0x15,
+	0xda, 0x62, 0x50, 0xd1, 0x06, 0xac, 0x0f, 0x07, 0x13, 0xf5, 0xf4, 0x52, 0xfb, 0x55, 0xb2, 0xec,
+	0x26, 0x74, 0x4f, 0xd4, 0xe1, 0xd9, 0xf8, 0xec, 0x72, 0xa4, 0x5f, 0x0c, 0x3e, 0x3b, 0x1b, 0x9d,
+	0xf6, 0x24, 0x3a, 0xcd, 0x85, 0x3a, 0x7c, 0x3a, 0x18, 0x9d, 0x0d, 0xc7, 0xbd, 0xd2, 0xf1, 0xbf,
+	0x2a, 0xd0, 0x38, 0x8f, 0xb3, 0x84, 0x7e, 0x01, 0xf5, 0xf8, 0x49, 0x02, 0x09, 0xd5, 0x9c, 0x7f,
+	0x40, 0xe9, 0xef, 0x2d, 0xb0, 0xf0, 0xb0, 0x2b, 0x6b, 0x68, 0x08, 0xad, 0x2b, 0x1c, 0x4c, 0xb1,
+	0x4b, 0x2c, 0x9b, 0x3e, 0x4f, 0xe4, 0xaf, 0x8d, 0x09, 0xe7, 0xf6, 0xfb, 0xcb, 0xaf, 0x93, 0xca,
+	0x1a, 0xfa, 0x18, 0xea, 0xf1, 0x0d, 0x08, 0x15, 0x1f, 0x40, 0x16, 0x38, 0x51, 0xb8, 0x2c, 0x29,
+	0x6b, 0xe8, 0x09, 0x34, 0x92, 0x87, 0xa5, 0xa5, 0x13, 0xec, 0x66, 0xfa, 0xdc, 0x23, 0x94, 0xb2,
+	0x86, 0x4e, 0xa1, 0x9b, 0x34, 0xe5, 0x17, 0xc9, 0x33, 0xcb, 0x37, 0xda, 0xc7, 0x13, 0xa8, 0xf1,
+	0xa6, 0x13, 0x09, 0xab, 0xe5, 0xee, 0x57, 0x7d, 0x79, 0xde, 0x90, 0xfe, 0xae, 0xf2, 0xcb, 0x69,
+	0x12, 0x8a, 0xed, 0xfc, 0x1d, 0x31, 0x99, 0xe1, 0xfe, 0xca, 0xab, 0xa3, 0xb2, 0x86, 0x4e, 0xa0,
+	0x2d, 0x3e, 0xb5, 0x2d, 0x9b, 0xe7, 0xde, 0x8a, 0x97, 0x39, 0x65, 0x8d, 0x95, 0x06, 0x6f, 0x0b,
+	0x73, 0xa5, 0x91, 0xeb, 0x7c, 0xfb, 0x7b, 0x0b, 0x2c, 0x89, 0x1f, 0xc7, 0xff, 0x29, 0xb1, 0x4a,
+	0x63, 0x07, 0x32, 0xfa, 0x14, 0x5a, 0x42, 0xef, 0x88, 0xf6, 0x0b, 0x4c, 0x31, 0xd7, 0x56, 0x8a,
+	0xf9, 0xca, 0x51, 0x82, 0xb2, 0x86, 0x2e, 0xa0, 0x2d, 0xf6, 0x5e, 0xe8, 0xdd, 0x45, 0x93, 0xe5,
+	0xfa, 0xb2, 0x5c, 0xd8, 0x73, 0xcc, 0xa1, 0xac, 0xa1, 0x8f, 0xa0, 0x7c, 0x8a, 0x09, 0x2a, 0xd0,
+	0x79, 0xd6, 0x36, 0xf5, 0xf7, 0x16, 0x58, 0x44, 0x67, 0xc4, 0x26, 0xa3, 0xe8, 0xcc, 0x82, 0x06,
+	0x64, 0xa5, 0x33, 0x03, 0xa8, 0xf1, 0xa6, 0x00, 0xdd, 0xcb, 0x4f, 0x94, 0x6b, 0x15, 0x56, 0x4d,
+	0xf1, 0xa2, 0xc6, 0xce, 0xe0, 0x1f, 0xff, 0x6f, 0x00, 0x33, 0x90, 0xd9, 0x46, 0xdf, 0x18, 0x00,
+	0x00,
 }
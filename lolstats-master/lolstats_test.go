@@ -111,3 +111,29 @@ func TestMatchRequestValid(t *testing.T) {
 		t.Error("Unexpected error for valid request")
 	}
 }
+
+func TestHeatmapRequestValid(t *testing.T) {
+	r := &HeatmapRequest{}
+	if err := r.Valid(); err == nil {
+		t.Error("Expected error for empty request")
+	}
+
+	r = &HeatmapRequest{
+		SummonerId: 123,
+		Platform:   "NA1",
+	}
+	if err := r.Valid(); err == nil {
+		t.Error("Expected error for no last_n")
+	}
+
+	r.LastN = 20
+	if err := r.Valid(); err != nil {
+		t.Error("Unexpected error for valid request")
+	}
+
+	r.RolePosition = "mid"
+	expected := `basic.summoner_id = 123 AND basic.platform_id = "NA1" AND role_position = "mid"`
+	if where := r.BuildWhereClause(); where != expected {
+		t.Errorf("expected %s, got %s", expected, where)
+	}
+}
@@ -482,6 +482,69 @@ func (s *StatsServer) Search(ctx context.Context, in *lolstats.SearchRequest) (o
 	return out, nil
 }
 
+func (s *StatsServer) Heatmap(ctx context.Context, in *lolstats.HeatmapRequest) (*lolstats.HeatmapResponse, error) {
+	if err := in.Valid(); err != nil {
+		return nil, err
+	}
+
+	claims, err := s.validateCtx(ctx, privileges.LOLstatsRead)
+	if err != nil {
+		return nil, err
+	}
+
+	pId := api.ParticipantIdentity{Player: api.Player{SummonerID: in.SummonerId}}
+	if err = s.validateSummonerAccess(ctx, claims, []api.ParticipantIdentity{pId}, in.Platform); err != nil {
+		return nil, err
+	}
+
+	if err := checkInvalidCharacters([]string{in.Platform, in.QueueType, in.RolePosition}); err != nil {
+		return nil, err
+	}
+
+	// Heatmaps are written by the advanced stats ingester, so only matches with advanced stats have them
+	queryString := fmt.Sprintf("SELECT basic.match_id, basic.platform_id FROM `%s` basic INNER JOIN `%s` advanced ON basic.summoner_id = advanced.summoner_id AND basic.platform_id = advanced.platform_id AND basic.match_id = advanced.match_id WHERE %s ORDER BY match_creation DESC LIMIT %d", s.BasicTable, s.AdvancedTable, in.BuildWhereClause(), in.LastN)
+
+	results, err := DoQuery(ctx, s.BQClient, queryString)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	// Fetch the heatmaps of each match from gcs, and merge them
+	var merged *ingest.Heatmaps
+	for _, row := range results {
+		filename := fmt.Sprintf("%s/%d-%s-%d.heatmap.json", s.StatsDir, row["match_id"].(int64), row["platform_id"].(string), in.SummonerId)
+		data, err := s.FilesClient.Read(filename)
+		if err != nil {
+			// Matches ingested before heatmaps existed don't have them, so just skip them
+			log.Warning(fmt.Sprintf("Unable to fetch heatmap %v: %v", filename, err))
+			continue
+		}
+
+		heatmaps := &ingest.Heatmaps{}
+		if err = json.Unmarshal(data, heatmaps); err != nil {
+			return nil, err
+		}
+		if merged == nil {
+			merged = heatmaps
+		} else if err = merged.Merge(heatmaps); err != nil {
+			// e.g. a match on another map
+			log.Warning(fmt.Sprintf("Unable to merge heatmap %v: %v", filename, err))
+		}
+	}
+
+	if merged == nil {
+		return nil, fmt.Errorf("No heatmaps found for summoner %v", in.SummonerId)
+	}
+
+	mergedStr, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lolstats.HeatmapResponse{HeatmapJson: string(mergedStr), Count: int64(merged.Matches)}, nil
+}
+
 func (s *StatsServer) validateCtx(ctx context.Context, claims string) (*users.Claims, error) {
 	// Don't validate tokens in dev
 	if s.ProjectID == "vs-dev" {
@@ -75,6 +75,19 @@ func (r *SearchRequest) Valid() error {
 	return nil
 }
 
+func (r *HeatmapRequest) Valid() error {
+	if r.SummonerId == 0 {
+		return fmt.Errorf("no summoner_id specified")
+	}
+	if r.Platform == "" {
+		return fmt.Errorf("no platform specified")
+	}
+	if r.LastN <= 0 {
+		return fmt.Errorf("must specify last_n")
+	}
+	return nil
+}
+
 func (r *GoalCreateStatsRequest) Valid() error {
 	if r.SummonerId == 0 || r.Platform == "" || r.RolePosition == "" || r.NumGoals == 0 || r.TargetAchievementCount == 0 || len(r.Categories) == 0 {
 		return fmt.Errorf("must specify summoner_id, platform, role_position, num_goals, target_achievement_count, at least 1 value in categories")
@@ -228,6 +241,18 @@ func GetMetaRole(lane, role string) baseview.RolePosition {
 	return ""
 }
 
+func (r *HeatmapRequest) BuildWhereClause() string {
+	whereClause := fmt.Sprintf(`basic.summoner_id = %d AND basic.platform_id = "%s"`, r.SummonerId, r.Platform)
+	if r.QueueType != "" {
+		whereClause += fmt.Sprintf(` AND basic.queue_type = "%s"`, r.QueueType)
+	}
+	if r.RolePosition != "" {
+		whereClause += fmt.Sprintf(` AND role_position = "%s"`, r.RolePosition)
+	}
+
+	return whereClause
+}
+
 func Round(f float64) int64 {
 	return int64(math.Floor(f + .5))
 }